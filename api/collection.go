@@ -0,0 +1,203 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func POSTCollection(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CollectionReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用创建/编辑/删除合集处理
+	resp, err := service.Collection(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCollectionInaccessible {
+			utility.Logger().Warnf("Collection warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Collection err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETCollectionList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CollectionListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取用户合集列表
+	resp, err := service.CollectionList(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("CollectionList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("CollectionList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("CollectionList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCollectionItem(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CollectionItemReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用添加/移除合集视频处理
+	resp, err := service.CollectionItem(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCollectionInaccessible || err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("CollectionItem warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CollectionItem err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCollectionMove(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CollectionMoveReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用调整合集视频顺序处理
+	resp, err := service.CollectionMove(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCollectionInaccessible {
+			utility.Logger().Warnf("CollectionMove warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CollectionMove err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETCollectionVideoList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CollectionVideoListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取合集视频列表
+	resp, err := service.CollectionVideoList(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCollectionInaccessible || err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("CollectionVideoList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("CollectionVideoList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("CollectionVideoList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -28,9 +28,12 @@ func POSTComment(ctx *gin.Context) {
 	resp, err := service.Comment(ctx, req)
 	if err != nil {
 		var httpCode int
-		if err == service.ErrorCommentInaccessible {
+		if err == service.ErrorCommentInaccessible || err == service.ErrorVideoInaccessible || err == service.ErrorCommentRestricted || err == service.ErrorUserBlocked {
 			utility.Logger().Warnf("Comment warn: %v", err)
 			httpCode = http.StatusForbidden
+		} else if err == service.ErrorSensitiveContent {
+			utility.Logger().Warnf("Comment warn: %v", err)
+			httpCode = http.StatusBadRequest
 		} else {
 			utility.Logger().Errorf("Comment err: %v", err)
 			httpCode = http.StatusInternalServerError
@@ -77,3 +80,188 @@ func GETCommentList(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func GETCommentReplyList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentReplyListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取回复列表
+	resp, err := service.CommentReplyList(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCommentInaccessible {
+			utility.Logger().Warnf("CommentReplyList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CommentReplyList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCommentLike(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentLikeReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用评论点赞/取消赞处理
+	resp, err := service.CommentLike(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCommentInaccessible {
+			utility.Logger().Warnf("CommentLike warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CommentLike err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCommentPin(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentPinReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用置顶/取消置顶评论处理
+	resp, err := service.CommentPin(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCommentInaccessible || err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("CommentPin warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CommentPin err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCommentPolicy(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentPolicyReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "设置失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用设置评论权限
+	resp, err := service.CommentPolicy(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("CommentPolicy warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CommentPolicy err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "设置失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 设置成功
+	status := response.Status{Status_Code: 0, Status_Msg: "设置成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTCommentEdit(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentEditReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "编辑失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用编辑评论
+	resp, err := service.CommentEdit(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCommentInaccessible || err == service.ErrorCommentEditExpired {
+			utility.Logger().Warnf("CommentEdit warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("CommentEdit err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "编辑失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 编辑成功
+	status := response.Status{Status_Code: 0, Status_Msg: "编辑成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func POSTDraft(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.DraftReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用创建/编辑/删除草稿处理
+	resp, err := service.Draft(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorDraftInaccessible {
+			utility.Logger().Warnf("Draft warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorSensitiveContent || err == service.ErrorImageTooLarge {
+			utility.Logger().Warnf("Draft warn: %v", err)
+			httpCode = http.StatusBadRequest
+		} else {
+			utility.Logger().Errorf("Draft err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETDraftList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.DraftListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取草稿列表
+	resp, err := service.DraftList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("DraftList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTDraftPublish(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.DraftPublishReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "发布失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用发布草稿处理
+	resp, err := service.DraftPublish(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorDraftInaccessible {
+			utility.Logger().Warnf("DraftPublish warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("DraftPublish err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "发布失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 发布成功
+	status := response.Status{Status_Code: 0, Status_Msg: "发布成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -27,8 +27,15 @@ func POSTFavorite(ctx *gin.Context) {
 	// 调用赞/取消赞处理
 	resp, err := service.Favorite(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("Favorite err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("Favorite warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Favorite err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "操作失败: " + err.Error(),
 		})
@@ -57,8 +64,18 @@ func GETFavoriteList(ctx *gin.Context) {
 	// 调用获取喜欢列表
 	resp, err := service.FavoriteList(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("FavoriteList err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("FavoriteList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("FavoriteList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("FavoriteList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
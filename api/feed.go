@@ -55,3 +55,40 @@ func GETFeed(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func POSTWatch(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.WatchReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用记录已看视频处理
+	resp, err := service.Watch(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("Watch warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Watch err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -28,8 +28,21 @@ func POSTMessage(ctx *gin.Context) {
 	// 调用消息发送处理
 	resp, err := service.Message(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("Message err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorSensitiveContent || err == service.ErrorMessageInvalid || err == service.ErrorImageTooLarge {
+			utility.Logger().Warnf("Message warn: %v", err)
+			httpCode = http.StatusBadRequest
+		} else if err == service.ErrorUserBlocked {
+			utility.Logger().Warnf("Message warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorShareInaccessible {
+			utility.Logger().Warnf("Message warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("Message err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "操作失败: " + err.Error(),
 		})
@@ -68,8 +81,15 @@ func GETMessageList(ctx *gin.Context) {
 	// 调用获取消息记录
 	resp, err := service.MessageList(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("MessageList err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserBlocked {
+			utility.Logger().Warnf("MessageList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("MessageList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
@@ -81,3 +101,195 @@ func GETMessageList(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func POSTMessageAck(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.MessageAckReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "确认失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用消息确认服务
+	resp, err := service.MessageAck(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorMessageInaccessible {
+			utility.Logger().Warnf("MessageAck warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("MessageAck err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "确认失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 确认成功
+	status := response.Status{Status_Code: 0, Status_Msg: "确认成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTMessageDelete(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.MessageDeleteReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "删除失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用消息撤回/删除服务
+	resp, err := service.MessageDelete(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorMessageInaccessible {
+			utility.Logger().Warnf("MessageDelete warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else if err == service.ErrorMessageRecallExpired {
+			utility.Logger().Warnf("MessageDelete warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("MessageDelete err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "删除失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 删除成功
+	status := response.Status{Status_Code: 0, Status_Msg: "删除成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETConversationList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ConversationListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取会话列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用会话列表服务
+	resp, err := service.ConversationList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("ConversationList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取会话列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取会话列表成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取会话列表成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTConversationRead(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ConversationReadReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "标记已读失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用会话已读服务
+	resp, err := service.ConversationRead(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("ConversationRead err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "标记已读失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 标记已读成功
+	status := response.Status{Status_Code: 0, Status_Msg: "标记已读成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETUnreadCount(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.UnreadCountReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取未读数失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用未读消息数服务
+	resp, err := service.UnreadCount(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("UnreadCount err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取未读数失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取未读数成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取未读数成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETChatStream(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ChatStreamReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "连接失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用聊天实时连接服务(协议升级后由服务接管连接 直至连接关闭)
+	err = service.ChatStream(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("ChatStream err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "连接失败: " + err.Error(),
+		})
+		return
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GETNotificationList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.NotificationListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取通知列表
+	resp, err := service.NotificationList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("NotificationList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTNotificationRead(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.NotificationReadReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用标记通知为已读
+	resp, err := service.NotificationRead(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("NotificationRead err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -27,8 +27,15 @@ func POSTPublish(ctx *gin.Context) {
 	// 调用投稿处理
 	resp, err := service.Publish(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("Publish err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorSensitiveContent {
+			utility.Logger().Warnf("Publish warn: %v", err)
+			httpCode = http.StatusBadRequest
+		} else {
+			utility.Logger().Errorf("Publish err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "发布失败: " + err.Error(),
 		})
@@ -57,8 +64,18 @@ func GETPublishList(ctx *gin.Context) {
 	// 调用获取发布列表
 	resp, err := service.PublishList(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("PublishList err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("PublishList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("PublishList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("PublishList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
@@ -70,3 +87,40 @@ func GETPublishList(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func POSTPublishDelete(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.PublishDeleteReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "删除视频失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用删除视频服务
+	resp, err := service.PublishDelete(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("PublishDelete warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("PublishDelete err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "删除视频失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 删除视频成功
+	status := response.Status{Status_Code: 0, Status_Msg: "删除视频成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
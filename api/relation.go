@@ -27,8 +27,15 @@ func POSTFollow(ctx *gin.Context) {
 	// 调用关注/取消关注处理
 	resp, err := service.Follow(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("Follow err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserBlocked {
+			utility.Logger().Warnf("Follow warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Follow err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "操作失败: " + err.Error(),
 		})
@@ -41,6 +48,43 @@ func POSTFollow(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, resp)
 }
 
+func POSTRemoveFollower(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.RemoveFollowerReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "移除粉丝失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用移除粉丝服务
+	resp, err := service.RemoveFollower(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorNotFollower {
+			utility.Logger().Warnf("RemoveFollower warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("RemoveFollower err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "移除粉丝失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 移除粉丝成功
+	status := response.Status{Status_Code: 0, Status_Msg: "移除粉丝成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
 func GETFollowList(ctx *gin.Context) {
 	// 绑定JSON到结构体
 	req := &request.FollowListReq{}
@@ -57,8 +101,18 @@ func GETFollowList(ctx *gin.Context) {
 	// 调用获取关注列表
 	resp, err := service.FollowList(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("FollowList err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("FollowList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("FollowList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("FollowList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
@@ -87,8 +141,18 @@ func GETFollowerList(ctx *gin.Context) {
 	// 调用获取粉丝列表
 	resp, err := service.FollowerList(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("FollowerList err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("FollowerList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("FollowerList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("FollowerList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
@@ -141,3 +205,160 @@ func GETFriendList(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func POSTBlock(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.BlockReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用拉黑/取消拉黑处理
+	resp, err := service.Block(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("Block err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETBlockList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.BlockListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取拉黑列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用拉黑列表服务
+	resp, err := service.BlockList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("BlockList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取拉黑列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取拉黑列表成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取拉黑列表成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETFollowRequestList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.FollowRequestListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取关注申请列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用关注申请列表服务
+	resp, err := service.FollowRequestList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("FollowRequestList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取关注申请列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取关注申请列表成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取关注申请列表成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTFollowRequestAction(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.FollowRequestActionReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "处理关注申请失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用处理关注申请服务
+	resp, err := service.FollowRequestAction(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorFollowRequestInaccessible {
+			utility.Logger().Warnf("FollowRequestAction warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("FollowRequestAction err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "处理关注申请失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 处理关注申请成功
+	status := response.Status{Status_Code: 0, Status_Msg: "处理关注申请成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETRecommendList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.RecommendListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取推荐列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用好友推荐列表服务
+	resp, err := service.RecommendList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("RecommendList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取推荐列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取推荐列表成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取推荐列表成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func POSTReport(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ReportReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "举报失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用举报内容处理
+	resp, err := service.Report(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorReportTargetInaccessible {
+			utility.Logger().Warnf("Report warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Report err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "举报失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 举报成功
+	status := response.Status{Status_Code: 0, Status_Msg: "举报成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETModerationList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ModerationListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取举报列表
+	resp, err := service.ModerationList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("ModerationList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTModeration(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ModerationReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用处理/驳回举报处理
+	resp, err := service.Moderation(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("Moderation err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETCommentRevisionList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.CommentRevisionListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用获取评论修订记录
+	resp, err := service.CommentRevisionList(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorCommentInaccessible {
+			utility.Logger().Warnf("CommentRevisionList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("CommentRevisionList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
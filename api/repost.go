@@ -0,0 +1,136 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func POSTRepost(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.RepostReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用转发/取消转发处理
+	resp, err := service.Repost(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorVideoInaccessible {
+			utility.Logger().Warnf("Repost warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else {
+			utility.Logger().Errorf("Repost err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETRepostList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.RepostListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 处理可选参数
+	// latest_time字段
+	if req.Latest_Time == 0 { // 不存在时该字段为0
+		req.Latest_Time = time.Now().Unix() // 使用当前时间
+	} else {
+		req.Latest_Time = req.Latest_Time / 1000 // 请求为毫秒时间戳 故在此转换
+	}
+
+	// 调用获取用户转发列表
+	resp, err := service.RepostList(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorUserPrivate {
+			utility.Logger().Warnf("RepostList warn: %v", err)
+			httpCode = http.StatusForbidden
+		} else if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("RepostList warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("RepostList err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETFollowingFeed(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.FollowingFeedReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 处理可选参数
+	// latest_time字段
+	if req.Latest_Time == 0 { // 不存在时该字段为0
+		req.Latest_Time = time.Now().Unix() // 使用当前时间
+	} else {
+		req.Latest_Time = req.Latest_Time / 1000 // 请求为毫秒时间戳 故在此转换
+	}
+
+	// 调用获取关注动态流
+	resp, err := service.FollowingFeed(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("FollowingFeed err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
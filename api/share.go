@@ -0,0 +1,90 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GETShareLink(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ShareLinkReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用生成分享短链接
+	resp, err := service.ShareLink(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorLinkInvalid {
+			utility.Logger().Warnf("ShareLink warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("ShareLink err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func GETShareResolve(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.ShareResolveReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "解析失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用解析分享短链接
+	resp, err := service.ShareResolve(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorLinkInvalid {
+			utility.Logger().Warnf("ShareResolve warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("ShareResolve err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "解析失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 解析成功
+	if req.Redirect {
+		ctx.Redirect(http.StatusFound, resp.Redirect_URL)
+		return
+	}
+	status := response.Status{Status_Code: 0, Status_Msg: "解析成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
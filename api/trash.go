@@ -0,0 +1,82 @@
+package api
+
+import (
+	"douyin/service"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GETTrashList(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.TrashListReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取回收站列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用回收站列表服务
+	resp, err := service.TrashList(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("TrashList err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "获取回收站列表失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 获取回收站列表成功
+	status := response.Status{Status_Code: 0, Status_Msg: "获取回收站列表成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTTrashRestore(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.TrashRestoreReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "恢复失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用恢复服务
+	resp, err := service.TrashRestore(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorTrashInaccessible {
+			utility.Logger().Warnf("TrashRestore warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else if err == service.ErrorTrashConflict {
+			utility.Logger().Warnf("TrashRestore warn: %v", err)
+			httpCode = http.StatusConflict
+		} else {
+			utility.Logger().Errorf("TrashRestore err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "恢复失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 恢复成功
+	status := response.Status{Status_Code: 0, Status_Msg: "恢复成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -112,8 +112,15 @@ func GETUserInfo(ctx *gin.Context) {
 	// 调用获取用户信息
 	resp, err := service.UserInfo(ctx, req)
 	if err != nil {
-		utility.Logger().Errorf("UserInfo err: %v", err)
-		ctx.JSON(http.StatusInternalServerError, &response.Status{
+		var httpCode int
+		if err == service.ErrorUserNotExists {
+			utility.Logger().Warnf("UserInfo warn: %v", err)
+			httpCode = http.StatusNotFound
+		} else {
+			utility.Logger().Errorf("UserInfo err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
 			Status_Code: -1,
 			Status_Msg:  "获取失败: " + err.Error(),
 		})
@@ -125,3 +132,70 @@ func GETUserInfo(ctx *gin.Context) {
 	resp.Status = status
 	ctx.JSON(http.StatusOK, resp)
 }
+
+func POSTUserSignature(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.UserSignatureReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用编辑个人签名处理
+	resp, err := service.UserSignature(ctx, req)
+	if err != nil {
+		var httpCode int
+		if err == service.ErrorSensitiveContent {
+			utility.Logger().Warnf("UserSignature warn: %v", err)
+			httpCode = http.StatusBadRequest
+		} else {
+			utility.Logger().Errorf("UserSignature err: %v", err)
+			httpCode = http.StatusInternalServerError
+		}
+		ctx.JSON(httpCode, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "操作失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 操作成功
+	status := response.Status{Status_Code: 0, Status_Msg: "操作成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func POSTUserPrivacy(ctx *gin.Context) {
+	// 绑定JSON到结构体
+	req := &request.UserPrivacyReq{}
+	err := ctx.ShouldBind(req)
+	if err != nil {
+		utility.Logger().Errorf("ShouldBind err: %v", err)
+		ctx.JSON(http.StatusBadRequest, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "设置失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 调用设置账号隐私服务
+	resp, err := service.UserPrivacy(ctx, req)
+	if err != nil {
+		utility.Logger().Errorf("UserPrivacy err: %v", err)
+		ctx.JSON(http.StatusInternalServerError, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "设置失败: " + err.Error(),
+		})
+		return
+	}
+
+	// 设置成功
+	status := response.Status{Status_Code: 0, Status_Msg: "设置成功"}
+	resp.Status = status
+	ctx.JSON(http.StatusOK, resp)
+}
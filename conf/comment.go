@@ -0,0 +1,5 @@
+package conf
+
+type Comment struct {
+	EditWindow int `yaml:"editWindow"`
+}
@@ -1,6 +1,7 @@
 package conf
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,12 +10,34 @@ import (
 )
 
 type Config struct {
-	System *System `yaml:"system"`
-	MySQL  *MySQL  `yaml:"mysql"`
-	OSS    *OSS    `yaml:"oss"`
-	Redis  *Redis  `yaml:"redis"`
-	Cache  *Cache  `yaml:"cache"`
-	Log    *Log    `yaml:"log"`
+	System     *System     `yaml:"system"`
+	MySQL      *MySQL      `yaml:"mysql"`
+	OSS        *OSS        `yaml:"oss"`
+	Redis      *Redis      `yaml:"redis"`
+	Cache      *Cache      `yaml:"cache"`
+	Feed       *Feed       `yaml:"feed"`
+	Comment    *Comment    `yaml:"comment"`
+	Message    *Message    `yaml:"message"`
+	Trash      *Trash      `yaml:"trash"`
+	Recommend  *Recommend  `yaml:"recommend"`
+	Moderation *Moderation `yaml:"moderation"`
+	Filter     *Filter     `yaml:"filter"`
+	Share      *Share      `yaml:"share"`
+	Log        *Log        `yaml:"log"`
+}
+
+// 配置项缺省值(兼容不含新增配置项的旧配置文件 与config.yaml.example一致)
+var defaults = map[string]any{
+	"feed.seenWindow":            72,
+	"comment.editWindow":         15,
+	"message.recallWindow":       2,
+	"trash.retention":            30,
+	"trash.purgeInterval":        60,
+	"recommend.interval":         360,
+	"recommend.size":             50,
+	"moderation.reportThreshold": 5,
+	"filter.wordList":            "none", // 未配置词表时不过滤
+	"filter.reloadInterval":      60,
 }
 
 var _cfg *Config
@@ -29,6 +52,9 @@ func InitConfig() {
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath(workDir)
 	viper.AddConfigPath(workDir + "/conf/locale")
+	for key, value := range defaults {
+		viper.SetDefault(key, value)
+	}
 	err := viper.ReadInConfig()
 	if err != nil {
 		panic(err)
@@ -38,7 +64,15 @@ func InitConfig() {
 		panic(err)
 	}
 
+	// 必填项检查
+	if _cfg.Share == nil || _cfg.Share.LinkKey == "" { // 短链接置换密钥不可使用公开的默认值
+		panic(errors.New("未配置share.linkKey"))
+	}
+
 	// 特殊值替换
+	if _cfg.Filter.WordList == "" { // 未配置词表时不过滤
+		_cfg.Filter.WordList = "none"
+	}
 	if strings.ToLower(_cfg.System.TempDir) == "system" { // 若使用系统默认临时文件夹
 		_cfg.System.TempDir = filepath.Join(os.TempDir(), "douyin")
 	}
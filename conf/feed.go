@@ -0,0 +1,5 @@
+package conf
+
+type Feed struct {
+	SeenWindow int `yaml:"seenWindow"`
+}
@@ -0,0 +1,6 @@
+package conf
+
+type Filter struct {
+	WordList       string `yaml:"wordList"`
+	ReloadInterval int    `yaml:"reloadInterval"`
+}
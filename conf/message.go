@@ -0,0 +1,5 @@
+package conf
+
+type Message struct {
+	RecallWindow int `yaml:"recallWindow"`
+}
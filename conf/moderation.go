@@ -0,0 +1,6 @@
+package conf
+
+type Moderation struct {
+	ReportThreshold int    `yaml:"reportThreshold"`
+	Moderators      []uint `yaml:"moderators"`
+}
@@ -0,0 +1,6 @@
+package conf
+
+type Recommend struct {
+	Interval int `yaml:"interval"`
+	Size     int `yaml:"size"`
+}
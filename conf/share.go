@@ -0,0 +1,5 @@
+package conf
+
+type Share struct {
+	LinkKey string `yaml:"linkKey"`
+}
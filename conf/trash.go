@@ -0,0 +1,6 @@
+package conf
+
+type Trash struct {
+	Retention     int `yaml:"retention"`
+	PurgeInterval int `yaml:"purgeInterval"`
+}
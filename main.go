@@ -4,6 +4,7 @@ import (
 	"douyin/conf"
 	"douyin/repo"
 	"douyin/router"
+	"douyin/service"
 	"douyin/utility"
 
 	"context"
@@ -20,14 +21,16 @@ func init() {
 	// 读取配置并初始化公共日志记录器
 	conf.InitConfig()
 	utility.InitLogger()
+	utility.InitFilter()
 
 	// 设定Gin模式(GORM的日志记录模式将与Gin模式相同)
 	if strings.ToLower(conf.Cfg().Log.Level) != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// 初始化存储层
+	// 初始化存储层及服务层
 	repo.Init()
+	service.Init()
 }
 
 func main() {
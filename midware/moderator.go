@@ -0,0 +1,34 @@
+package midware
+
+import (
+	"douyin/conf"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gin中间件
+// 审核员鉴权 需置于jwt鉴权中间件(强制)之后
+func MiddlewareModerator() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req_id, ok := ctx.Get("req_id")
+		if ok {
+			for _, moderatorID := range conf.Cfg().Moderation.Moderators {
+				if moderatorID == req_id.(uint) {
+					ctx.Next()
+					return
+				}
+			}
+		}
+
+		utility.Logger().Warnf("MiddlewareModerator warn: 非审核员请求")
+		ctx.JSON(http.StatusForbidden, &response.Status{
+			Status_Code: -1,
+			Status_Msg:  "需要审核员权限",
+		})
+		ctx.Abort()
+	}
+}
@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+	"douyin/repo/internal/redis"
+
+	"context"
+)
+
+// 创建拉黑关系(并解除双方之间的关注关系与关注申请)
+func CreateUserBlocks(ctx context.Context, id uint, blockID uint) (err error) {
+	err = db.CreateUserBlocks(ctx, id, blockID)
+	if err != nil {
+		return err
+	}
+	err = redis.SetUserBlocksBit(ctx, id, blockID, true) // 拉黑关系采用直写策略
+	if err != nil {
+		_ = redis.DelUserBlocks(ctx, id, blockID) // 使缓存失效 以便下次读取时从数据库重新载入
+		return err
+	}
+
+	// 解除双向关注 经由同步队列保证计数一致
+	if CheckUserFollows(ctx, id, blockID) {
+		_ = DeleteUserFollows(ctx, id, blockID)
+	}
+	if CheckUserFollows(ctx, blockID, id) {
+		_ = DeleteUserFollows(ctx, blockID, id)
+	}
+
+	// 撤回双向待处理的关注申请
+	_ = db.DeleteFollowRequest(ctx, id, blockID)
+	_ = db.DeleteFollowRequest(ctx, blockID, id)
+	return nil
+}
+
+// 删除拉黑关系
+func DeleteUserBlocks(ctx context.Context, id uint, blockID uint) (err error) {
+	err = db.DeleteUserBlocks(ctx, id, blockID)
+	if err != nil {
+		return err
+	}
+	err = redis.SetUserBlocksBit(ctx, id, blockID, false)
+	if err != nil {
+		_ = redis.DelUserBlocks(ctx, id, blockID) // 使缓存失效 以便下次读取时从数据库重新载入
+		return err
+	}
+	return nil
+}
+
+// 读取拉黑(用户)列表 (select: Blocks.ID) //TODO
+func ReadUserBlocks(ctx context.Context, id uint) (users []model.User, err error) {
+	return db.ReadUserBlocks(ctx, id)
+}
+
+// 从数据库完整载入用户的拉黑关系位图(isReverse为true时载入反向位图 已载入时跳过)
+func loadUserBlocks(ctx context.Context, id uint, isReverse bool) {
+	loaded, err := redis.CheckUserBlocksLoaded(ctx, id, isReverse)
+	if err != nil || loaded {
+		return
+	}
+	if isReverse {
+		userIDs, err := db.ReadUserBlockerIDs(ctx, id)
+		if err == nil {
+			_ = redis.LoadUserBlockers(ctx, id, userIDs)
+		}
+		return
+	}
+	users, err := db.ReadUserBlocks(ctx, id)
+	if err == nil {
+		blockIDs := make([]uint, 0, len(users))
+		for _, user := range users {
+			blockIDs = append(blockIDs, user.ID)
+		}
+		_ = redis.LoadUserBlocks(ctx, id, blockIDs)
+	}
+}
+
+// 检查拉黑关系(缓存与数据库均无法确认时视为已拉黑)
+func CheckUserBlocks(ctx context.Context, id uint, blockID uint) (isBlocking bool) {
+	isBlocking, err := redis.GetUserBlocks(ctx, id, blockID, distrustProbability)
+	if err == nil { // 命中缓存
+		return isBlocking
+	}
+	record, err2 := db.CheckUserBlocks(ctx, id, blockID) // 缓存未命中或读取失败时回退至数据库
+	if err2 != nil {
+		return true
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		loadUserBlocks(ctx, id, false)
+		_ = redis.SetUserBlocksBit(ctx, id, blockID, record) // 立即修正缓存主记录
+	}
+	return record
+}
+
+// 批量检查拉黑关系(id是否拉黑了各blockID) 返回值与blockIDs一一对应 缓存与数据库均无法确认时视为已拉黑
+func CheckUserBlocksBatch(ctx context.Context, id uint, blockIDs []uint) (isBlocking []bool) {
+	isBlocking, exists, err := redis.GetUserBlocksBatch(ctx, id, blockIDs, distrustProbability)
+	if err != nil { // 读取失败时全部回退至数据库
+		isBlocking, exists = make([]bool, len(blockIDs)), make([]bool, len(blockIDs))
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range blockIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return isBlocking
+	}
+	if err == nil { // 位图可能尚未载入
+		loadUserBlocks(ctx, id, false)
+	}
+	missIDs := pickIDs(blockIDs, missIndexes)
+	records, err := db.CheckUserBlocksBatch(ctx, id, missIDs)
+	if err != nil {
+		for _, i := range missIndexes {
+			isBlocking[i] = true // 同步失败的用户对应true
+		}
+		return isBlocking
+	}
+	values := make([]bool, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]]
+		isBlocking[i] = values[j]
+	}
+	_ = redis.SetUserBlocksBitBatch(ctx, id, missIDs, values) // 立即修正缓存主记录
+	return isBlocking
+}
+
+// 批量检查反向拉黑关系(各id是否拉黑了blockID) 返回值与ids一一对应 缓存与数据库均无法确认时视为已拉黑
+func CheckUserBlockersBatch(ctx context.Context, blockID uint, ids []uint) (isBlocked []bool) {
+	isBlocked, exists, err := redis.GetUserBlockersBatch(ctx, blockID, ids, distrustProbability)
+	if err != nil { // 读取失败时全部回退至数据库
+		isBlocked, exists = make([]bool, len(ids)), make([]bool, len(ids))
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range ids {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return isBlocked
+	}
+	if err == nil { // 位图可能尚未载入
+		loadUserBlocks(ctx, blockID, true)
+	}
+	missIDs := pickIDs(ids, missIndexes)
+	records, err := db.CheckUserBlockersBatch(ctx, blockID, missIDs)
+	if err != nil {
+		for _, i := range missIndexes {
+			isBlocked[i] = true // 同步失败的用户对应true
+		}
+		return isBlocked
+	}
+	values := make([]bool, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]]
+		isBlocked[i] = values[j]
+	}
+	_ = redis.SetUserBlockersBitBatch(ctx, blockID, missIDs, values) // 立即修正缓存主记录
+	return isBlocked
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"douyin/repo/internal/redis"
+	"douyin/utility"
+
+	"context"
+	"errors"
+	"sync"
+)
+
+const chatBufferSize = 64 // 每个监听者的待推送事件缓冲数 缓冲已满时丢弃事件(客户端可回退至轮询)
+
+// 聊天事件分发中心(每个实例一个 经由Redis发布订阅接收全部实例发布的事件 再分发给本实例的监听者)
+type ChatHub struct {
+	subscriber *redis.ChatSubscriber
+	listeners  map[uint]map[chan string]struct{} // 用户ID -> 监听者集合
+	closed     bool
+	lock       *sync.Mutex
+}
+
+func (h *ChatHub) Init() {
+	h.subscriber = redis.NewChatSubscriber(context.Background())
+	h.listeners = make(map[uint]map[chan string]struct{})
+	h.lock = &sync.Mutex{}
+	go h.dispatch()
+}
+
+// 将收到的事件分发给对应用户的全部监听者
+func (h *ChatHub) dispatch() {
+	for event := range h.subscriber.Events() {
+		h.lock.Lock()
+		for listener := range h.listeners[event.UserID] {
+			select {
+			case listener <- event.Payload:
+			default: // 监听者处理过慢时丢弃事件 不阻塞其他监听者
+			}
+		}
+		h.lock.Unlock()
+	}
+}
+
+// 添加监听者 用户在本实例的首个监听者加入时订阅其频道
+func (h *ChatHub) Listen(ctx context.Context, userID uint) (listener chan string, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.closed {
+		return nil, errors.New("聊天事件分发中心已关闭")
+	}
+	if len(h.listeners[userID]) == 0 {
+		err = h.subscriber.Subscribe(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		h.listeners[userID] = make(map[chan string]struct{})
+	}
+	listener = make(chan string, chatBufferSize)
+	h.listeners[userID][listener] = struct{}{}
+	return listener, nil
+}
+
+// 移除监听者 用户在本实例的最后一个监听者离开时取消订阅其频道
+func (h *ChatHub) Unlisten(ctx context.Context, userID uint, listener chan string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if _, ok := h.listeners[userID][listener]; !ok { // 已被移除(如分发中心已关闭)
+		return
+	}
+	delete(h.listeners[userID], listener)
+	close(listener)
+	if len(h.listeners[userID]) == 0 {
+		delete(h.listeners, userID)
+		_ = h.subscriber.Unsubscribe(ctx, userID)
+	}
+}
+
+// 关闭分发中心 并关闭全部监听者以结束对应连接
+func (h *ChatHub) Close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.closed = true
+	for userID, listeners := range h.listeners {
+		for listener := range listeners {
+			close(listener)
+		}
+		delete(h.listeners, userID)
+	}
+	err := h.subscriber.Close()
+	if err != nil {
+		utility.Logger().Errorf("repo.ChatHub.Close err: %v", err)
+	}
+}
+
+var chatHub = &ChatHub{} // 聊天事件分发中心
+
+// 发布聊天事件(推送给目标用户在所有实例上的连接)
+func PublishChatEvent(ctx context.Context, userID uint, payload string) (err error) {
+	return redis.PublishUserChat(ctx, userID, payload)
+}
+
+// 监听用户的聊天事件 返回事件通道(分发中心关闭时随之关闭)及取消监听函数
+func ListenChatEvents(ctx context.Context, userID uint) (events <-chan string, cancel func(), err error) {
+	listener, err := chatHub.Listen(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listener, func() { chatHub.Unlisten(context.TODO(), userID, listener) }, nil
+}
@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+
+	"context"
+)
+
+// 创建合集 //TODO
+func CreateCollection(ctx context.Context, ownerID uint, name string, kind int, isPublic bool) (collection *model.Collection, err error) {
+	return db.CreateCollection(ctx, ownerID, name, kind, isPublic)
+}
+
+// 编辑合集 (updates为空时不做修改) //TODO
+func UpdateCollection(ctx context.Context, id uint, updates map[string]any) (err error) {
+	return db.UpdateCollection(ctx, id, updates)
+}
+
+// 删除合集 (合集条目将被一并永久删除) //TODO
+func DeleteCollection(ctx context.Context, id uint, permanently bool) (err error) {
+	return db.DeleteCollection(ctx, id, permanently)
+}
+
+// 读取合集基本信息 (select: ID, CreatedAt, UpdatedAt, Name, OwnerID, Kind, IsPublic, ItemsCount) //TODO
+func ReadCollectionBasics(ctx context.Context, id uint) (collection *model.Collection, err error) {
+	return db.ReadCollectionBasics(ctx, id)
+}
+
+// 读取用户合集列表 (select: ID, CreatedAt, UpdatedAt, Name, OwnerID, Kind, IsPublic, ItemsCount) //TODO
+func ReadUserCollections(ctx context.Context, ownerID uint, includePrivate bool) (collections []model.Collection, err error) {
+	return db.ReadUserCollections(ctx, ownerID, includePrivate)
+}
+
+// 向合集末尾添加视频 //TODO
+func CreateCollectionItem(ctx context.Context, collectionID uint, videoID uint) (err error) {
+	return db.CreateCollectionItem(ctx, collectionID, videoID)
+}
+
+// 从合集中移除视频 //TODO
+func DeleteCollectionItem(ctx context.Context, collectionID uint, videoID uint) (err error) {
+	return db.DeleteCollectionItem(ctx, collectionID, videoID)
+}
+
+// 将合集中的视频移动至指定位置(从0开始 超出范围时移至末尾) //TODO
+func MoveCollectionItem(ctx context.Context, collectionID uint, videoID uint, index int) (err error) {
+	return db.MoveCollectionItem(ctx, collectionID, videoID, index)
+}
+
+// 按顺序分页读取合集中的视频 (select: VideoID) //TODO
+func ReadCollectionItems(ctx context.Context, collectionID uint, offset int, num int) (items []model.CollectionItem, err error) {
+	return db.ReadCollectionItems(ctx, collectionID, offset, num)
+}
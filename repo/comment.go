@@ -6,46 +6,131 @@ import (
 	"douyin/repo/internal/redis"
 
 	"context"
+	"math"
 	"time"
 )
 
+const commentHotHalfLife = time.Hour * 12 // 评论热度的半衰期 即晚发布该时长的评论只需一半的互动量即可取得相同热度
+
+var commentHotEpoch = time.Unix(1577808000, 0) // 评论热度的时间基准(2020-01-01) 保证热度为正数
+
 // 获取评论主键最大值
 func MaxCommentID(ctx context.Context) (id uint, err error) {
 	return redis.GetCommentMaxID(ctx)
 }
 
-// 创建评论
-func CreateComment(ctx context.Context, authorID uint, videoID uint, content string) (comment *model.Comment, err error) {
-	comment, err = db.CreateComment(ctx, authorID, videoID, content)
+// 创建评论 (parentID不为0时为回复)
+func CreateComment(ctx context.Context, authorID uint, videoID uint, content string, parentID uint) (comment *model.Comment, err error) {
+	comment, err = db.CreateComment(ctx, authorID, videoID, content, parentID)
 	if err != nil {
 		return nil, err
 	}
 	_ = redis.IncrCommentMaxID(ctx)
 	_ = redis.DelUserCommentsCount(ctx, authorID, maxRWTime)
 	_ = redis.DelVideoCommentsCount(ctx, videoID, maxRWTime)
+	if comment.RootID != 0 {
+		_ = redis.DelCommentRepliesCount(ctx, comment.RootID, maxRWTime)
+		updateCommentHot(ctx, comment.RootID) // 回复计入顶层评论热度
+	} else {
+		_ = redis.AddVideoComment(ctx, videoID, comment.ID, commentHotScore(comment.CreatedAt, 0, 0)) // 增量加入时间序列表与热度榜
+	}
 	return comment, nil
 }
 
-// 删除评论
+// 删除评论 其下的全部回复将被一并删除
 func DeleteComment(ctx context.Context, id uint, permanently bool) (err error) {
-	comment, err2 := ReadCommentBasics(ctx, id) // 读取基本信息以获取作者ID与视频ID (必须在删除前进行)
-	err = db.DeleteComment(ctx, id, permanently)
+	deleted, err := db.DeleteComment(ctx, id, permanently)
 	if err != nil {
 		return err
 	}
-	if err2 == nil { // 若此前成功获取到作者ID与视频ID
-		_ = redis.DelUserCommentsCount(ctx, comment.AuthorID, maxRWTime)
-		_ = redis.DelVideoCommentsCount(ctx, comment.VideoID, maxRWTime)
-	}
+	delCommentsCache(ctx, deleted)
 	return nil
 }
 
-// 根据视频ID和创建时间查找评论列表(num==-1时取消数量限制) (select: ID, CreatedAt) //TODO
-func FindCommentsByCreatedAt(ctx context.Context, videoID uint, createdAt int64, forward bool, num int) (comments []model.Comment, err error) {
-	return db.FindCommentsByCreatedAt(ctx, videoID, createdAt, forward, num)
+// 删除一组同时被删除或恢复的评论的相关缓存 其中首条为目标评论
+func delCommentsCache(ctx context.Context, comments []model.Comment) {
+	authorIDs := make(map[uint]struct{})
+	for _, comment := range comments {
+		_ = redis.DelCommentBasics(ctx, comment.ID, maxRWTime) // 基本信息中不含删除状态 须一并删除
+		if _, ok := authorIDs[comment.AuthorID]; !ok {
+			authorIDs[comment.AuthorID] = struct{}{}
+			_ = redis.DelUserCommentsCount(ctx, comment.AuthorID, maxRWTime)
+		}
+	}
+	_ = redis.DelVideoCommentsCount(ctx, comments[0].VideoID, maxRWTime)
+	if comments[0].RootID != 0 {
+		_ = redis.DelCommentRepliesCount(ctx, comments[0].RootID, maxRWTime)
+		updateCommentHot(ctx, comments[0].RootID)
+	} else {
+		_ = redis.DelVideoCommentsList(ctx, comments[0].VideoID, maxRWTime)
+		_ = redis.DelVideoCommentsHot(ctx, comments[0].VideoID, maxRWTime)
+		_ = redis.DelVideoBasics(ctx, comments[0].VideoID, maxRWTime) // 可能已取消置顶
+	}
+}
+
+// 计算评论热度 点赞与回复越多越高 发布越晚越高(对数形式 使热度无需随时间重算 互动变化时可增量更新)
+func commentHotScore(createdAt time.Time, likedCount int64, repliesCount int64) (score float64) {
+	interactions := math.Max(float64(likedCount+2*repliesCount), 0)
+	age := math.Max(createdAt.Sub(commentHotEpoch).Hours(), 0)
+	return math.Log2(interactions+1) + age/commentHotHalfLife.Hours() + 1
+}
+
+// 按当前点赞数与回复数增量更新顶层评论的热度(热度榜不存在时跳过 待重建)
+func updateCommentHot(ctx context.Context, id uint) {
+	comment, err := ReadCommentBasics(ctx, id)
+	if err != nil || comment.RootID != 0 {
+		return
+	}
+	score := commentHotScore(comment.CreatedAt, CountCommentLiked(ctx, id), CountCommentReplies(ctx, id))
+	_ = redis.UpdateVideoCommentHot(ctx, comment.VideoID, id, score)
+}
+
+// 根据数据库重建视频顶层评论的时间序列表与热度榜
+func syncVideoCommentsRank(ctx context.Context, videoID uint) (err error) {
+	comments, err := db.ReadVideoCommentRanks(ctx, videoID)
+	if err != nil {
+		return err
+	}
+	commentIDs := make([]uint, 0, len(comments))
+	scores := make([]float64, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+		scores = append(scores, commentHotScore(comment.CreatedAt, int64(comment.LikedCount), int64(comment.RepliesCount)))
+	}
+	err = redis.SetVideoCommentsList(ctx, videoID, commentIDs, cacheExpiration)
+	if err != nil {
+		return err
+	}
+	return redis.SetVideoCommentsHot(ctx, videoID, commentIDs, scores, cacheExpiration)
+}
+
+// 按时间顺序从游标(评论ID 为0时表示从头开始 不含游标本身)起向新(forward为true)或向旧分页查找顶层评论(不含隐藏评论)
+func FindVideoCommentsList(ctx context.Context, videoID uint, cursor uint, forward bool, num int) (commentIDs []uint, err error) {
+	commentIDs, err = redis.GetVideoCommentsList(ctx, videoID, cursor, forward, num)
+	if err == redis.ErrorRedisNil { // 启动同步
+		err = syncVideoCommentsRank(ctx, videoID)
+		if err != nil {
+			return nil, err
+		}
+		return redis.GetVideoCommentsList(ctx, videoID, cursor, forward, num)
+	}
+	return commentIDs, err
+}
+
+// 按热度从高到低分页查找顶层评论(不含隐藏评论)
+func FindVideoCommentsHot(ctx context.Context, videoID uint, offset int, num int) (commentIDs []uint, err error) {
+	commentIDs, err = redis.GetVideoCommentsHot(ctx, videoID, offset, num)
+	if err == redis.ErrorRedisNil { // 启动同步
+		err = syncVideoCommentsRank(ctx, videoID)
+		if err != nil {
+			return nil, err
+		}
+		return redis.GetVideoCommentsHot(ctx, videoID, offset, num)
+	}
+	return commentIDs, err
 }
 
-// 读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID)
+// 读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID, IsHidden, ParentID, RootID, IsEdited)
 func ReadCommentBasics(ctx context.Context, id uint) (comment *model.Comment, err error) {
 	comment, err = redis.GetCommentBasics(ctx, id)
 	if err == nil { // 命中缓存
@@ -76,3 +161,168 @@ func ReadCommentBasics(ctx context.Context, id uint) (comment *model.Comment, er
 		return nil, err
 	}
 }
+
+// 批量读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID, IsHidden, ParentID, RootID, IsEdited) 返回值与ids一一对应 不存在或读取失败时对应nil
+func ReadCommentBasicsBatch(ctx context.Context, ids []uint) (comments []*model.Comment, err error) {
+	comments, err = redis.GetCommentBasicsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	for i, comment := range comments {
+		if comment != nil && comment.ID == 0 {
+			emptyIndexes = append(emptyIndexes, i)
+		}
+	}
+	if len(emptyIndexes) > 0 {
+		time.Sleep(maxRWTime)
+		retried, err := redis.GetCommentBasicsBatch(ctx, pickIDs(ids, emptyIndexes)) // 重试
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range emptyIndexes {
+			comments[i] = retried[j]
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i, comment := range comments {
+		if comment == nil {
+			missIndexes = append(missIndexes, i)
+		} else if comment.ID == 0 { // 命中空对象
+			comments[i] = nil
+		}
+	}
+	if len(missIndexes) == 0 {
+		return comments, nil
+	}
+	missIDs := pickIDs(ids, missIndexes)
+	placeholders := make([]*model.Comment, len(missIDs))
+	for j := range placeholders {
+		placeholders[j] = &model.Comment{}
+	}
+	_ = redis.SetCommentBasicsBatch(ctx, missIDs, placeholders, emptyExpiration) // 防止缓存穿透与缓存击穿
+	records, err := db.ReadCommentBasicsBatch(ctx, missIDs)
+	if err != nil {
+		return comments, nil // 同步失败的评论对应nil
+	}
+	recordMap := make(map[uint]*model.Comment, len(records))
+	for k := range records {
+		recordMap[records[k].ID] = &records[k]
+	}
+	foundIDs := make([]uint, 0, len(records))
+	found := make([]*model.Comment, 0, len(records))
+	for j, i := range missIndexes {
+		if record, ok := recordMap[missIDs[j]]; ok {
+			comments[i] = record
+			foundIDs = append(foundIDs, missIDs[j])
+			found = append(found, record)
+		}
+	}
+	_ = redis.SetCommentBasicsBatch(ctx, foundIDs, found, cacheExpiration)
+	return comments, nil
+}
+
+// 按时间顺序分页查找顶层评论下的回复列表(不含隐藏回复) (select: ID, CreatedAt) //TODO
+func FindCommentReplies(ctx context.Context, rootID uint, offset int, num int) (comments []model.Comment, err error) {
+	return db.FindCommentReplies(ctx, rootID, offset, num)
+}
+
+// 读取回复数量
+func CountCommentReplies(ctx context.Context, id uint) (count int64) {
+	count, err := redis.GetCommentRepliesCount(ctx, id)
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			time.Sleep(maxRWTime)
+			count, err = redis.GetCommentRepliesCount(ctx, id) // 重试
+		} else {
+			return count
+		}
+	}
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			return 0
+		} else {
+			return count
+		}
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		_ = redis.SetCommentRepliesCount(ctx, id, -1, emptyExpiration) // 防止缓存穿透与缓存击穿
+		record := db.CountCommentReplies(ctx, id)
+		if record >= 0 {
+			_ = redis.SetCommentRepliesCount(ctx, id, record, cacheExpiration)
+			return record
+		} else {
+			return -1
+		}
+	} else {
+		return -1
+	}
+}
+
+// 批量读取回复数量 返回值与ids一一对应
+func CountCommentRepliesBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetCommentRepliesCountBatch, redis.SetCommentRepliesCountBatch, db.CountCommentRepliesBatch)
+}
+
+// 读取评论受赞数量
+func CountCommentLiked(ctx context.Context, id uint) (count int64) {
+	count, err := redis.GetCommentLikedCount(ctx, id)
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			time.Sleep(maxRWTime)
+			count, err = redis.GetCommentLikedCount(ctx, id) // 重试
+		} else {
+			return count
+		}
+	}
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			return 0
+		} else {
+			return count
+		}
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		_ = redis.SetCommentLikedCount(ctx, id, -1, emptyExpiration) // 防止缓存穿透与缓存击穿
+		record := db.CountCommentLiked(ctx, id)
+		if record >= 0 {
+			_ = redis.SetCommentLikedCount(ctx, id, record, cacheExpiration)
+			return record
+		} else {
+			return -1
+		}
+	} else {
+		return -1
+	}
+}
+
+// 批量读取评论受赞数量 返回值与ids一一对应
+func CountCommentLikedBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetCommentLikedCountBatch, redis.SetCommentLikedCountBatch, db.CountCommentLikedBatch)
+}
+
+// 编辑评论(须在发布后的可编辑时限内)
+func UpdateComment(ctx context.Context, id uint, editorID uint, content string) (err error) {
+	comment, err := ReadCommentBasics(ctx, id) // 读取基本信息以获取发布时间
+	if err != nil {
+		return err
+	}
+	if time.Since(comment.CreatedAt) > commentEditWindow {
+		return ErrorEditWindowExpired
+	}
+	err = db.UpdateComment(ctx, id, editorID, content)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelCommentBasics(ctx, id, maxRWTime)
+	return nil
+}
+
+// 读取评论修订记录 按时间顺序 //TODO
+func ReadCommentRevisions(ctx context.Context, id uint) (revisions []model.CommentRevision, err error) {
+	return db.ReadCommentRevisions(ctx, id)
+}
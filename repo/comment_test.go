@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommentHotScore(t *testing.T) {
+	now := time.Now()
+
+	if score := commentHotScore(commentHotEpoch.Add(-time.Hour), 0, 0); score <= 0 {
+		t.Errorf("commentHotScore(before epoch) = %v, want > 0", score)
+	}
+	if commentHotScore(now, 10, 0) <= commentHotScore(now, 0, 0) {
+		t.Error("点赞应提升热度")
+	}
+	if commentHotScore(now, 0, 1) <= commentHotScore(now, 1, 0) {
+		t.Error("回复的权重应高于点赞")
+	}
+	if commentHotScore(now, 0, 0) <= commentHotScore(now.Add(-time.Hour), 0, 0) {
+		t.Error("较新的评论应有更高热度")
+	}
+	// 互动数翻倍约等于新发布一个半衰期
+	older := commentHotScore(now.Add(-commentHotHalfLife), 2*127+1, 0)
+	newer := commentHotScore(now, 127, 0)
+	if diff := older - newer; diff < -1e-9 || diff > 1e-9 {
+		t.Errorf("half-life mismatch: %v vs %v", older, newer)
+	}
+	if commentHotScore(now, -5, 0) != commentHotScore(now, 0, 0) {
+		t.Error("负的互动数应按0处理")
+	}
+}
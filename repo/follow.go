@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+
+	"context"
+)
+
+// 创建关注申请 //TODO
+func CreateFollowRequest(ctx context.Context, userID uint, followID uint) (request *model.FollowRequest, err error) {
+	return db.CreateFollowRequest(ctx, userID, followID)
+}
+
+// 撤回关注申请 //TODO
+func DeleteFollowRequest(ctx context.Context, userID uint, followID uint) (err error) {
+	return db.DeleteFollowRequest(ctx, userID, followID)
+}
+
+// 同意关注申请(并创建关注关系)
+func AcceptFollowRequest(ctx context.Context, id uint, followID uint) (request *model.FollowRequest, err error) {
+	request, err = db.TakeFollowRequest(ctx, id, followID)
+	if err != nil {
+		return nil, err
+	}
+	err = CreateUserFollows(ctx, request.UserID, followID)
+	if err != nil && err != ErrorRecordExists { // 已关注时视为成功
+		return nil, err
+	}
+	return request, nil
+}
+
+// 同意发给指定用户的全部关注申请(并创建关注关系) 返回成功同意的申请数
+func AcceptAllFollowRequests(ctx context.Context, followID uint) (count int, err error) {
+	requests, err := db.TakeAllFollowRequests(ctx, followID)
+	if err != nil {
+		return 0, err
+	}
+	for _, request := range requests {
+		err = CreateUserFollows(ctx, request.UserID, followID)
+		if err == nil || err == ErrorRecordExists { // 已关注时视为成功
+			count++
+		}
+	}
+	return count, nil
+}
+
+// 拒绝关注申请
+func DeclineFollowRequest(ctx context.Context, id uint, followID uint) (err error) {
+	_, err = db.TakeFollowRequest(ctx, id, followID)
+	return err
+}
+
+// 分页查找发给指定用户的关注申请 按申请时间倒序 //TODO
+func FindFollowRequests(ctx context.Context, followID uint, offset int, num int) (requests []model.FollowRequest, err error) {
+	return db.FindFollowRequests(ctx, followID, offset, num)
+}
@@ -7,12 +7,20 @@ import (
 	"douyin/repo/internal/redis"
 	"douyin/utility"
 
+	"context"
 	"errors"
+	"math"
 	"time"
 )
 
 // 自定义错误类型
 var ErrorEmptyObject = errors.New("对象不存在或尚不存在")
+var ErrorEditWindowExpired = errors.New("已超出可编辑时限")
+var ErrorRecallWindowExpired = errors.New("已超出可撤回时限")
+var ErrorRecordExists = db.ErrorRecordExists
+var ErrorRecordNotExists = db.ErrorRecordNotExists
+var ErrorRestoreConflict = db.ErrorRestoreConflict
+var ErrorImageTooLarge = oss.ErrorImageTooLarge
 
 var syncInterval time.Duration
 var maxRWTime time.Duration
@@ -20,6 +28,14 @@ var cacheExpiration time.Duration
 var emptyExpiration time.Duration
 var distrustProbability float32
 var urlExpiration time.Duration
+var seenWindow time.Duration
+var reportThreshold int64
+var commentEditWindow time.Duration
+var messageRecallWindow time.Duration
+var trashRetention time.Duration
+var trashPurgeInterval time.Duration
+var recommendInterval time.Duration
+var recommendSize int
 
 func Init() {
 	cacheCfg := conf.Cfg().Cache
@@ -29,6 +45,17 @@ func Init() {
 	emptyExpiration = time.Second * time.Duration(cacheCfg.EmptyExpiration).Abs()
 	distrustProbability = cacheCfg.DistrustProbability
 	urlExpiration = time.Hour*time.Duration(conf.Cfg().OSS.Expiry).Abs() - time.Minute
+	seenWindow = time.Hour * time.Duration(conf.Cfg().Feed.SeenWindow).Abs()
+	reportThreshold = int64(conf.Cfg().Moderation.ReportThreshold)
+	commentEditWindow = time.Minute * time.Duration(conf.Cfg().Comment.EditWindow).Abs()
+	messageRecallWindow = time.Minute * time.Duration(conf.Cfg().Message.RecallWindow).Abs()
+	trashRetention = time.Hour * 24 * time.Duration(conf.Cfg().Trash.Retention).Abs()
+	trashPurgeInterval = time.Minute * time.Duration(conf.Cfg().Trash.PurgeInterval).Abs()
+	recommendInterval = time.Minute * time.Duration(conf.Cfg().Recommend.Interval).Abs()
+	recommendSize = int(math.Abs(float64(conf.Cfg().Recommend.Size)))
+	if recommendSize == 0 {
+		recommendSize = defaultRecommendSize
+	}
 
 	// 初始化存储层
 	db.InitMySQL()
@@ -44,16 +71,111 @@ func Init() {
 			utility.Logger().Warnf("repo.Init warn: 数据表迁移成功")
 		}
 	}
+	err := db.MakeDataMigrate() // 一次性数据迁移 失败时下次启动重试
+	if err != nil {
+		utility.Logger().Errorf("repo.Init (MakeDataMigrate) err: %v", err)
+	}
 
 	// 初始化同步系统
 	syncQueue.Init()
 	syncCron.AddFunc("@every "+syncInterval.String(), syncTask)
 	syncCron.AddFunc("@every "+(syncInterval+time.Second).String(), updateTask) // 间隔为持久化同步+1秒, 保证互质以减小同时运行的概率
+	if trashPurgeInterval > 0 {
+		syncCron.AddFunc("@every "+trashPurgeInterval.String(), purgeTask)
+	}
+	if recommendInterval > 0 {
+		syncCron.AddFunc("@every "+recommendInterval.String(), recommendTask)
+	}
 	syncCron.Start()
+
+	// 初始化聊天事件分发中心
+	chatHub.Init()
 }
 
 func Stop() {
+	chatHub.Close() // 关闭全部聊天连接
 	syncCron.Stop()
 	utility.Logger().Warnf("repo.Stop warn: 已停止启动新任务, 正在等待现有任务结束...")
 	time.Sleep(maxRWTime) // 等待同步任务(如有)彻底结束
 }
+
+// 按下标选取ID
+func pickIDs(ids []uint, indexes []int) (picked []uint) {
+	picked = make([]uint, 0, len(indexes))
+	for _, i := range indexes {
+		picked = append(picked, ids[i])
+	}
+	return picked
+}
+
+// 批量读取计数的通用流程 返回值与ids一一对应 出错时对应计数为-1
+func countBatch(ctx context.Context, ids []uint,
+	getCache func(ctx context.Context, ids []uint) (counts []int64, exists []bool, err error),
+	setCache func(ctx context.Context, ids []uint, counts []int64, expiration time.Duration) (err error),
+	readDB func(ctx context.Context, ids []uint) (counts map[uint]int64, err error),
+) (counts []int64) {
+	counts = make([]int64, len(ids))
+	cached, exists, err := getCache(ctx, ids)
+	if err != nil {
+		for i := range counts {
+			counts[i] = -1
+		}
+		return counts
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	for i := range ids {
+		if exists[i] && cached[i] == -1 {
+			emptyIndexes = append(emptyIndexes, i)
+		} else {
+			counts[i] = cached[i]
+		}
+	}
+	if len(emptyIndexes) > 0 {
+		time.Sleep(maxRWTime)
+		retried, retryExists, err := getCache(ctx, pickIDs(ids, emptyIndexes)) // 重试
+		for j, i := range emptyIndexes {
+			if err != nil {
+				counts[i] = -1
+			} else if !retryExists[j] {
+				exists[i] = false // 需启动同步
+			} else if retried[j] == -1 { // 命中空对象
+				counts[i] = 0
+			} else {
+				counts[i] = retried[j]
+			}
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range ids {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return counts
+	}
+	missIDs := pickIDs(ids, missIndexes)
+	placeholders := make([]int64, len(missIDs))
+	for j := range placeholders {
+		placeholders[j] = -1
+	}
+	_ = setCache(ctx, missIDs, placeholders, emptyExpiration) // 防止缓存穿透与缓存击穿
+	records, err := readDB(ctx, missIDs)
+	if err != nil {
+		for _, i := range missIndexes {
+			counts[i] = -1
+		}
+		return counts
+	}
+	values := make([]int64, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]] // 未找到时为0
+		counts[i] = values[j]
+	}
+	_ = setCache(ctx, missIDs, values, cacheExpiration)
+	return counts
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 计数缓存的内存替身 记录每次写入的过期时间
+type fakeCountCache struct {
+	counts  map[uint]int64
+	expires map[uint]time.Duration
+	getErr  error
+	onGet   func(c *fakeCountCache) // 每次读取后调用 用于模拟并发写入
+}
+
+func (c *fakeCountCache) get(ctx context.Context, ids []uint) (counts []int64, exists []bool, err error) {
+	if c.getErr != nil {
+		return nil, nil, c.getErr
+	}
+	counts = make([]int64, len(ids))
+	exists = make([]bool, len(ids))
+	for i, id := range ids {
+		counts[i], exists[i] = c.counts[id]
+	}
+	if c.onGet != nil {
+		c.onGet(c)
+	}
+	return counts, exists, nil
+}
+
+func (c *fakeCountCache) set(ctx context.Context, ids []uint, counts []int64, expiration time.Duration) (err error) {
+	for i, id := range ids {
+		c.counts[id] = counts[i]
+		c.expires[id] = expiration
+	}
+	return nil
+}
+
+func newFakeCountCache(counts map[uint]int64) *fakeCountCache {
+	return &fakeCountCache{counts: counts, expires: make(map[uint]time.Duration)}
+}
+
+func setTestExpirations(t *testing.T) {
+	oldMaxRWTime, oldCache, oldEmpty := maxRWTime, cacheExpiration, emptyExpiration
+	maxRWTime, cacheExpiration, emptyExpiration = 0, time.Minute, time.Second
+	t.Cleanup(func() {
+		maxRWTime, cacheExpiration, emptyExpiration = oldMaxRWTime, oldCache, oldEmpty
+	})
+}
+
+func TestCountBatchCacheHits(t *testing.T) {
+	setTestExpirations(t)
+	cache := newFakeCountCache(map[uint]int64{1: 3, 2: 0})
+	readDB := func(ctx context.Context, ids []uint) (map[uint]int64, error) {
+		t.Errorf("readDB(%v) called on full cache hit", ids)
+		return nil, nil
+	}
+
+	counts := countBatch(context.Background(), []uint{2, 1, 2}, cache.get, cache.set, readDB)
+	if want := []int64{0, 3, 0}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("countBatch = %v, want %v", counts, want)
+	}
+}
+
+func TestCountBatchCacheMiss(t *testing.T) {
+	setTestExpirations(t)
+	cache := newFakeCountCache(map[uint]int64{1: 3})
+	var placeholders map[uint]int64
+	readDB := func(ctx context.Context, ids []uint) (map[uint]int64, error) {
+		if want := []uint{4, 5}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("readDB ids = %v, want %v", ids, want)
+		}
+		placeholders = map[uint]int64{4: cache.counts[4], 5: cache.counts[5]}
+		return map[uint]int64{4: 7}, nil // 5无记录
+	}
+
+	counts := countBatch(context.Background(), []uint{4, 1, 5}, cache.get, cache.set, readDB)
+	if want := []int64{7, 3, 0}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("countBatch = %v, want %v", counts, want)
+	}
+	if want := map[uint]int64{4: -1, 5: -1}; !reflect.DeepEqual(placeholders, want) {
+		t.Errorf("placeholders before readDB = %v, want %v", placeholders, want)
+	}
+	if cache.counts[4] != 7 || cache.counts[5] != 0 || cache.expires[4] != cacheExpiration || cache.expires[5] != cacheExpiration {
+		t.Errorf("cache after countBatch = %v %v, want 4:7 and 5:0 with cacheExpiration", cache.counts, cache.expires)
+	}
+	if _, ok := cache.expires[1]; ok {
+		t.Error("countBatch rewrote a cache hit")
+	}
+}
+
+func TestCountBatchEmptyObject(t *testing.T) {
+	setTestExpirations(t)
+	cache := newFakeCountCache(map[uint]int64{1: -1, 2: -1, 3: -1})
+	cache.onGet = func(c *fakeCountCache) { // 首次读取后其他请求完成了同步
+		c.counts[1] = 9
+		delete(c.counts, 3)
+		c.onGet = nil
+	}
+	readDB := func(ctx context.Context, ids []uint) (map[uint]int64, error) {
+		if want := []uint{3}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("readDB ids = %v, want %v", ids, want)
+		}
+		return map[uint]int64{3: 4}, nil
+	}
+
+	counts := countBatch(context.Background(), []uint{1, 2, 3}, cache.get, cache.set, readDB)
+	if want := []int64{9, 0, 4}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("countBatch = %v, want %v", counts, want)
+	}
+}
+
+func TestCountBatchErrors(t *testing.T) {
+	setTestExpirations(t)
+
+	// 读取缓存出错时全部为-1
+	cache := newFakeCountCache(map[uint]int64{1: 3})
+	cache.getErr = errors.New("redis down")
+	readDB := func(ctx context.Context, ids []uint) (map[uint]int64, error) {
+		t.Errorf("readDB(%v) called after cache error", ids)
+		return nil, nil
+	}
+	counts := countBatch(context.Background(), []uint{1, 2}, cache.get, cache.set, readDB)
+	if want := []int64{-1, -1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("countBatch(cache error) = %v, want %v", counts, want)
+	}
+
+	// 读取数据库出错时仅未命中的为-1
+	cache = newFakeCountCache(map[uint]int64{1: 3})
+	readDB = func(ctx context.Context, ids []uint) (map[uint]int64, error) {
+		return nil, errors.New("mysql down")
+	}
+	counts = countBatch(context.Background(), []uint{1, 2}, cache.get, cache.set, readDB)
+	if want := []int64{3, -1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("countBatch(db error) = %v, want %v", counts, want)
+	}
+	if cache.counts[2] != -1 || cache.expires[2] != emptyExpiration {
+		t.Errorf("cache[2] = %v (%v), want empty placeholder with emptyExpiration", cache.counts[2], cache.expires[2])
+	}
+}
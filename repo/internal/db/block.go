@@ -0,0 +1,126 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// 创建拉黑关系
+func CreateUserBlocks(ctx context.Context, id uint, blockID uint) (err error) {
+	if id == blockID {
+		return ErrorSelfBlock // 默认禁止自己拉黑自己
+	}
+
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		user := &model.User{ID: id}
+
+		var results []model.User
+		err2 := tx.Model(user).Select("id").Where("id=?", blockID).Limit(1).Association("Blocks").Find(&results)
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复创建
+			return ErrorRecordExists
+		}
+
+		return tx.Model(user).Association("Blocks").Append(&model.User{ID: blockID})
+	})
+}
+
+// 删除拉黑关系
+func DeleteUserBlocks(ctx context.Context, id uint, blockID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		user := &model.User{ID: id}
+
+		var results []model.User
+		err2 := tx.Model(user).Select("id").Where("id=?", blockID).Limit(1).Association("Blocks").Find(&results)
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许凭空删除
+			return ErrorRecordNotExists
+		}
+
+		return tx.Model(user).Association("Blocks").Delete(&model.User{ID: blockID})
+	})
+}
+
+// 读取拉黑(用户)列表 (select: Blocks.ID)
+func ReadUserBlocks(ctx context.Context, id uint) (users []model.User, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.User{ID: id}).Select("id").Association("Blocks").Find(&users)
+	if err != nil {
+		return users, err
+	}
+	return users, nil
+}
+
+// 读取拉黑了用户的用户ID列表
+func ReadUserBlockerIDs(ctx context.Context, blockID uint) (userIDs []uint, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Table("block").Where("block_id=?", blockID).Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// 检查拉黑关系
+func CheckUserBlocks(ctx context.Context, id uint, blockID uint) (isBlocking bool, err error) {
+	if id == blockID {
+		return false, nil // 默认自己不拉黑自己
+	}
+
+	DB := _db.WithContext(ctx)
+	var results []model.User
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("id=?", blockID).Limit(1).Association("Blocks").Find(&results)
+	if err != nil {
+		return false, err
+	}
+	return len(results) > 0, nil
+}
+
+// 批量检查拉黑关系(id是否拉黑了各blockID) 结果中仅包含已被拉黑的用户ID
+func CheckUserBlocksBatch(ctx context.Context, id uint, blockIDs []uint) (isBlocking map[uint]bool, err error) {
+	DB := _db.WithContext(ctx)
+	isBlocking = make(map[uint]bool, len(blockIDs))
+	if len(blockIDs) == 0 {
+		return isBlocking, nil
+	}
+	var results []model.User
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("id IN ?", blockIDs).Association("Blocks").Find(&results)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.ID != id { // 默认自己不拉黑自己
+			isBlocking[result.ID] = true
+		}
+	}
+	return isBlocking, nil
+}
+
+// 批量检查反向拉黑关系(各id是否拉黑了blockID) 结果中仅包含拉黑了blockID的用户ID
+func CheckUserBlockersBatch(ctx context.Context, blockID uint, ids []uint) (isBlocked map[uint]bool, err error) {
+	DB := _db.WithContext(ctx)
+	isBlocked = make(map[uint]bool, len(ids))
+	if len(ids) == 0 {
+		return isBlocked, nil
+	}
+	var userIDs []uint
+	err = DB.Table("block").Where("block_id=? AND user_id IN ?", blockID, ids).Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		if userID != blockID { // 默认自己不拉黑自己
+			isBlocked[userID] = true
+		}
+	}
+	return isBlocked, nil
+}
@@ -0,0 +1,221 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 锁定合集记录(SELECT ... FOR UPDATE) 使同一合集内调整视频位置的事务串行执行
+func lockCollection(tx *gorm.DB, collectionID uint) (err error) {
+	var ids []uint
+	return tx.Model(&model.Collection{}).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id=?", collectionID).Find(&ids).Error
+}
+
+// 创建合集
+func CreateCollection(ctx context.Context, ownerID uint, name string, kind int, isPublic bool) (collection *model.Collection, err error) {
+	DB := _db.WithContext(ctx)
+	collection = &model.Collection{Name: name, OwnerID: ownerID, Kind: kind, IsPublic: isPublic}
+	err = DB.Model(&model.Collection{}).Create(collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// 编辑合集 (updates为空时不做修改)
+func UpdateCollection(ctx context.Context, id uint, updates map[string]any) (err error) {
+	DB := _db.WithContext(ctx)
+	if len(updates) == 0 {
+		return nil
+	}
+	result := DB.Model(&model.Collection{}).Where("id=?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 { // 不允许凭空编辑
+		return ErrorRecordNotExists
+	}
+	return nil
+}
+
+// 删除合集 (合集条目将被一并永久删除)
+func DeleteCollection(ctx context.Context, id uint, permanently bool) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		collection := &model.Collection{ID: id}
+
+		var results []model.Collection
+		err2 := tx.Model(&model.Collection{}).Select("id").Where("id=?", id).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许凭空删除
+			return ErrorRecordNotExists
+		}
+
+		err2 = tx.Model(&model.CollectionItem{}).Where("collection_id=?", id).Delete(&model.CollectionItem{}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		if permanently {
+			err2 = tx.Model(&model.Collection{}).Unscoped().Delete(collection).Error
+		} else {
+			err2 = tx.Model(&model.Collection{}).Delete(collection).Error
+		}
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 读取合集基本信息 (select: ID, CreatedAt, UpdatedAt, Name, OwnerID, Kind, IsPublic, ItemsCount)
+func ReadCollectionBasics(ctx context.Context, id uint) (collection *model.Collection, err error) {
+	DB := _db.WithContext(ctx)
+	collection = &model.Collection{}
+	err = DB.Model(&model.Collection{}).Select("id", "created_at", "updated_at", "name", "owner_id", "kind", "is_public", "items_count").Where("id=?", id).First(collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// 读取用户合集列表 (select: ID, CreatedAt, UpdatedAt, Name, OwnerID, Kind, IsPublic, ItemsCount)
+func ReadUserCollections(ctx context.Context, ownerID uint, includePrivate bool) (collections []model.Collection, err error) {
+	DB := _db.WithContext(ctx)
+	query := DB.Model(&model.Collection{}).Select("id", "created_at", "updated_at", "name", "owner_id", "kind", "is_public", "items_count").Where("owner_id=?", ownerID)
+	if !includePrivate {
+		query = query.Where("is_public=?", true)
+	}
+	err = query.Order("created_at").Find(&collections).Error
+	if err != nil {
+		return collections, err
+	}
+	return collections, nil
+}
+
+// 向合集末尾添加视频
+func CreateCollectionItem(ctx context.Context, collectionID uint, videoID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		err2 := lockCollection(tx, collectionID) // 防止并发添加取得相同的位置
+		if err2 != nil {
+			return err2
+		}
+
+		var results []model.CollectionItem
+		err2 = tx.Model(&model.CollectionItem{}).Select("id").Where("collection_id=? AND video_id=?", collectionID, videoID).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复添加
+			return ErrorRecordExists
+		}
+
+		var maxPosition int64
+		err2 = tx.Model(&model.CollectionItem{}).Select("IFNULL(MAX(position),0)").Where("collection_id=?", collectionID).Scan(&maxPosition).Error
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(&model.CollectionItem{}).Create(&model.CollectionItem{CollectionID: collectionID, VideoID: videoID, Position: maxPosition + 1}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(&model.Collection{ID: collectionID}).Update("ItemsCount", gorm.Expr("items_count+?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 从合集中移除视频
+func DeleteCollectionItem(ctx context.Context, collectionID uint, videoID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		result := tx.Model(&model.CollectionItem{}).Where("collection_id=? AND video_id=?", collectionID, videoID).Delete(&model.CollectionItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 { // 不允许凭空删除
+			return ErrorRecordNotExists
+		}
+
+		err2 := tx.Model(&model.Collection{ID: collectionID}).Update("ItemsCount", gorm.Expr("items_count-?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 将合集中的视频移动至指定位置(从0开始 超出范围时移至末尾)
+func MoveCollectionItem(ctx context.Context, collectionID uint, videoID uint, index int) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		err2 := lockCollection(tx, collectionID) // 防止与并发添加或移动交错
+		if err2 != nil {
+			return err2
+		}
+
+		var items []model.CollectionItem
+		err2 = tx.Model(&model.CollectionItem{}).Select("id", "video_id", "position").Where("collection_id=?", collectionID).Order("position").Find(&items).Error
+		if err2 != nil {
+			return err2
+		}
+
+		from := -1
+		for i, item := range items {
+			if item.VideoID == videoID {
+				from = i
+				break
+			}
+		}
+		if from == -1 { // 不允许凭空移动
+			return ErrorRecordNotExists
+		}
+		if index >= len(items) {
+			index = len(items) - 1
+		}
+		if index == from {
+			return nil
+		}
+
+		// 重排后依次写回位置 仅更新发生变化的条目
+		moved := items[from]
+		items = append(items[:from], items[from+1:]...)
+		items = append(items[:index], append([]model.CollectionItem{moved}, items[index:]...)...)
+		for i, item := range items {
+			position := int64(i + 1)
+			if item.Position == position {
+				continue
+			}
+			err2 = tx.Model(&model.CollectionItem{ID: item.ID}).Update("position", position).Error
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		return nil
+	})
+}
+
+// 按顺序分页读取合集中的视频 (select: VideoID)
+func ReadCollectionItems(ctx context.Context, collectionID uint, offset int, num int) (items []model.CollectionItem, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.CollectionItem{}).Select("video_id").Where("collection_id=?", collectionID).Order("position").Offset(offset).Limit(num).Find(&items).Error
+	if err != nil {
+		return items, err
+	}
+	return items, nil
+}
@@ -4,7 +4,6 @@ import (
 	"douyin/repo/internal/db/model"
 
 	"context"
-	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,19 +15,41 @@ func MaxCommentID(ctx context.Context) (max uint, err error) {
 	return max, err
 }
 
-// 创建评论
-func CreateComment(ctx context.Context, authorID uint, videoID uint, content string) (comment *model.Comment, err error) {
+// 创建评论 (parentID不为0时为回复 所回复的评论须处于同一视频下)
+func CreateComment(ctx context.Context, authorID uint, videoID uint, content string, parentID uint) (comment *model.Comment, err error) {
 	DB := _db.WithContext(ctx)
 	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
-		comment = &model.Comment{Content: content, AuthorID: authorID, VideoID: videoID}
+		comment = &model.Comment{Content: content, AuthorID: authorID, VideoID: videoID, ParentID: parentID}
 		author := &model.User{ID: authorID}
 		video := &model.Video{ID: videoID}
 
+		if parentID != 0 {
+			var results []model.Comment
+			err2 := tx.Model(&model.Comment{}).Select("id", "root_id").Where("id=? AND video_id=?", parentID, videoID).Limit(1).Find(&results).Error
+			if err2 != nil {
+				return err2
+			}
+			if len(results) == 0 { // 不允许凭空回复
+				return ErrorRecordNotExists
+			}
+			comment.RootID = results[0].RootID
+			if comment.RootID == 0 { // 回复顶层评论
+				comment.RootID = results[0].ID
+			}
+		}
+
 		err2 := tx.Model(&model.Comment{}).Create(comment).Error
 		if err2 != nil {
 			return err2
 		}
 
+		if comment.RootID != 0 {
+			err2 = tx.Model(&model.Comment{ID: comment.RootID}).Update("RepliesCount", gorm.Expr("replies_count+?", 1)).Error
+			if err2 != nil {
+				return err2
+			}
+		}
+
 		err2 = tx.Model(author).Update("CommentsCount", gorm.Expr("comments_count+?", 1)).Error
 		if err2 != nil {
 			return err2
@@ -47,80 +68,192 @@ func CreateComment(ctx context.Context, authorID uint, videoID uint, content str
 	return comment, nil
 }
 
-// 删除评论
-func DeleteComment(ctx context.Context, id uint, permanently bool) (err error) {
+// 删除评论 其下的全部回复将被一并删除 (返回被删除的评论 其中首条为目标评论 select: ID, AuthorID, VideoID, RootID)
+func DeleteComment(ctx context.Context, id uint, permanently bool) (deleted []model.Comment, err error) {
 	DB := _db.WithContext(ctx)
-	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
-		comment := &model.Comment{ID: id}
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var err2 error
+		deleted, err2 = deleteComment(tx, id, permanently)
+		return err2
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
 
-		var results []model.Comment
-		err2 := tx.Model(&model.Comment{}).Select("id").Where("id=?", id).Limit(1).Find(&results).Error
-		if err2 != nil {
-			return err2
+// 在事务中删除评论及其全部回复 返回被删除的评论 其中首项为目标评论 (select: ID, AuthorID, VideoID, RootID)
+func deleteComment(tx *gorm.DB, id uint, permanently bool) (deleted []model.Comment, err error) {
+	var results []model.Comment
+	err = tx.Model(&model.Comment{}).Select("id", "author_id", "video_id", "root_id").Where("id=?", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 { // 不允许凭空删除
+		return nil, ErrorRecordNotExists
+	}
+	deleted = append(deleted, results[0])
+
+	// 逐层查找全部回复
+	frontier := []uint{id}
+	for len(frontier) > 0 {
+		var children []model.Comment
+		err = tx.Model(&model.Comment{}).Select("id", "author_id", "video_id", "root_id").Where("parent_id IN ?", frontier).Find(&children).Error
+		if err != nil {
+			return nil, err
 		}
-		if len(results) == 0 { // 不允许凭空删除
-			return ErrorRecordNotExists
+		frontier = frontier[:0]
+		for _, child := range children {
+			deleted = append(deleted, child)
+			frontier = append(frontier, child.ID)
 		}
+	}
 
-		var authorID uint
-		err2 = tx.Model(comment).Select("author_id").Scan(&authorID).Error
-		if err2 != nil {
-			return err2
-		}
-		author := &model.User{ID: authorID}
+	ids := make([]uint, 0, len(deleted))
+	authorCounts := make(map[uint]int) // 各作者被删除的评论数
+	for _, comment := range deleted {
+		ids = append(ids, comment.ID)
+		authorCounts[comment.AuthorID]++
+	}
 
-		var videoID uint
-		err2 = tx.Model(comment).Select("video_id").Scan(&videoID).Error
-		if err2 != nil {
-			return err2
-		}
-		video := &model.Video{ID: videoID}
+	if permanently {
+		err = purgeComments(tx, ids)
+	} else {
+		err = tx.Model(&model.Comment{}).Where("id IN ?", ids).Delete(&model.Comment{}).Error
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		if permanently {
-			err2 = tx.Model(&model.Comment{}).Unscoped().Delete(comment).Error
-		} else {
-			err2 = tx.Model(&model.Comment{}).Delete(comment).Error
-		}
-		if err2 != nil {
-			return err2
+	for authorID, count := range authorCounts {
+		err = tx.Model(&model.User{ID: authorID}).Update("CommentsCount", gorm.Expr("comments_count-?", count)).Error
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		err2 = tx.Model(author).Update("CommentsCount", gorm.Expr("comments_count-?", 1)).Error
-		if err2 != nil {
-			return err2
+	err = tx.Model(&model.Video{ID: deleted[0].VideoID}).Update("CommentsCount", gorm.Expr("comments_count-?", len(deleted))).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if deleted[0].RootID == 0 { // 删除顶层评论时取消其置顶
+		err = tx.Model(&model.Video{}).Where("id=? AND pinned_comment_id=?", deleted[0].VideoID, id).Update("pinned_comment_id", 0).Error
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		err2 = tx.Model(video).Update("CommentsCount", gorm.Expr("comments_count-?", 1)).Error
-		if err2 != nil {
-			return err2
+	if deleted[0].RootID != 0 { // 删除回复时更新所属顶层评论的回复数
+		err = tx.Model(&model.Comment{ID: deleted[0].RootID}).Update("RepliesCount", gorm.Expr("replies_count-?", len(deleted))).Error
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		return nil
-	})
+	return deleted, nil
 }
 
-// 根据视频ID和创建时间查找评论列表(num==-1时取消数量限制) (select: ID, CreatedAt)
-func FindCommentsByCreatedAt(ctx context.Context, videoID uint, createdAt int64, forward bool, num int) (comments []model.Comment, err error) {
+// 读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID, IsHidden, ParentID, RootID, IsEdited)
+func ReadCommentBasics(ctx context.Context, id uint) (comment *model.Comment, err error) {
 	DB := _db.WithContext(ctx)
-	stop := time.Unix(createdAt, 0)
-	if forward {
-		err = DB.Model(&model.Comment{}).Select("id", "created_at").Where("video_id=?", videoID).Where("created_at>?", stop).Order("created_at").Limit(num).Find(&comments).Error
-	} else {
-		err = DB.Model(&model.Comment{}).Select("id", "created_at").Where("video_id=?", videoID).Where("created_at<?", stop).Order("created_at desc").Limit(num).Find(&comments).Error
+	comment = &model.Comment{}
+	err = DB.Model(&model.Comment{}).Select("id", "created_at", "updated_at", "content", "author_id", "video_id", "is_hidden", "parent_id", "root_id", "is_edited").Where("id=?", id).First(comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// 批量读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID, IsHidden, ParentID, RootID, IsEdited) 未找到的评论不包含在结果中
+func ReadCommentBasicsBatch(ctx context.Context, ids []uint) (comments []model.Comment, err error) {
+	DB := _db.WithContext(ctx)
+	if len(ids) == 0 {
+		return comments, nil
 	}
+	err = DB.Model(&model.Comment{}).Select("id", "created_at", "updated_at", "content", "author_id", "video_id", "is_hidden", "parent_id", "root_id", "is_edited").Where("id IN ?", ids).Find(&comments).Error
+	return comments, err
+}
+
+// 按时间顺序分页查找顶层评论下的回复列表(不含隐藏回复) (select: ID, CreatedAt)
+func FindCommentReplies(ctx context.Context, rootID uint, offset int, num int) (comments []model.Comment, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Comment{}).Select("id", "created_at").Where("root_id=? AND is_hidden=?", rootID, false).Order("created_at").Order("id").Offset(offset).Limit(num).Find(&comments).Error
 	if err != nil {
 		return comments, err
 	}
-	return comments, err
+	return comments, nil
 }
 
-// 读取评论基本信息 (select: ID, CreatedAt, UpdatedAt, Content, AuthorID, VideoID)
-func ReadCommentBasics(ctx context.Context, id uint) (comment *model.Comment, err error) {
+// 读取回复数量
+func CountCommentReplies(ctx context.Context, id uint) (count int64) {
 	DB := _db.WithContext(ctx)
-	comment = &model.Comment{}
-	err = DB.Model(&model.Comment{}).Select("id", "created_at", "updated_at", "content", "author_id", "video_id").Where("id=?", id).First(comment).Error
+	err := DB.Model(&model.Comment{ID: id}).Select("RepliesCount").Scan(&count).Error
 	if err != nil {
-		return nil, err
+		return -1 // 出错
 	}
-	return comment, nil
+	return count
+}
+
+// 批量读取回复数量
+func CountCommentRepliesBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.Comment{}, "replies_count", ids)
+}
+
+// 读取评论受赞数量
+func CountCommentLiked(ctx context.Context, id uint) (count int64) {
+	DB := _db.WithContext(ctx)
+	err := DB.Model(&model.Comment{ID: id}).Select("LikedCount").Scan(&count).Error
+	if err != nil {
+		return -1 // 出错
+	}
+	return count
+}
+
+// 批量读取评论受赞数量
+func CountCommentLikedBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.Comment{}, "liked_count", ids)
+}
+
+// 读取视频下全部顶层评论的排序依据(不含隐藏评论) (select: ID, CreatedAt, RepliesCount, LikedCount)
+func ReadVideoCommentRanks(ctx context.Context, videoID uint) (comments []model.Comment, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Comment{}).Select("id", "created_at", "replies_count", "liked_count").Where("video_id=? AND root_id=? AND is_hidden=?", videoID, 0, false).Order("id").Find(&comments).Error
+	if err != nil {
+		return comments, err
+	}
+	return comments, nil
+}
+
+// 编辑评论 编辑前的内容存入修订记录
+func UpdateComment(ctx context.Context, id uint, editorID uint, content string) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.Comment
+		err2 := tx.Model(&model.Comment{}).Select("id", "content").Where("id=?", id).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许凭空编辑
+			return ErrorRecordNotExists
+		}
+
+		revision := &model.CommentRevision{CommentID: id, EditorID: editorID, Content: results[0].Content}
+		err2 = tx.Model(&model.CommentRevision{}).Create(revision).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Model(&model.Comment{ID: id}).Updates(map[string]any{"content": content, "is_edited": true}).Error
+	})
+}
+
+// 读取评论修订记录 按时间顺序
+func ReadCommentRevisions(ctx context.Context, id uint) (revisions []model.CommentRevision, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.CommentRevision{}).Where("comment_id=?", id).Order("id").Find(&revisions).Error
+	if err != nil {
+		return revisions, err
+	}
+	return revisions, nil
 }
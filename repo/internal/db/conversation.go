@@ -0,0 +1,156 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 按ID大小排列会话双方
+func orderConversationUsers(userID uint, otherID uint) (user1ID uint, user2ID uint) {
+	if userID < otherID {
+		return userID, otherID
+	}
+	return otherID, userID
+}
+
+// 更新会话最近一条消息(会话不存在时创建 仅当新消息更新时更新) 并为对方增加unread条未读消息
+func setConversationLastMessage(tx *gorm.DB, userID uint, otherID uint, messageID uint, messageAt time.Time, unread int64) (err error) {
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	conversation := &model.Conversation{User1ID: user1ID, User2ID: user2ID, LastMessageID: messageID, LastMessageAt: messageAt}
+	if otherID == user1ID {
+		conversation.User1Unread = unread
+	} else {
+		conversation.User2Unread = unread
+	}
+	return tx.Model(&model.Conversation{}).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user1_id"}, {Name: "user2_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"last_message_id": gorm.Expr("GREATEST(last_message_id, VALUES(last_message_id))"),
+			"last_message_at": gorm.Expr("GREATEST(last_message_at, VALUES(last_message_at))"),
+			"user1_unread":    gorm.Expr("user1_unread + VALUES(user1_unread)"),
+			"user2_unread":    gorm.Expr("user2_unread + VALUES(user2_unread)"),
+		}),
+	}).Create(conversation).Error
+}
+
+// 按双方之间最近一条可见消息(不含隐藏及撤回消息)重新计算会话最近一条消息(会话不存在时创建) 用于原最近一条消息被隐藏或撤回后
+func refreshConversationLastMessage(tx *gorm.DB, userID uint, otherID uint) (err error) {
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	err = tx.Model(&model.Conversation{}).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Conversation{User1ID: user1ID, User2ID: user2ID, LastMessageAt: time.Now()}).Error
+	if err != nil {
+		return err
+	}
+	visible := func(column string) *gorm.DB {
+		return tx.Model(&model.Message{}).Select(column).Where(tx.Where("from_user_id=? AND to_user_id=?", user1ID, user2ID).Or("from_user_id=? AND to_user_id=?", user2ID, user1ID)).Where("is_hidden=? AND is_recalled=?", false, false)
+	}
+	return tx.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=?", user1ID, user2ID).Updates(map[string]any{
+		"last_message_id": gorm.Expr("IFNULL((?),0)", visible("MAX(id)")),
+		"last_message_at": gorm.Expr("IFNULL((?),last_message_at)", visible("MAX(created_at)")), // 无可见消息时保留原活跃时间
+	}).Error
+}
+
+// 按双方之间最近一条可见消息重新计算会话最近一条消息(会话不存在时创建)
+func RefreshConversationLastMessage(ctx context.Context, userID uint, otherID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return refreshConversationLastMessage(DB, userID, otherID)
+}
+
+// 批量查找用户与各对方用户会话的最近一条消息ID 未找到会话的对方用户不包含在结果中
+func FindConversationsLastMessageBatch(ctx context.Context, userID uint, otherIDs []uint) (lastMessageIDs map[uint]uint, err error) {
+	DB := _db.WithContext(ctx)
+	lastMessageIDs = make(map[uint]uint, len(otherIDs))
+	if len(otherIDs) == 0 {
+		return lastMessageIDs, nil
+	}
+	var conversations []model.Conversation
+	err = DB.Model(&model.Conversation{}).Select("user1_id", "user2_id", "last_message_id").Where(DB.Where("user1_id=? AND user2_id IN ?", userID, otherIDs).Or("user2_id=? AND user1_id IN ?", userID, otherIDs)).Find(&conversations).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, conversation := range conversations {
+		if conversation.User1ID == userID {
+			lastMessageIDs[conversation.User2ID] = conversation.LastMessageID
+		} else {
+			lastMessageIDs[conversation.User1ID] = conversation.LastMessageID
+		}
+	}
+	return lastMessageIDs, nil
+}
+
+// 按最近活跃时间倒序分页查找用户的会话(不含无消息往来的会话) (select: *)
+func FindUserConversations(ctx context.Context, userID uint, offset int, num int) (conversations []model.Conversation, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Conversation{}).Where(DB.Where("user1_id=?", userID).Or("user2_id=?", userID)).Where("last_message_id<>?", 0).Order("last_message_at desc, id desc").Offset(offset).Limit(num).Find(&conversations).Error
+	if err != nil {
+		return nil, err
+	}
+	return conversations, nil
+}
+
+// 清零用户在会话中的未读消息数
+func ClearConversationUnread(ctx context.Context, userID uint, otherID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	column := "user2_unread"
+	if userID == user1ID {
+		column = "user1_unread"
+	}
+	return DB.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=?", user1ID, user2ID).Update(column, 0).Error
+}
+
+// 按已读进度重置用户在会话中的未读消息数(对方发来的ID大于readID的消息视为未读 不含隐藏消息、撤回消息及用户已删除的消息)
+func ResetConversationUnread(ctx context.Context, userID uint, otherID uint, readID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	column := "user2_unread"
+	if userID == user1ID {
+		column = "user1_unread"
+	}
+	deleted := DB.Model(&model.MessageDeletion{}).Select("1").Where("message_deletion.message_id=message.id AND message_deletion.user_id=?", userID)
+	unread := DB.Model(&model.Message{}).Select("COUNT(*)").Where("from_user_id=? AND to_user_id=? AND id>? AND is_hidden=? AND is_recalled=?", otherID, userID, readID, false, false).Where("NOT EXISTS (?)", deleted)
+	return DB.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=?", user1ID, user2ID).Update(column, unread).Error
+}
+
+// 为用户减少一条会话中的未读消息(不低于0)
+func decrConversationUnread(tx *gorm.DB, userID uint, otherID uint) (err error) {
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	column := "user2_unread"
+	if userID == user1ID {
+		column = "user1_unread"
+	}
+	return tx.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=? AND "+column+">?", user1ID, user2ID, 0).Update(column, gorm.Expr(column+"-?", 1)).Error
+}
+
+// 为用户增加一条会话中的未读消息
+func incrConversationUnread(tx *gorm.DB, userID uint, otherID uint) (err error) {
+	user1ID, user2ID := orderConversationUsers(userID, otherID)
+	column := "user2_unread"
+	if userID == user1ID {
+		column = "user1_unread"
+	}
+	return tx.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=?", user1ID, user2ID).Update(column, gorm.Expr(column+"+?", 1)).Error
+}
+
+// 统计用户全部会话的未读消息总数(与会话列表一致 不含与存在任一方向拉黑关系的用户之间的会话)
+func CountUserUnread(ctx context.Context, userID uint) (count int64, err error) {
+	DB := _db.WithContext(ctx)
+	blocked := DB.Table("block").Select("1").Where("(block.user_id=? AND block.block_id=CASE WHEN conversation.user1_id=? THEN conversation.user2_id ELSE conversation.user1_id END) OR (block.block_id=? AND block.user_id=CASE WHEN conversation.user1_id=? THEN conversation.user2_id ELSE conversation.user1_id END)", userID, userID, userID, userID)
+	err = DB.Model(&model.Conversation{}).Select("IFNULL(SUM(CASE WHEN user1_id=? THEN user1_unread ELSE user2_unread END),0)", userID).Where(DB.Where("user1_id=?", userID).Or("user2_id=?", userID)).Where("NOT EXISTS (?)", blocked).Scan(&count).Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// 根据已有消息补全会话记录(用于迁移早于会话记录的历史消息 不改变未读消息数)
+func backfillConversations(tx *gorm.DB) (err error) {
+	return tx.Exec(`INSERT INTO conversation (created_at, updated_at, user1_id, user2_id, last_message_id, last_message_at, user1_unread, user2_unread)
+		SELECT NOW(), NOW(), LEAST(from_user_id, to_user_id), GREATEST(from_user_id, to_user_id), MAX(id), MAX(created_at), 0, 0 FROM message
+		WHERE deleted_at IS NULL AND is_hidden=? AND is_recalled=? AND from_user_id<>to_user_id GROUP BY LEAST(from_user_id, to_user_id), GREATEST(from_user_id, to_user_id)
+		ON DUPLICATE KEY UPDATE last_message_id=GREATEST(conversation.last_message_id, VALUES(last_message_id)), last_message_at=GREATEST(conversation.last_message_at, VALUES(last_message_at))`, false, false).Error
+}
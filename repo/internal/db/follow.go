@@ -0,0 +1,99 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// 创建关注申请
+func CreateFollowRequest(ctx context.Context, userID uint, followID uint) (request *model.FollowRequest, err error) {
+	if userID == followID {
+		return nil, ErrorSelfFollow // 默认禁止自己关注自己
+	}
+
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.FollowRequest
+		err2 := tx.Model(&model.FollowRequest{}).Select("id").Where("user_id=? AND follow_id=?", userID, followID).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复创建
+			return ErrorRecordExists
+		}
+
+		request = &model.FollowRequest{UserID: userID, FollowID: followID}
+		return tx.Model(&model.FollowRequest{}).Create(request).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// 根据申请者与被申请者删除关注申请(撤回申请)
+func DeleteFollowRequest(ctx context.Context, userID uint, followID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	result := DB.Where("user_id=? AND follow_id=?", userID, followID).Delete(&model.FollowRequest{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 { // 不允许凭空删除
+		return ErrorRecordNotExists
+	}
+	return nil
+}
+
+// 取出(读取并删除)发给指定用户的关注申请 用于同意或拒绝申请
+func TakeFollowRequest(ctx context.Context, id uint, followID uint) (request *model.FollowRequest, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.FollowRequest
+		err2 := tx.Model(&model.FollowRequest{}).Where("id=? AND follow_id=?", id, followID).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不存在或非发给该用户的申请
+			return ErrorRecordNotExists
+		}
+		request = &results[0]
+
+		return tx.Delete(&model.FollowRequest{}, request.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// 取出(读取并删除)发给指定用户的全部关注申请
+func TakeAllFollowRequests(ctx context.Context, followID uint) (requests []model.FollowRequest, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		err2 := tx.Model(&model.FollowRequest{}).Where("follow_id=?", followID).Find(&requests).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(requests) == 0 {
+			return nil
+		}
+		return tx.Where("follow_id=?", followID).Delete(&model.FollowRequest{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// 分页查找发给指定用户的关注申请 按申请时间倒序
+func FindFollowRequests(ctx context.Context, followID uint, offset int, num int) (requests []model.FollowRequest, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.FollowRequest{}).Where("follow_id=?", followID).Order("created_at desc, id desc").Offset(offset).Limit(num).Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
 )
@@ -18,9 +19,19 @@ import (
 // 自定义错误类型
 var ErrorRecordExists = errors.New("记录已存在")
 var ErrorRecordNotExists = errors.New("记录不存在")
+var ErrorInvalidTarget = errors.New("举报对象类型有误")
+var ErrorRestoreConflict = errors.New("所属内容已不存在, 无法恢复")
 
 var _db *gorm.DB
 
+// 一次性数据迁移 按顺序执行 每项成功后记录在迁移表中 不再重复执行
+var dataMigrations = []struct {
+	name string
+	run  func(tx *gorm.DB) error
+}{
+	{"backfill_conversations", backfillConversations}, // 补全早于会话记录的历史消息
+}
+
 func InitMySQL() {
 	mysqlCfg := conf.Cfg().MySQL
 
@@ -63,5 +74,55 @@ func InitMySQL() {
 // 为了保护数据, 并不支持改变已有的字段类型或删除未被使用的字段
 func MakeMigrate() (err error) {
 	DB := _db.WithContext(context.Background())
-	return DB.Set("gorm:table_options", "charset=utf8mb4").AutoMigrate(&model.User{}, &model.Video{}, &model.Comment{}, &model.Message{})
+	return DB.Set("gorm:table_options", "charset=utf8mb4").AutoMigrate(&model.User{}, &model.Video{}, &model.Comment{}, &model.Message{}, &model.Collection{}, &model.CollectionItem{}, &model.Repost{}, &model.Report{}, &model.Mention{}, &model.Notification{}, &model.CommentRevision{}, &model.Trash{}, &model.FollowRequest{}, &model.Conversation{}, &model.MessageDeletion{})
+}
+
+// 执行尚未执行的一次性数据迁移(不受自动迁移开关影响)
+// 迁移记录与迁移本身在同一事务中写入 多个实例同时启动时仅有一个实例执行
+func MakeDataMigrate() (err error) {
+	DB := _db.WithContext(context.Background())
+	err = DB.Set("gorm:table_options", "charset=utf8mb4").AutoMigrate(&model.Migration{})
+	if err != nil {
+		return err
+	}
+	for _, migration := range dataMigrations {
+		err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+			result := tx.Model(&model.Migration{}).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Migration{Name: migration.name})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 { // 已执行过
+				return nil
+			}
+			return migration.run(tx)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", migration.name, err)
+		}
+	}
+	return nil
+}
+
+// 批量读取计数结果
+type countResult struct {
+	ID    uint
+	Count int64
+}
+
+// 批量读取指定模型的计数字段 未找到的ID不包含在结果中
+func countBatch(ctx context.Context, value any, column string, ids []uint) (counts map[uint]int64, err error) {
+	DB := _db.WithContext(ctx)
+	counts = make(map[uint]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	var results []countResult
+	err = DB.Model(value).Select("id", column+" AS count").Where("id IN ?", ids).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		counts[result.ID] = result.Count
+	}
+	return counts, nil
 }
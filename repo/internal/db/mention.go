@@ -0,0 +1,78 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// 根据用户名批量查找用户 (select: ID, Username) 未找到的用户名不包含在结果中
+func FindUsersByUsernames(ctx context.Context, usernames []string) (users []model.User, err error) {
+	DB := _db.WithContext(ctx)
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err = DB.Model(&model.User{}).Select("id", "username").Where("username IN ?", usernames).Find(&users).Error
+	return users, err
+}
+
+// 创建或替换提及对象中的提及 并为每个新被提及的用户(提及者本人除外)创建一条通知
+func CreateMentions(ctx context.Context, actorID uint, targetType int, targetID uint, mentions []model.Mention) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var previous []model.Mention
+		err2 := tx.Model(&model.Mention{}).Select("id", "user_id").Where("target_type=? AND target_id=?", targetType, targetID).Find(&previous).Error
+		if err2 != nil {
+			return err2
+		}
+		notified := make(map[uint]bool, len(previous)+len(mentions)) // 此前已被提及的用户不再重复通知
+		if len(previous) > 0 {
+			ids := make([]uint, 0, len(previous))
+			for _, mention := range previous {
+				ids = append(ids, mention.ID)
+				notified[mention.UserID] = true
+			}
+			err2 = tx.Model(&model.Mention{}).Where("id IN ?", ids).Delete(&model.Mention{}).Error
+			if err2 != nil {
+				return err2
+			}
+		}
+		if len(mentions) == 0 {
+			return nil
+		}
+
+		err2 = tx.Model(&model.Mention{}).Create(&mentions).Error
+		if err2 != nil {
+			return err2
+		}
+
+		kind := model.NotificationKindCommentMention
+		if targetType == model.MentionTargetVideo {
+			kind = model.NotificationKindVideoMention
+		}
+		notifications := make([]model.Notification, 0, len(mentions))
+		for _, mention := range mentions {
+			if mention.UserID == actorID || notified[mention.UserID] { // 同一对象中重复提及仅通知一次
+				continue
+			}
+			notified[mention.UserID] = true
+			notifications = append(notifications, model.Notification{UserID: mention.UserID, ActorID: actorID, Kind: kind, TargetID: targetID})
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		return tx.Model(&model.Notification{}).Create(&notifications).Error
+	})
+}
+
+// 批量读取提及对象中的提及 按位置排序
+func FindMentionsBatch(ctx context.Context, targetType int, targetIDs []uint) (mentions []model.Mention, err error) {
+	DB := _db.WithContext(ctx)
+	if len(targetIDs) == 0 {
+		return mentions, nil
+	}
+	err = DB.Model(&model.Mention{}).Where("target_type=? AND target_id IN ?", targetType, targetIDs).Order("`offset`").Find(&mentions).Error
+	return mentions, err
+}
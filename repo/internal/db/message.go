@@ -5,6 +5,9 @@ import (
 
 	"context"
 	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // 获取消息主键最大值
@@ -14,28 +17,140 @@ func MaxMessageID(ctx context.Context) (max uint, err error) {
 	return max, err
 }
 
-// 创建消息
-func CreateMessage(ctx context.Context, fromUserID uint, toUserID uint, content string) (message *model.Message, err error) {
+// 创建消息(同时更新会话最近一条消息)
+func CreateMessage(ctx context.Context, fromUserID uint, toUserID uint, msgType int, content string, payload string) (message *model.Message, err error) {
 	DB := _db.WithContext(ctx)
-	message = &model.Message{Content: content, FromUserID: fromUserID, ToUserID: toUserID}
-	err = DB.Model(&model.Message{}).Create(message).Error
+	message = &model.Message{Content: content, FromUserID: fromUserID, ToUserID: toUserID, Status: model.MessageStatusSent, Type: msgType, Payload: payload}
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		err2 := tx.Model(&model.Message{}).Create(message).Error
+		if err2 != nil {
+			return err2
+		}
+		return setConversationLastMessage(tx, fromUserID, toUserID, message.ID, message.CreatedAt, 1)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return message, nil
 }
 
-// 根据聊天双方ID和创建时间查找消息列表(num==-1时取消数量限制) (select: *)
+// 根据聊天双方ID和创建时间查找消息列表(num==-1时取消数量限制 不含隐藏消息、撤回消息及User1ID已删除的消息) (select: *)
 func FindMessagesByCreatedAt(ctx context.Context, User1ID uint, User2ID uint, createdAt int64, forward bool, num int) (messages []model.Message, err error) {
 	DB := _db.WithContext(ctx)
 	stop := time.Unix(createdAt, 0)
+	deleted := DB.Model(&model.MessageDeletion{}).Select("1").Where("message_deletion.message_id=message.id AND message_deletion.user_id=?", User1ID)
 	if forward {
-		err = DB.Model(&model.Message{}).Where(DB.Where("from_user_id=? AND to_user_id=?", User1ID, User2ID).Or("from_user_id=? AND to_user_id=?", User2ID, User1ID)).Where("created_at>?", stop).Order("created_at").Limit(num).Find(&messages).Error
+		err = DB.Model(&model.Message{}).Where(DB.Where("from_user_id=? AND to_user_id=?", User1ID, User2ID).Or("from_user_id=? AND to_user_id=?", User2ID, User1ID)).Where("is_hidden=? AND is_recalled=?", false, false).Where("NOT EXISTS (?)", deleted).Where("created_at>?", stop).Order("created_at").Limit(num).Find(&messages).Error
 	} else {
-		err = DB.Model(&model.Message{}).Where(DB.Where("from_user_id=? AND to_user_id=?", User1ID, User2ID).Or("from_user_id=? AND to_user_id=?", User2ID, User1ID)).Where("created_at<?", stop).Order("created_at desc").Limit(num).Find(&messages).Error
+		err = DB.Model(&model.Message{}).Where(DB.Where("from_user_id=? AND to_user_id=?", User1ID, User2ID).Or("from_user_id=? AND to_user_id=?", User2ID, User1ID)).Where("is_hidden=? AND is_recalled=?", false, false).Where("NOT EXISTS (?)", deleted).Where("created_at<?", stop).Order("created_at desc").Limit(num).Find(&messages).Error
 	}
 	if err != nil {
 		return messages, err
 	}
 	return messages, err
 }
+
+// 读取消息基本信息 (select: ID, CreatedAt, UpdatedAt, Content, FromUserID, ToUserID, IsHidden, IsRecalled, Status, Type, Payload)
+func ReadMessageBasics(ctx context.Context, id uint) (message *model.Message, err error) {
+	DB := _db.WithContext(ctx)
+	message = &model.Message{}
+	err = DB.Model(&model.Message{}).Select("id", "created_at", "updated_at", "content", "from_user_id", "to_user_id", "is_hidden", "is_recalled", "status", "type", "payload").Where("id=?", id).First(message).Error
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// 批量读取消息基本信息 未找到的消息不包含在结果中 (select: ID, CreatedAt, UpdatedAt, Content, FromUserID, ToUserID, IsHidden, IsRecalled, Status, Type, Payload)
+func ReadMessageBasicsBatch(ctx context.Context, ids []uint) (messages []model.Message, err error) {
+	DB := _db.WithContext(ctx)
+	if len(ids) == 0 {
+		return messages, nil
+	}
+	err = DB.Model(&model.Message{}).Select("id", "created_at", "updated_at", "content", "from_user_id", "to_user_id", "is_hidden", "is_recalled", "status", "type", "payload").Where("id IN ?", ids).Find(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// 读取已持久化的送达与已读进度(fromUserID发给toUserID的消息中已送达与已读的最大ID 无时为0)
+func FindMessagesProgress(ctx context.Context, fromUserID uint, toUserID uint) (deliveredID uint, readID uint, err error) {
+	DB := _db.WithContext(ctx)
+	var result struct {
+		DeliveredID uint
+		ReadID      uint
+	}
+	err = DB.Model(&model.Message{}).Select("IFNULL(MAX(CASE WHEN status>=? THEN id END),0) AS delivered_id, IFNULL(MAX(CASE WHEN status>=? THEN id END),0) AS read_id", model.MessageStatusDelivered, model.MessageStatusRead).Where("from_user_id=? AND to_user_id=?", fromUserID, toUserID).Scan(&result).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.DeliveredID, result.ReadID, nil
+}
+
+// 查找fromUserID发给toUserID的最近一条消息ID(不含撤回消息 无时为0)
+func FindLastMessageID(ctx context.Context, fromUserID uint, toUserID uint) (id uint, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Message{}).Select("IFNULL(MAX(id),0)").Where("from_user_id=? AND to_user_id=? AND is_recalled=?", fromUserID, toUserID, false).Scan(&id).Error
+	return id, err
+}
+
+// 将fromUserID发给toUserID且ID不超过maxID的消息状态推进至status(不回退已推进的状态)
+func UpdateMessagesStatus(ctx context.Context, fromUserID uint, toUserID uint, maxID uint, status int) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Model(&model.Message{}).Where("from_user_id=? AND to_user_id=? AND id<=? AND status<?", fromUserID, toUserID, maxID, status).Update("status", status).Error
+}
+
+// 撤回消息(仅标记撤回 保留消息记录 并修正会话最近一条消息) unread为true时同时为接收者减少一条未读消息
+func RecallMessage(ctx context.Context, id uint, fromUserID uint, toUserID uint, unread bool) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		result := tx.Model(&model.Message{}).Where("id=? AND is_recalled=?", id, false).Update("is_recalled", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 { // 不允许重复撤回
+			return ErrorRecordNotExists
+		}
+		if unread {
+			err2 := decrConversationUnread(tx, toUserID, fromUserID)
+			if err2 != nil {
+				return err2
+			}
+		}
+		return refreshConversationLastMessage(tx, fromUserID, toUserID)
+	})
+}
+
+// 为用户删除消息(仅对该用户隐藏 重复删除时忽略) unread为true时同时为该用户减少一条与otherID会话中的未读消息
+func CreateMessageDeletion(ctx context.Context, userID uint, otherID uint, messageID uint, unread bool) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		result := tx.Model(&model.MessageDeletion{}).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.MessageDeletion{UserID: userID, MessageID: messageID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 || !unread { // 重复删除时不重复减少
+			return nil
+		}
+		return decrConversationUnread(tx, userID, otherID)
+	})
+}
+
+// 批量查找用户已删除的消息ID 返回值为messageIDs中已被该用户删除的部分
+func FindDeletedMessageIDs(ctx context.Context, userID uint, messageIDs []uint) (deletedIDs map[uint]struct{}, err error) {
+	DB := _db.WithContext(ctx)
+	deletedIDs = make(map[uint]struct{})
+	if len(messageIDs) == 0 {
+		return deletedIDs, nil
+	}
+	var ids []uint
+	err = DB.Model(&model.MessageDeletion{}).Select("message_id").Where("user_id=? AND message_id IN ?", userID, messageIDs).Find(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		deletedIDs[id] = struct{}{}
+	}
+	return deletedIDs, nil
+}
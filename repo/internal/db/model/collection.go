@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Collection struct {
+	ID        uint           `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time      `gorm:"autoCreateTime;precision:0;index" redis:"createdat"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
+	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
+
+	Name       string           `gorm:"size:64" redis:"name"`
+	OwnerID    uint             `gorm:"index" redis:"ownerid"`
+	Kind       int              `gorm:"default:2" redis:"kind"` // 1-合集(仅可收录自己的作品)，2-收藏夹(可收录任意已发布视频)
+	IsPublic   bool             `gorm:"default:false" redis:"ispublic"`
+	Items      []CollectionItem `gorm:"foreignKey:CollectionID" redis:"-"`
+	ItemsCount uint             `gorm:"default:0" redis:"itemscount"`
+}
+
+// 合集条目(不使用软删除以便重复收录)
+type CollectionItem struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"`
+
+	CollectionID uint  `gorm:"uniqueIndex:idx_collection_video;index:idx_collection_position,priority:1" redis:"collectionid"`
+	VideoID      uint  `gorm:"uniqueIndex:idx_collection_video" redis:"videoid"`
+	Position     int64 `gorm:"index:idx_collection_position,priority:2" redis:"position"` // 越小越靠前
+}
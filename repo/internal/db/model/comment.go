@@ -12,7 +12,23 @@ type Comment struct {
 	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
 	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
 
-	Content  string `gorm:"size:256" redis:"content"`
-	AuthorID uint   `redis:"authorid"`
-	VideoID  uint   `redis:"videoid"`
+	Content      string `gorm:"size:256" redis:"content"`
+	AuthorID     uint   `redis:"authorid"`
+	VideoID      uint   `redis:"videoid"`
+	IsHidden     bool   `gorm:"default:false;index" redis:"ishidden"` // 被举报隐藏的评论不出现在评论列表中
+	ParentID     uint   `gorm:"default:0;index" redis:"parentid"`     // 所回复的评论ID 为0时表示顶层评论
+	RootID       uint   `gorm:"default:0;index" redis:"rootid"`       // 所属顶层评论ID 为0时表示顶层评论
+	RepliesCount uint   `gorm:"default:0" redis:"-"`                  // 顶层评论下的回复总数
+	LikedCount   uint   `gorm:"default:0" redis:"-"`                  // 评论受赞数(点赞经缓存回写同步至此)
+	IsEdited     bool   `gorm:"default:false" redis:"isedited"`       // 发布后是否被作者编辑过
+}
+
+// 评论修订记录(保存每次编辑前的内容 仅审核员可见)
+type CommentRevision struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"` // 编辑时间
+
+	CommentID uint   `gorm:"index" redis:"commentid"`
+	EditorID  uint   `redis:"editorid"`
+	Content   string `gorm:"size:256" redis:"content"` // 编辑前的内容
 }
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// 会话(聊天双方共用一条 User1ID始终小于User2ID)
+type Conversation struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
+
+	User1ID       uint      `gorm:"uniqueIndex:idx_conversation,priority:1" redis:"user1id"`
+	User2ID       uint      `gorm:"uniqueIndex:idx_conversation,priority:2;index" redis:"user2id"`
+	LastMessageID uint      `redis:"lastmessageid"`                          // 最近一条消息ID 为0时表示双方无消息往来
+	LastMessageAt time.Time `gorm:"precision:0;index" redis:"lastmessageat"` // 最近活跃时间(最近一条消息的发送时间)
+	User1Unread   int64     `gorm:"default:0" redis:"user1unread"`           // User1的未读消息数
+	User2Unread   int64     `gorm:"default:0" redis:"user2unread"`           // User2的未读消息数
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// 关注申请(关注私密账号时需经对方同意 处理后即删除以便重复申请)
+type FollowRequest struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0;index" redis:"createdat"` // 申请时间
+
+	UserID   uint `gorm:"uniqueIndex:idx_follow_request,priority:1" redis:"userid"`         // 申请者ID
+	FollowID uint `gorm:"uniqueIndex:idx_follow_request,priority:2;index" redis:"followid"` // 被申请关注的用户ID
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// 提及对象类型
+const MentionTargetVideo = 1   // 视频标题
+const MentionTargetComment = 2 // 评论
+
+// @提及(写入时即解析为用户ID 位置与长度均以字符计)
+type Mention struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"`
+
+	TargetType int  `gorm:"index:idx_mention_target,priority:1" redis:"targettype"`
+	TargetID   uint `gorm:"index:idx_mention_target,priority:2" redis:"targetid"`
+	UserID     uint `gorm:"index" redis:"userid"` // 被提及的用户ID
+	Offset     int  `redis:"offset"`              // @符号在文本中的位置
+	Length     int  `redis:"length"`              // 含@符号在内的长度
+}
@@ -6,6 +6,11 @@ import (
 	"gorm.io/gorm"
 )
 
+// 消息状态
+const MessageStatusSent = 1      // 已发送
+const MessageStatusDelivered = 2 // 已送达
+const MessageStatusRead = 3      // 已读
+
 type Message struct {
 	ID        uint           `gorm:"primaryKey" redis:"id"`
 	CreatedAt time.Time      `gorm:"autoCreateTime;precision:0;index" redis:"createdat"`
@@ -16,4 +21,18 @@ type Message struct {
 	FromUserID uint   `redis:"fromuserid"`
 	ToUserID   uint   `redis:"touserid"`
 	ToUser     *User  `gorm:"foreignKey:ToUserID" redis:"-"`
+	IsHidden   bool   `gorm:"default:false;index" redis:"ishidden"` // 被举报隐藏的消息不出现在消息列表中
+	IsRecalled bool   `gorm:"default:false" redis:"isrecalled"`     // 被撤回的消息对双方均不再展示 但记录(含接收者关联)保留以供审计
+	Status     int    `gorm:"default:1" redis:"status"`             // 送达与已读状态由缓存记录进度 经回写同步至此
+	Type       int    `gorm:"default:1" redis:"type"`               // 消息类型 1-文本 2-图片 3-视频分享 4-名片(由服务层校验)
+	Payload    string `gorm:"size:64" redis:"payload"`              // 图片消息为图片对象ID 视频分享与名片消息为视频ID与用户ID 文本消息为空
+}
+
+// 消息删除记录(仅为自己删除 消息本身不受影响)
+type MessageDeletion struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"`
+
+	UserID    uint `gorm:"uniqueIndex:idx_message_deletion,priority:1" redis:"userid"`
+	MessageID uint `gorm:"uniqueIndex:idx_message_deletion,priority:2" redis:"messageid"`
 }
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"time"
+)
+
+// 已执行的一次性数据迁移(每项仅执行一次)
+type Migration struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0" redis:"createdat"` // 执行时间
+
+	Name string `gorm:"size:64;uniqueIndex" redis:"name"` // 迁移名称
+}
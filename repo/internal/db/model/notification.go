@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// 通知类型
+const NotificationKindVideoMention = 1   // 在视频标题中被提及
+const NotificationKindCommentMention = 2 // 在评论中被提及
+
+type Notification struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0;index" redis:"createdat"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
+
+	UserID   uint `gorm:"index:idx_notification_user,priority:1" redis:"userid"` // 接收通知的用户ID
+	ActorID  uint `redis:"actorid"`                                              // 触发通知的用户ID
+	Kind     int  `redis:"kind"`
+	TargetID uint `redis:"targetid"`                                                           // 视频ID或评论ID
+	IsRead   bool `gorm:"default:false;index:idx_notification_user,priority:2" redis:"isread"` // 是否已读
+}
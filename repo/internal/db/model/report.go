@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 举报对象类型
+const ReportTargetVideo = 1     // 视频
+const ReportTargetComment = 2   // 评论
+const ReportTargetMessage = 3   // 消息
+const ReportTargetSignature = 4 // 用户签名
+
+// 举报原因
+const ReportReasonOther = 6 // 其他
+
+// 举报状态
+const ReportStateOpen = 1      // 待处理
+const ReportStateActioned = 2  // 已处理(内容已隐藏)
+const ReportStateDismissed = 3 // 已驳回(恢复被自动隐藏或送审隐藏的内容)
+
+type Report struct {
+	ID        uint           `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time      `gorm:"autoCreateTime;precision:0;index" redis:"createdat"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
+	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
+
+	ReporterID uint   `gorm:"index" redis:"reporterid"`
+	TargetType int    `gorm:"index:idx_report_target,priority:1" redis:"targettype"`
+	TargetID   uint   `gorm:"index:idx_report_target,priority:2" redis:"targetid"`
+	Reason     int    `redis:"reason"` // 1-垃圾广告，2-辱骂攻击，3-色情低俗，4-暴力血腥，5-违法违规，6-其他
+	Detail     string `gorm:"size:256" redis:"detail"`
+	State      int    `gorm:"default:1;index" redis:"state"`
+	HandlerID  uint   `redis:"handlerid"` // 处理该举报的审核员ID
+}
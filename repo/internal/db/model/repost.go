@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repost struct {
+	ID        uint           `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time      `gorm:"autoCreateTime;precision:0;index" redis:"createdat"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
+	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
+
+	Content string `gorm:"size:256" redis:"content"` // 转发时附带的评论 可为空
+	UserID  uint   `gorm:"index" redis:"userid"`
+	VideoID uint   `gorm:"index" redis:"videoid"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// 回收站对象类型
+const TrashTargetVideo = 1   // 视频(含草稿)
+const TrashTargetComment = 2 // 评论(含其下全部回复)
+
+// 回收站条目(每次删除操作对应一条 仅内容作者可恢复)
+type Trash struct {
+	ID        uint      `gorm:"primaryKey" redis:"id"`
+	CreatedAt time.Time `gorm:"autoCreateTime;precision:0;index" redis:"createdat"` // 删除时间
+
+	OwnerID    uint   `gorm:"index" redis:"ownerid"` // 被删除内容的作者ID(而非执行删除的用户)
+	TargetType int    `gorm:"index:idx_trash_target,priority:1" redis:"targettype"`
+	TargetID   uint   `gorm:"index:idx_trash_target,priority:2" redis:"targetid"`
+	Summary    string `gorm:"size:256" redis:"summary"` // 删除时的视频标题或评论内容
+}
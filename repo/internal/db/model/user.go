@@ -13,21 +13,27 @@ type User struct {
 	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
 	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
 
-	Username       string    `gorm:"size:32;uniqueIndex" redis:"username"`
-	Password       string    `gorm:"size:64" redis:"-"` // bcrypt结果长度为60
-	Signature      string    `gorm:"size:256" redis:"signature"`
-	Works          []Video   `gorm:"foreignKey:AuthorID" redis:"-"`
-	WorksCount     uint      `gorm:"default:0" redis:"-"`
-	Favorites      []*Video  `gorm:"many2many:favorite" redis:"-"`
-	FavoritesCount uint      `gorm:"default:0" redis:"-"`
-	FavoritedCount uint      `gorm:"default:0" redis:"-"`
-	Comments       []Comment `gorm:"foreignKey:AuthorID" redis:"-"`
-	CommentsCount  uint      `gorm:"default:0" redis:"-"`
-	Follows        []*User   `gorm:"many2many:follow;joinForeignKey:user_id;joinReferences:follow_id" redis:"-"`
-	FollowsCount   uint      `gorm:"default:0" redis:"-"`
-	Followers      []*User   `gorm:"many2many:follow;joinForeignKey:follow_id;joinReferences:user_id" redis:"-"`
-	FollowersCount uint      `gorm:"default:0" redis:"-"`
-	Messages       []Message `gorm:"foreignKey:FromUserID" redis:"-"`
+	Username        string       `gorm:"size:32;uniqueIndex" redis:"username"`
+	Password        string       `gorm:"size:64" redis:"-"` // bcrypt结果长度为60
+	Signature       string       `gorm:"size:256" redis:"signature"`
+	SignatureHidden bool         `gorm:"default:false" redis:"signaturehidden"` // 被举报隐藏的签名不对外展示
+	IsPrivate       bool         `gorm:"default:false" redis:"isprivate"`       // 私密账号需经同意方可关注 作品、点赞及关注列表仅对已关注者可见
+	Works           []Video      `gorm:"foreignKey:AuthorID" redis:"-"`
+	WorksCount      uint         `gorm:"default:0" redis:"-"`
+	Favorites       []*Video     `gorm:"many2many:favorite" redis:"-"`
+	FavoritesCount  uint         `gorm:"default:0" redis:"-"`
+	FavoritedCount  uint         `gorm:"default:0" redis:"-"`
+	Likes           []*Comment   `gorm:"many2many:comment_like" redis:"-"`
+	Comments        []Comment    `gorm:"foreignKey:AuthorID" redis:"-"`
+	CommentsCount   uint         `gorm:"default:0" redis:"-"`
+	Follows         []*User      `gorm:"many2many:follow;joinForeignKey:user_id;joinReferences:follow_id" redis:"-"`
+	FollowsCount    uint         `gorm:"default:0" redis:"-"`
+	Followers       []*User      `gorm:"many2many:follow;joinForeignKey:follow_id;joinReferences:user_id" redis:"-"`
+	FollowersCount  uint         `gorm:"default:0" redis:"-"`
+	Blocks          []*User      `gorm:"many2many:block;joinForeignKey:user_id;joinReferences:block_id" redis:"-"`
+	Messages        []Message    `gorm:"foreignKey:FromUserID" redis:"-"`
+	Collections     []Collection `gorm:"foreignKey:OwnerID" redis:"-"`
+	Reposts         []Repost     `gorm:"foreignKey:UserID" redis:"-"`
 }
 
 const passwordCost = 12 //密码加密难度
@@ -12,10 +12,17 @@ type Video struct {
 	UpdatedAt time.Time      `gorm:"autoUpdateTime;precision:0" redis:"updatedat"`
 	DeletedAt gorm.DeletedAt `gorm:"index" redis:"-"`
 
-	Title          string    `gorm:"size:256" redis:"title"`
-	AuthorID       uint      `redis:"authorid"`
-	Favorited      []*User   `gorm:"many2many:favorite" redis:"-"`
-	FavoritedCount uint      `gorm:"default:0" redis:"-"`
-	Comments       []Comment `gorm:"foreignKey:VideoID" redis:"-"`
-	CommentsCount  uint      `gorm:"default:0" redis:"-"`
+	Title           string    `gorm:"size:256" redis:"title"`
+	AuthorID        uint      `redis:"authorid"`
+	IsDraft         bool      `gorm:"default:false;index" redis:"isdraft"`  // 草稿不出现在公开列表中且不计入作品数
+	IsHidden        bool      `gorm:"default:false;index" redis:"ishidden"` // 被举报隐藏的视频不出现在任何列表中
+	PinnedCommentID uint      `gorm:"default:0" redis:"pinnedcommentid"`    // 作者置顶的顶层评论ID 为0时表示无置顶
+	CommentPolicy   int       `gorm:"default:1" redis:"commentpolicy"`      // 评论权限 1-所有人 2-关闭评论 3-仅粉丝 4-仅互关好友(由服务层校验)
+	HasCustomCover  bool      `gorm:"default:false" redis:"-"`              // 作者已上传自定义封面时不再自动切取封面
+	Favorited       []*User   `gorm:"many2many:favorite" redis:"-"`
+	FavoritedCount  uint      `gorm:"default:0" redis:"-"`
+	Comments        []Comment `gorm:"foreignKey:VideoID" redis:"-"`
+	CommentsCount   uint      `gorm:"default:0" redis:"-"`
+	Reposts         []Repost  `gorm:"foreignKey:VideoID" redis:"-"`
+	RepostsCount    uint      `gorm:"default:0" redis:"-"`
 }
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+)
+
+// 分页查找用户收到的通知 按时间倒序
+func FindUserNotifications(ctx context.Context, userID uint, offset int, num int) (notifications []model.Notification, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Notification{}).Where("user_id=?", userID).Order("created_at DESC").Order("id DESC").Offset(offset).Limit(num).Find(&notifications).Error
+	if err != nil {
+		return notifications, err
+	}
+	return notifications, nil
+}
+
+// 读取用户未读通知数量(不含对象已删除或被隐藏的通知 与通知列表保持一致)
+func CountUserUnreadNotifications(ctx context.Context, userID uint) (count int64) {
+	DB := _db.WithContext(ctx)
+	videos := DB.Model(&model.Video{}).Select("id").Where("is_hidden=? AND is_draft=?", false, false)
+	comments := DB.Model(&model.Comment{}).Select("id").Where("is_hidden=?", false)
+	err := DB.Model(&model.Notification{}).Where("user_id=? AND is_read=?", userID, false).
+		Where("(kind=? AND target_id IN (?)) OR (kind=? AND target_id IN (?))", model.NotificationKindVideoMention, videos, model.NotificationKindCommentMention, comments).
+		Count(&count).Error
+	if err != nil {
+		return -1 // 出错
+	}
+	return count
+}
+
+// 将用户的通知标记为已读(id为0时标记全部)
+func UpdateNotificationsRead(ctx context.Context, userID uint, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	query := DB.Model(&model.Notification{}).Where("user_id=? AND is_read=?", userID, false)
+	if id != 0 {
+		query = query.Where("id=?", id)
+	}
+	return query.Update("is_read", true).Error
+}
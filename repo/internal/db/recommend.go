@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+)
+
+// 统计二度关注 即用户的关注中有多少人关注了候选用户 (最多返回num名候选用户)
+func CountSecondDegreeFollows(ctx context.Context, userID uint, num int) (counts map[uint]int64, err error) {
+	DB := _db.WithContext(ctx)
+	var results []countResult
+	err = DB.Table("follow AS f1").Select("f2.follow_id AS id, COUNT(*) AS count").Joins("JOIN follow AS f2 ON f2.user_id=f1.follow_id").Where("f1.user_id=? AND f2.follow_id<>?", userID, userID).Group("f2.follow_id").Order("count desc").Limit(num).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return collectCounts(results), nil
+}
+
+// 统计共同关注 即用户与候选用户共同关注了多少人 (最多返回num名候选用户)
+func CountCommonFollows(ctx context.Context, userID uint, num int) (counts map[uint]int64, err error) {
+	DB := _db.WithContext(ctx)
+	var results []countResult
+	err = DB.Table("follow AS f1").Select("f2.user_id AS id, COUNT(*) AS count").Joins("JOIN follow AS f2 ON f2.follow_id=f1.follow_id").Where("f1.user_id=? AND f2.user_id<>?", userID, userID).Group("f2.user_id").Order("count desc").Limit(num).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return collectCounts(results), nil
+}
+
+// 统计共同点赞 即用户与候选用户共同点赞了多少视频 (最多返回num名候选用户)
+func CountSharedFavorites(ctx context.Context, userID uint, num int) (counts map[uint]int64, err error) {
+	DB := _db.WithContext(ctx)
+	var results []countResult
+	err = DB.Table("favorite AS v1").Select("v2.user_id AS id, COUNT(*) AS count").Joins("JOIN favorite AS v2 ON v2.video_id=v1.video_id").Where("v1.user_id=? AND v2.user_id<>?", userID, userID).Group("v2.user_id").Order("count desc").Limit(num).Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	return collectCounts(results), nil
+}
+
+// 将计数结果转换为映射
+func collectCounts(results []countResult) (counts map[uint]int64) {
+	counts = make(map[uint]int64, len(results))
+	for _, result := range results {
+		counts[result.ID] = result.Count
+	}
+	return counts
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// 获取举报对象对应的模型及隐藏状态字段
+func reportTargetModel(targetType int) (target any, column string, err error) {
+	switch targetType {
+	case model.ReportTargetVideo:
+		return &model.Video{}, "is_hidden", nil
+	case model.ReportTargetComment:
+		return &model.Comment{}, "is_hidden", nil
+	case model.ReportTargetMessage:
+		return &model.Message{}, "is_hidden", nil
+	case model.ReportTargetSignature:
+		return &model.User{}, "signature_hidden", nil
+	default:
+		return nil, "", ErrorInvalidTarget
+	}
+}
+
+// 设置内容隐藏状态 隐藏或恢复消息时同时修正所属会话的最近一条消息 隐藏或恢复未读消息时为接收者减少或增加一条未读消息
+func setContentHidden(tx *gorm.DB, targetType int, targetID uint, hidden bool) (err error) {
+	target, column, err := reportTargetModel(targetType)
+	if err != nil {
+		return err
+	}
+	result := tx.Model(target).Where("id=? AND "+column+"=?", targetID, !hidden).Update(column, hidden)
+	if result.Error != nil || targetType != model.ReportTargetMessage || result.RowsAffected == 0 { // 隐藏状态未改变时无需修正
+		return result.Error
+	}
+
+	var messages []model.Message
+	err = tx.Model(&model.Message{}).Select("id", "from_user_id", "to_user_id", "is_recalled", "status").Where("id=?", targetID).Limit(1).Find(&messages).Error
+	if err != nil || len(messages) == 0 {
+		return err
+	}
+	message := messages[0]
+
+	// 未读消息(以数据库中的消息状态为准 可能滞后于缓存中的已读进度 已读时将重新计算)
+	if !message.IsRecalled && message.Status < model.MessageStatusRead {
+		var deleted int64
+		err = tx.Model(&model.MessageDeletion{}).Where("user_id=? AND message_id=?", message.ToUserID, message.ID).Count(&deleted).Error
+		if err != nil {
+			return err
+		}
+		if deleted == 0 && hidden { // 删除时已减少过未读消息数
+			err = decrConversationUnread(tx, message.ToUserID, message.FromUserID)
+		} else if deleted == 0 {
+			err = incrConversationUnread(tx, message.ToUserID, message.FromUserID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return refreshConversationLastMessage(tx, message.FromUserID, message.ToUserID)
+}
+
+// 创建举报 返回该对象当前待处理的举报数
+func CreateReport(ctx context.Context, reporterID uint, targetType int, targetID uint, reason int, detail string) (report *model.Report, openCount int64, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		report = &model.Report{ReporterID: reporterID, TargetType: targetType, TargetID: targetID, Reason: reason, Detail: detail}
+
+		var results []model.Report
+		err2 := tx.Model(&model.Report{}).Select("id").Where("reporter_id=? AND target_type=? AND target_id=? AND state=?", reporterID, targetType, targetID, model.ReportStateOpen).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复举报
+			return ErrorRecordExists
+		}
+
+		err2 = tx.Model(&model.Report{}).Create(report).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Model(&model.Report{}).Where("target_type=? AND target_id=? AND state=?", targetType, targetID, model.ReportStateOpen).Count(&openCount).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return report, openCount, nil
+}
+
+// 设置内容隐藏状态
+func SetContentHidden(ctx context.Context, targetType int, targetID uint, hidden bool) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		return setContentHidden(tx, targetType, targetID, hidden)
+	})
+}
+
+// 创建系统送审记录(举报人ID为0)并隐藏内容
+func CreateReview(ctx context.Context, targetType int, targetID uint, detail string) (report *model.Report, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		report = &model.Report{ReporterID: 0, TargetType: targetType, TargetID: targetID, Reason: model.ReportReasonOther, Detail: detail}
+
+		err2 := tx.Model(&model.Report{}).Create(report).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return setContentHidden(tx, targetType, targetID, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// 处理举报 同一对象的全部待处理举报将一并处理 已处理时隐藏内容 已驳回时恢复被自动隐藏或送审隐藏的内容(曾有举报被处理时保持隐藏) (返回 select: *)
+func HandleReports(ctx context.Context, id uint, handlerID uint, state int) (report *model.Report, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.Report
+		err2 := tx.Model(&model.Report{}).Where("id=? AND state=?", id, model.ReportStateOpen).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许处理不存在或已处理的举报
+			return ErrorRecordNotExists
+		}
+		report = &results[0]
+
+		err2 = tx.Model(&model.Report{}).Where("target_type=? AND target_id=? AND state=?", report.TargetType, report.TargetID, model.ReportStateOpen).Updates(map[string]any{"state": state, "handler_id": handlerID}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		if state == model.ReportStateActioned {
+			return setContentHidden(tx, report.TargetType, report.TargetID, true)
+		}
+
+		var actioned int64
+		err2 = tx.Model(&model.Report{}).Where("target_type=? AND target_id=? AND state=?", report.TargetType, report.TargetID, model.ReportStateActioned).Count(&actioned).Error
+		if err2 != nil {
+			return err2
+		}
+		if actioned > 0 { // 内容曾被审核员处理隐藏
+			return nil
+		}
+		return setContentHidden(tx, report.TargetType, report.TargetID, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// 根据状态按时间顺序分页查找举报列表 (select: *)
+func FindReportsByState(ctx context.Context, state int, offset int, num int) (reports []model.Report, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Report{}).Where("state=?", state).Order("created_at").Offset(offset).Limit(num).Find(&reports).Error
+	if err != nil {
+		return reports, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,178 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// 以内存SQLite替换数据库连接
+func openTestDB(t *testing.T) {
+	DB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+		Logger:         logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite err: %v", err)
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		t.Fatalf("sqlite DB err: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存数据库仅在同一连接内可见
+	err = DB.AutoMigrate(&model.User{}, &model.Video{}, &model.Report{}, &model.Message{}, &model.Conversation{}, &model.MessageDeletion{})
+	if err != nil {
+		t.Fatalf("AutoMigrate err: %v", err)
+	}
+	old := _db
+	_db = DB
+	t.Cleanup(func() {
+		sqlDB.Close()
+		_db = old
+	})
+}
+
+func createTestUsers(t *testing.T, num int) {
+	for i := 1; i <= num; i++ {
+		if err := _db.Create(&model.User{ID: uint(i), Username: "user" + strconv.Itoa(i)}).Error; err != nil {
+			t.Fatalf("create user err: %v", err)
+		}
+	}
+}
+
+func videoHidden(t *testing.T, id uint) bool {
+	video, err := ReadVideoBasics(context.Background(), id)
+	if err != nil {
+		t.Fatalf("ReadVideoBasics err: %v", err)
+	}
+	return video.IsHidden
+}
+
+// 举报达到阈值自动隐藏后驳回 内容恢复
+func TestDismissRestoresAutoHidden(t *testing.T) {
+	openTestDB(t)
+	createTestUsers(t, 3)
+	ctx := context.Background()
+	if err := _db.Create(&model.Video{ID: 1, AuthorID: 1, Title: "v"}).Error; err != nil {
+		t.Fatalf("create video err: %v", err)
+	}
+	const threshold = 2
+
+	// 举报
+	var report *model.Report
+	var openCount int64
+	var err error
+	for _, reporterID := range []uint{2, 3} {
+		report, openCount, err = CreateReport(ctx, reporterID, model.ReportTargetVideo, 1, model.ReportReasonOther, "")
+		if err != nil {
+			t.Fatalf("CreateReport(%d) err: %v", reporterID, err)
+		}
+	}
+	if _, _, err = CreateReport(ctx, 2, model.ReportTargetVideo, 1, model.ReportReasonOther, ""); err != ErrorRecordExists {
+		t.Errorf("duplicate CreateReport err = %v, want ErrorRecordExists", err)
+	}
+	if openCount != threshold {
+		t.Fatalf("openCount = %d, want %d", openCount, threshold)
+	}
+
+	// 自动隐藏
+	if err = SetContentHidden(ctx, model.ReportTargetVideo, 1, true); err != nil {
+		t.Fatalf("SetContentHidden err: %v", err)
+	}
+	if !videoHidden(t, 1) {
+		t.Fatal("video not hidden after reaching threshold")
+	}
+
+	// 驳回
+	handled, err := HandleReports(ctx, report.ID, 1, model.ReportStateDismissed)
+	if err != nil {
+		t.Fatalf("HandleReports err: %v", err)
+	}
+	if handled.TargetID != 1 {
+		t.Errorf("handled target = %d, want 1", handled.TargetID)
+	}
+	var open int64
+	_db.Model(&model.Report{}).Where("state=?", model.ReportStateOpen).Count(&open)
+	if open != 0 {
+		t.Errorf("open reports after dismissal = %d, want 0", open)
+	}
+
+	// 恢复
+	if videoHidden(t, 1) {
+		t.Error("video still hidden after dismissal")
+	}
+	if _, err = HandleReports(ctx, report.ID, 1, model.ReportStateDismissed); err != ErrorRecordNotExists {
+		t.Errorf("HandleReports on handled report err = %v, want ErrorRecordNotExists", err)
+	}
+}
+
+// 曾被处理隐藏的内容 再次举报后驳回仍保持隐藏
+func TestDismissKeepsActionedHidden(t *testing.T) {
+	openTestDB(t)
+	createTestUsers(t, 3)
+	ctx := context.Background()
+	if err := _db.Create(&model.Video{ID: 1, AuthorID: 1, Title: "v"}).Error; err != nil {
+		t.Fatalf("create video err: %v", err)
+	}
+
+	report, _, err := CreateReport(ctx, 2, model.ReportTargetVideo, 1, model.ReportReasonOther, "")
+	if err != nil {
+		t.Fatalf("CreateReport err: %v", err)
+	}
+	if _, err = HandleReports(ctx, report.ID, 1, model.ReportStateActioned); err != nil {
+		t.Fatalf("HandleReports(actioned) err: %v", err)
+	}
+	if !videoHidden(t, 1) {
+		t.Fatal("video not hidden after action")
+	}
+
+	report, _, err = CreateReport(ctx, 3, model.ReportTargetVideo, 1, model.ReportReasonOther, "")
+	if err != nil {
+		t.Fatalf("CreateReport err: %v", err)
+	}
+	if _, err = HandleReports(ctx, report.ID, 1, model.ReportStateDismissed); err != nil {
+		t.Fatalf("HandleReports(dismissed) err: %v", err)
+	}
+	if !videoHidden(t, 1) {
+		t.Error("actioned video restored by a later dismissal")
+	}
+}
+
+// 送审隐藏未读消息后驳回 接收者的未读消息数与会话最近一条消息一并恢复
+func TestDismissRestoresMessage(t *testing.T) {
+	openTestDB(t)
+	createTestUsers(t, 2)
+	ctx := context.Background()
+	if err := _db.Create(&model.Message{ID: 1, FromUserID: 1, ToUserID: 2, Content: "hi"}).Error; err != nil {
+		t.Fatalf("create message err: %v", err)
+	}
+	if err := _db.Create(&model.Conversation{User1ID: 1, User2ID: 2, LastMessageID: 1, User2Unread: 1}).Error; err != nil {
+		t.Fatalf("create conversation err: %v", err)
+	}
+	conversation := func() (conversation model.Conversation) {
+		_db.Model(&model.Conversation{}).Where("user1_id=? AND user2_id=?", 1, 2).First(&conversation)
+		return conversation
+	}
+
+	report, err := CreateReview(ctx, model.ReportTargetMessage, 1, "")
+	if err != nil {
+		t.Fatalf("CreateReview err: %v", err)
+	}
+	if c := conversation(); c.User2Unread != 0 || c.LastMessageID != 0 {
+		t.Fatalf("after review: unread = %d, last message = %d, want 0, 0", c.User2Unread, c.LastMessageID)
+	}
+
+	if _, err = HandleReports(ctx, report.ID, 1, model.ReportStateDismissed); err != nil {
+		t.Fatalf("HandleReports err: %v", err)
+	}
+	if c := conversation(); c.User2Unread != 1 || c.LastMessageID != 1 {
+		t.Errorf("after dismissal: unread = %d, last message = %d, want 1, 1", c.User2Unread, c.LastMessageID)
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 创建转发 (同一用户对同一视频仅可转发一次)
+func CreateRepost(ctx context.Context, userID uint, videoID uint, content string) (repost *model.Repost, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		repost = &model.Repost{Content: content, UserID: userID, VideoID: videoID}
+		video := &model.Video{ID: videoID}
+
+		var results []model.Repost
+		err2 := tx.Model(&model.Repost{}).Select("id").Where("user_id=? AND video_id=?", userID, videoID).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复转发
+			return ErrorRecordExists
+		}
+
+		err2 = tx.Model(&model.Repost{}).Create(repost).Error
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(video).Update("RepostsCount", gorm.Expr("reposts_count+?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repost, nil
+}
+
+// 删除转发 (永久删除以允许再次转发)
+func DeleteRepost(ctx context.Context, userID uint, videoID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		video := &model.Video{ID: videoID}
+
+		result := tx.Model(&model.Repost{}).Unscoped().Where("user_id=? AND video_id=?", userID, videoID).Delete(&model.Repost{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 { // 不允许凭空删除
+			return ErrorRecordNotExists
+		}
+
+		err2 := tx.Model(video).Update("RepostsCount", gorm.Expr("reposts_count-?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 根据转发用户ID和(创建时间, ID)复合游标倒序查找转发列表 beforeID为0时包含createdAt时刻的全部转发 (select: *)
+func FindRepostsByCreatedAt(ctx context.Context, userIDs []uint, createdAt int64, beforeID uint, num int) (reposts []model.Repost, err error) {
+	DB := _db.WithContext(ctx)
+	if len(userIDs) == 0 {
+		return reposts, nil
+	}
+	stop := time.Unix(createdAt, 0)
+	cursor := DB.Where("created_at<=?", stop)
+	if beforeID != 0 {
+		cursor = DB.Where("created_at<?", stop).Or("created_at=? AND id<?", stop, beforeID)
+	}
+	err = DB.Model(&model.Repost{}).Where("user_id IN ?", userIDs).Where(cursor).Order("created_at desc, id desc").Limit(num).Find(&reposts).Error
+	if err != nil {
+		return reposts, err
+	}
+	return reposts, nil
+}
@@ -0,0 +1,374 @@
+package db
+
+import (
+	"douyin/repo/internal/db/model"
+
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 将视频移入回收站(软删除并记录回收站条目 条目归属视频作者)
+func TrashVideo(ctx context.Context, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var video model.Video
+		err2 := tx.Model(&model.Video{}).Select("title", "author_id").Where("id=?", id).First(&video).Error // 须在删除前读取
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = deleteVideo(tx, id, false)
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Create(&model.Trash{OwnerID: video.AuthorID, TargetType: model.TrashTargetVideo, TargetID: id, Summary: video.Title}).Error
+	})
+}
+
+// 将评论及其全部回复移入回收站(软删除并记录回收站条目 条目归属评论作者) (返回被删除的评论 其中首条为目标评论 select: ID, AuthorID, VideoID, RootID)
+func TrashComment(ctx context.Context, id uint) (deleted []model.Comment, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var comment model.Comment
+		err2 := tx.Model(&model.Comment{}).Select("content", "author_id").Where("id=?", id).First(&comment).Error // 须在删除前读取
+		if err2 != nil {
+			return err2
+		}
+
+		deleted, err2 = deleteComment(tx, id, false)
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Create(&model.Trash{OwnerID: comment.AuthorID, TargetType: model.TrashTargetComment, TargetID: id, Summary: comment.Content}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// 根据内容作者ID查找回收站条目(仅含since之后删除的条目 按删除时间倒序)
+func FindUserTrash(ctx context.Context, ownerID uint, since time.Time, offset int, num int) (items []model.Trash, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Trash{}).Where("owner_id=? AND created_at>=?", ownerID, since).Order("created_at desc, id desc").Offset(offset).Limit(num).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// 查找before之前删除的回收站条目(按删除时间正序)
+func FindExpiredTrash(ctx context.Context, before time.Time, num int) (items []model.Trash, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Trash{}).Where("created_at<?", before).Order("created_at, id").Limit(num).Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// 恢复回收站条目(仅限内容作者本人且删除时间在since之后) 恢复评论时返回被恢复的评论 其中首条为目标评论 (select: ID, AuthorID, VideoID, RootID)
+func RestoreTrash(ctx context.Context, id uint, ownerID uint, since time.Time) (trash *model.Trash, restored []model.Comment, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.Trash
+		err2 := tx.Model(&model.Trash{}).Where("id=? AND owner_id=? AND created_at>=?", id, ownerID, since).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不存在或已过期
+			return ErrorRecordNotExists
+		}
+		trash = &results[0]
+
+		switch trash.TargetType {
+		case model.TrashTargetVideo:
+			restored = nil
+			err2 = restoreVideo(tx, trash.TargetID)
+		case model.TrashTargetComment:
+			restored, err2 = restoreComment(tx, trash.TargetID)
+		default:
+			err2 = ErrorInvalidTarget
+		}
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Delete(&model.Trash{}, trash.ID).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return trash, restored, nil
+}
+
+// 在事务中恢复软删除的视频
+func restoreVideo(tx *gorm.DB, id uint) (err error) {
+	var results []model.Video
+	err = tx.Unscoped().Model(&model.Video{}).Select("id", "author_id", "is_draft").Where("id=? AND deleted_at IS NOT NULL", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return ErrorRecordNotExists
+	}
+
+	err = tx.Unscoped().Model(&model.Video{}).Where("id=?", id).Update("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+
+	if !results[0].IsDraft { // 草稿不计入作品数
+		err = tx.Model(&model.User{ID: results[0].AuthorID}).Update("WorksCount", gorm.Expr("works_count+?", 1)).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 在事务中恢复软删除的评论及与其一并删除的回复 返回被恢复的评论 其中首条为目标评论 (select: ID, AuthorID, VideoID, RootID)
+func restoreComment(tx *gorm.DB, id uint) (restored []model.Comment, err error) {
+	var results []model.Comment
+	err = tx.Unscoped().Model(&model.Comment{}).Select("id", "author_id", "video_id", "parent_id", "root_id", "deleted_at").Where("id=? AND deleted_at IS NOT NULL", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrorRecordNotExists
+	}
+	target := results[0]
+	restored = append(restored, target)
+
+	// 所属视频与所回复的评论须仍存在
+	var count int64
+	err = tx.Model(&model.Video{}).Where("id=?", target.VideoID).Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrorRestoreConflict
+	}
+	if target.ParentID != 0 {
+		err = tx.Model(&model.Comment{}).Where("id=?", target.ParentID).Count(&count).Error
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrorRestoreConflict
+		}
+	}
+
+	// 逐层查找与目标评论一并删除的回复(删除时间相同) 此前单独删除的回复不予恢复
+	frontier := []uint{id}
+	for len(frontier) > 0 {
+		var children []model.Comment
+		err = tx.Unscoped().Model(&model.Comment{}).Select("id", "author_id", "video_id", "root_id").Where("parent_id IN ? AND deleted_at=?", frontier, target.DeletedAt).Find(&children).Error
+		if err != nil {
+			return nil, err
+		}
+		frontier = frontier[:0]
+		for _, child := range children {
+			restored = append(restored, child)
+			frontier = append(frontier, child.ID)
+		}
+	}
+
+	ids := make([]uint, 0, len(restored))
+	authorCounts := make(map[uint]int) // 各作者被恢复的评论数
+	for _, comment := range restored {
+		ids = append(ids, comment.ID)
+		authorCounts[comment.AuthorID]++
+	}
+
+	err = tx.Unscoped().Model(&model.Comment{}).Where("id IN ?", ids).Update("deleted_at", nil).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for authorID, count := range authorCounts {
+		err = tx.Model(&model.User{ID: authorID}).Update("CommentsCount", gorm.Expr("comments_count+?", count)).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Model(&model.Video{ID: target.VideoID}).Update("CommentsCount", gorm.Expr("comments_count+?", len(restored))).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if target.RootID != 0 { // 恢复回复时更新所属顶层评论的回复数
+		err = tx.Model(&model.Comment{ID: target.RootID}).Update("RepliesCount", gorm.Expr("replies_count+?", len(restored))).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return restored, nil
+}
+
+// 彻底清除回收站中的评论及其全部回复(计数已在移入回收站时更新)
+func PurgeComment(ctx context.Context, trashID uint, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		// 逐层查找全部已删除的回复(含此前单独删除的回复)
+		var ids []uint
+		err2 := tx.Unscoped().Model(&model.Comment{}).Where("id=? AND deleted_at IS NOT NULL", id).Pluck("id", &ids).Error
+		if err2 != nil {
+			return err2
+		}
+		frontier := append([]uint(nil), ids...)
+		for len(frontier) > 0 {
+			var children []uint
+			err2 = tx.Unscoped().Model(&model.Comment{}).Where("parent_id IN ? AND deleted_at IS NOT NULL", frontier).Pluck("id", &children).Error
+			if err2 != nil {
+				return err2
+			}
+			ids = append(ids, children...)
+			frontier = children
+		}
+
+		if len(ids) > 0 { // 评论可能已被恢复或随视频一并清除
+			err2 = purgeComments(tx, ids)
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		return tx.Delete(&model.Trash{}, trashID).Error
+	})
+}
+
+// 彻底清除回收站中的视频及其全部关联记录 并更新相关计数 回收站条目保留至OSS对象移除后删除 (返回点赞数与评论数有变化的用户ID)
+func PurgeVideo(ctx context.Context, id uint) (favoriterIDs []uint, commenterIDs []uint, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		favoriterIDs, commenterIDs = nil, nil // 事务重试时重置
+
+		var results []model.Video
+		err2 := tx.Unscoped().Model(&model.Video{}).Select("id", "author_id").Where("id=? AND deleted_at IS NOT NULL", id).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 视频记录已在此前的清除任务中清除
+			return nil
+		}
+		authorID := results[0].AuthorID
+
+		// 点赞关系
+		err2 = tx.Table("favorite").Where("video_id=?", id).Pluck("user_id", &favoriterIDs).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(favoriterIDs) > 0 {
+			err2 = tx.Model(&model.User{}).Where("id IN ?", favoriterIDs).Update("FavoritesCount", gorm.Expr("favorites_count-?", 1)).Error
+			if err2 != nil {
+				return err2
+			}
+			err2 = tx.Model(&model.User{ID: authorID}).Update("FavoritedCount", gorm.Expr("favorited_count-?", len(favoriterIDs))).Error
+			if err2 != nil {
+				return err2
+			}
+			err2 = tx.Table("favorite").Where("video_id=?", id).Delete(nil).Error
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		// 评论(已软删除的评论计数已在删除时更新)
+		var comments []model.Comment
+		err2 = tx.Unscoped().Model(&model.Comment{}).Select("id", "author_id", "deleted_at").Where("video_id=?", id).Find(&comments).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(comments) > 0 {
+			ids := make([]uint, 0, len(comments))
+			authorCounts := make(map[uint]int) // 各作者被清除的未删除评论数
+			for _, comment := range comments {
+				ids = append(ids, comment.ID)
+				if !comment.DeletedAt.Valid {
+					authorCounts[comment.AuthorID]++
+				}
+			}
+			for commenterID, count := range authorCounts {
+				err2 = tx.Model(&model.User{ID: commenterID}).Update("CommentsCount", gorm.Expr("comments_count-?", count)).Error
+				if err2 != nil {
+					return err2
+				}
+				commenterIDs = append(commenterIDs, commenterID)
+			}
+			err2 = purgeComments(tx, ids)
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		// 转发
+		err2 = tx.Unscoped().Model(&model.Repost{}).Where("video_id=?", id).Delete(&model.Repost{}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		// 合集条目
+		var collectionIDs []uint
+		err2 = tx.Model(&model.CollectionItem{}).Where("video_id=?", id).Pluck("collection_id", &collectionIDs).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(collectionIDs) > 0 {
+			err2 = tx.Model(&model.Collection{}).Where("id IN ?", collectionIDs).Update("ItemsCount", gorm.Expr("items_count-?", 1)).Error
+			if err2 != nil {
+				return err2
+			}
+			err2 = tx.Model(&model.CollectionItem{}).Where("video_id=?", id).Delete(&model.CollectionItem{}).Error
+			if err2 != nil {
+				return err2
+			}
+		}
+
+		// 标题中的提及
+		err2 = tx.Model(&model.Mention{}).Where("target_type=? AND target_id=?", model.MentionTargetVideo, id).Delete(&model.Mention{}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return tx.Unscoped().Model(&model.Video{}).Where("id=?", id).Delete(&model.Video{}).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return favoriterIDs, commenterIDs, nil
+}
+
+// 删除回收站条目
+func DeleteTrash(ctx context.Context, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Delete(&model.Trash{}, id).Error
+}
+
+// 在事务中彻底删除评论及其关联记录(不更新计数)
+func purgeComments(tx *gorm.DB, ids []uint) (err error) {
+	err = tx.Table("comment_like").Where("comment_id IN ?", ids).Delete(nil).Error // 先行清除点赞关系以满足外键约束
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&model.Mention{}).Where("target_type=? AND target_id IN ?", model.MentionTargetComment, ids).Delete(&model.Mention{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&model.CommentRevision{}).Where("comment_id IN ?", ids).Delete(&model.CommentRevision{}).Error
+	if err != nil {
+		return err
+	}
+	err = tx.Model(&model.Trash{}).Where("target_type=? AND target_id IN ?", model.TrashTargetComment, ids).Delete(&model.Trash{}).Error // 其中评论的回收站条目一并失效
+	if err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&model.Comment{}).Where("id IN ?", ids).Delete(&model.Comment{}).Error
+}
@@ -11,6 +11,7 @@ import (
 
 // 自定义错误类型
 var ErrorSelfFollow = errors.New("禁止自己关注自己")
+var ErrorSelfBlock = errors.New("禁止自己拉黑自己")
 
 // 获取用户主键最大值
 func MaxUserID(ctx context.Context) (max uint, err error) {
@@ -56,18 +57,61 @@ func CheckUserLogin(ctx context.Context, username string, password string) (id u
 	return results[0].ID, true
 }
 
-// 读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature)
+// 读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature, SignatureHidden, IsPrivate)
 func ReadUserBasics(ctx context.Context, id uint) (user *model.User, err error) {
 	DB := _db.WithContext(ctx)
-	user = &model.User{}
-	err = DB.Model(&model.User{}).Select("id", "created_at", "updated_at", "username", "signature").Where("id=?", id).First(user).Error
-	return user, err
+	var results []model.User
+	err = DB.Model(&model.User{}).Select("id", "created_at", "updated_at", "username", "signature", "signature_hidden", "is_private").Where("id=?", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrorRecordNotExists
+	}
+	return &results[0], nil
+}
+
+// 编辑个人签名 (同时设置新签名的隐藏状态)
+func UpdateUserSignature(ctx context.Context, id uint, signature string, hidden bool) (err error) {
+	DB := _db.WithContext(ctx)
+	result := DB.Model(&model.User{}).Where("id=?", id).Updates(map[string]any{"signature": signature, "signature_hidden": hidden})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 { // 不允许凭空编辑
+		return ErrorRecordNotExists
+	}
+	return nil
+}
+
+// 设置账号是否私密
+func UpdateUserPrivacy(ctx context.Context, id uint, isPrivate bool) (err error) {
+	DB := _db.WithContext(ctx)
+	var results []model.User
+	err = DB.Model(&model.User{}).Select("id").Where("id=?", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 { // 不允许凭空编辑
+		return ErrorRecordNotExists
+	}
+	return DB.Model(&model.User{ID: id}).Update("is_private", isPrivate).Error
 }
 
-// 读取作品(视频)列表 (select: Works.ID)
+// 读取作品(视频)列表 (select: Works.ID) 不含草稿及隐藏视频
 func ReadUserWorks(ctx context.Context, id uint) (videos []model.Video, err error) {
 	DB := _db.WithContext(ctx)
-	err = DB.Model(&model.User{ID: id}).Select("id").Association("Works").Find(&videos)
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("is_draft=? AND is_hidden=?", false, false).Association("Works").Find(&videos)
+	if err != nil {
+		return videos, err
+	}
+	return videos, nil
+}
+
+// 读取草稿列表 (select: Works.ID, Works.IsHidden)
+func ReadUserDrafts(ctx context.Context, id uint) (videos []model.Video, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.User{ID: id}).Select("id", "is_hidden").Where("is_draft=?", true).Order("updated_at desc").Association("Works").Find(&videos)
 	if err != nil {
 		return videos, err
 	}
@@ -216,6 +260,74 @@ func CheckUserFavorites(ctx context.Context, id uint, videoID uint) (isFavorite
 	return err == nil && len(results) > 0
 }
 
+// 创建评论点赞关系
+func CreateUserLikes(ctx context.Context, id uint, commentID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		user := &model.User{ID: id}
+		comment := &model.Comment{ID: commentID}
+
+		var results []model.Comment
+		err2 := tx.Model(user).Select("id").Where("id=?", commentID).Limit(1).Association("Likes").Find(&results)
+		if err2 != nil {
+			return err2
+		}
+		if len(results) > 0 { // 不允许重复创建
+			return ErrorRecordExists
+		}
+
+		err2 = tx.Model(user).Association("Likes").Append(comment)
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(comment).Update("LikedCount", gorm.Expr("liked_count+?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 删除评论点赞关系
+func DeleteUserLikes(ctx context.Context, id uint, commentID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		user := &model.User{ID: id}
+		comment := &model.Comment{ID: commentID}
+
+		var results []model.Comment
+		err2 := tx.Model(user).Select("id").Where("id=?", commentID).Limit(1).Association("Likes").Find(&results)
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许凭空删除
+			return ErrorRecordNotExists
+		}
+
+		err2 = tx.Model(user).Association("Likes").Delete(comment)
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(comment).Update("LikedCount", gorm.Expr("liked_count-?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 检查评论点赞关系
+func CheckUserLikes(ctx context.Context, id uint, commentID uint) (isLiked bool) {
+	DB := _db.WithContext(ctx)
+	var results []model.Comment
+	err := DB.Model(&model.User{ID: id}).Select("id").Where("id=?", commentID).Limit(1).Association("Likes").Find(&results)
+	return err == nil && len(results) > 0
+}
+
 // 读取评论列表 (select: Comments.ID)
 func ReadUserComments(ctx context.Context, id uint) (comments []model.Comment, err error) {
 	DB := _db.WithContext(ctx)
@@ -328,6 +440,16 @@ func ReadUserFollows(ctx context.Context, id uint) (users []model.User, err erro
 	return users, nil
 }
 
+// 读取朋友(互相关注的用户)ID列表
+func ReadUserFriends(ctx context.Context, id uint) (friendIDs []uint, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Table("follow AS f1").Joins("JOIN follow AS f2 ON f2.user_id=f1.follow_id AND f2.follow_id=f1.user_id").Where("f1.user_id=?", id).Pluck("f1.follow_id", &friendIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return friendIDs, nil
+}
+
 // 读取关注(用户)数量
 func CountUserFollows(ctx context.Context, id uint) (count int64) {
 	DB := _db.WithContext(ctx)
@@ -385,3 +507,99 @@ func CountUserMessages(ctx context.Context, id uint) (count int64) {
 	DB := _db.WithContext(ctx)
 	return DB.Model(&model.User{ID: id}).Association("Messages").Count()
 }
+
+// 批量读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature, SignatureHidden, IsPrivate) 未找到的用户不包含在结果中
+func ReadUserBasicsBatch(ctx context.Context, ids []uint) (users []model.User, err error) {
+	DB := _db.WithContext(ctx)
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err = DB.Model(&model.User{}).Select("id", "created_at", "updated_at", "username", "signature", "signature_hidden", "is_private").Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// 批量读取作品(视频)数量
+func CountUserWorksBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "works_count", ids)
+}
+
+// 批量读取点赞(视频)数量
+func CountUserFavoritesBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "favorites_count", ids)
+}
+
+// 批量读取获赞数量
+func CountUserFavoritedBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "favorited_count", ids)
+}
+
+// 批量读取评论数量
+func CountUserCommentsBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "comments_count", ids)
+}
+
+// 批量读取关注(用户)数量
+func CountUserFollowsBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "follows_count", ids)
+}
+
+// 批量读取粉丝(用户)数量
+func CountUserFollowersBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.User{}, "followers_count", ids)
+}
+
+// 批量检查点赞关系 结果中仅包含已点赞的视频ID
+func CheckUserFavoritesBatch(ctx context.Context, id uint, videoIDs []uint) (isFavorite map[uint]bool, err error) {
+	DB := _db.WithContext(ctx)
+	isFavorite = make(map[uint]bool, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return isFavorite, nil
+	}
+	var results []model.Video
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("id IN ?", videoIDs).Association("Favorites").Find(&results)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		isFavorite[result.ID] = true
+	}
+	return isFavorite, nil
+}
+
+// 批量检查评论点赞关系 结果中仅包含已点赞的评论ID
+func CheckUserLikesBatch(ctx context.Context, id uint, commentIDs []uint) (isLiked map[uint]bool, err error) {
+	DB := _db.WithContext(ctx)
+	isLiked = make(map[uint]bool, len(commentIDs))
+	if len(commentIDs) == 0 {
+		return isLiked, nil
+	}
+	var results []model.Comment
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("id IN ?", commentIDs).Association("Likes").Find(&results)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		isLiked[result.ID] = true
+	}
+	return isLiked, nil
+}
+
+// 批量检查关注关系 结果中仅包含已关注的用户ID
+func CheckUserFollowsBatch(ctx context.Context, id uint, followIDs []uint) (isFollowing map[uint]bool, err error) {
+	DB := _db.WithContext(ctx)
+	isFollowing = make(map[uint]bool, len(followIDs))
+	if len(followIDs) == 0 {
+		return isFollowing, nil
+	}
+	var results []model.User
+	err = DB.Model(&model.User{ID: id}).Select("id").Where("id IN ?", followIDs).Association("Follows").Find(&results)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.ID != id { // 默认自己不关注自己
+			isFollowing[result.ID] = true
+		}
+	}
+	return isFollowing, nil
+}
@@ -45,62 +45,93 @@ func CreateVideo(ctx context.Context, authorID uint, title string) (video *model
 func DeleteVideo(ctx context.Context, id uint, permanently bool) (err error) {
 	DB := _db.WithContext(ctx)
 	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
-		video := &model.Video{ID: id}
+		return deleteVideo(tx, id, permanently)
+	})
+}
 
-		var results []model.Video
-		err2 := tx.Model(&model.Video{}).Select("id").Where("id=?", id).Limit(1).Find(&results).Error
-		if err2 != nil {
-			return err2
-		}
-		if len(results) == 0 { // 不允许凭空删除
-			return ErrorRecordNotExists
-		}
+// 在事务中删除视频
+func deleteVideo(tx *gorm.DB, id uint, permanently bool) (err error) {
+	video := &model.Video{ID: id}
 
-		var authorID uint
-		err2 = tx.Model(video).Select("author_id").Scan(&authorID).Error
-		if err2 != nil {
-			return err2
-		}
-		author := &model.User{ID: authorID}
+	var results []model.Video
+	err = tx.Model(&model.Video{}).Select("id").Where("id=?", id).Limit(1).Find(&results).Error
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 { // 不允许凭空删除
+		return ErrorRecordNotExists
+	}
 
-		if permanently {
-			err2 = tx.Model(&model.Video{}).Unscoped().Delete(video).Error
-		} else {
-			err2 = tx.Model(&model.Video{}).Delete(video).Error
-		}
-		if err2 != nil {
-			return err2
-		}
+	var authorID uint
+	err = tx.Model(video).Select("author_id").Scan(&authorID).Error
+	if err != nil {
+		return err
+	}
+	author := &model.User{ID: authorID}
 
-		err2 = tx.Model(author).Update("WorksCount", gorm.Expr("works_count-?", 1)).Error
-		if err2 != nil {
-			return err2
+	var isDraft bool
+	err = tx.Model(video).Select("is_draft").Scan(&isDraft).Error
+	if err != nil {
+		return err
+	}
+
+	if permanently {
+		err = tx.Model(&model.Video{}).Unscoped().Delete(video).Error
+	} else {
+		err = tx.Model(&model.Video{}).Delete(video).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	if !isDraft { // 草稿不计入作品数
+		err = tx.Model(author).Update("WorksCount", gorm.Expr("works_count-?", 1)).Error
+		if err != nil {
+			return err
 		}
+	}
 
-		return nil
-	})
+	return nil
 }
 
-// 根据创建时间查找视频列表(num==-1时取消数量限制) (select: ID, CreatedAt)
+// 根据创建时间查找视频列表(num==-1时取消数量限制 不含草稿及隐藏视频) (select: ID, CreatedAt)
 func FindVideosByCreatedAt(ctx context.Context, createdAt int64, forward bool, num int) (videos []model.Video, err error) {
 	DB := _db.WithContext(ctx)
 	stop := time.Unix(createdAt, 0)
 	if forward {
-		err = DB.Model(&model.Video{}).Select("id", "created_at").Where("created_at>?", stop).Order("created_at").Limit(num).Find(&videos).Error
+		err = DB.Model(&model.Video{}).Select("id", "created_at").Where("is_draft=? AND is_hidden=?", false, false).Where("created_at>?", stop).Order("created_at").Limit(num).Find(&videos).Error
 	} else {
-		err = DB.Model(&model.Video{}).Select("id", "created_at").Where("created_at<?", stop).Order("created_at desc").Limit(num).Find(&videos).Error
+		err = DB.Model(&model.Video{}).Select("id", "created_at").Where("is_draft=? AND is_hidden=?", false, false).Where("created_at<?", stop).Order("created_at desc").Limit(num).Find(&videos).Error
+	}
+	if err != nil {
+		return videos, err
+	}
+	return videos, nil
+}
+
+// 根据作者ID和(创建时间, ID)复合游标倒序查找视频列表(不含草稿及隐藏视频) beforeID为0时包含createdAt时刻的全部视频 (select: ID, CreatedAt)
+func FindVideosByAuthorsAndCreatedAt(ctx context.Context, authorIDs []uint, createdAt int64, beforeID uint, num int) (videos []model.Video, err error) {
+	DB := _db.WithContext(ctx)
+	if len(authorIDs) == 0 {
+		return videos, nil
 	}
+	stop := time.Unix(createdAt, 0)
+	cursor := DB.Where("created_at<=?", stop)
+	if beforeID != 0 {
+		cursor = DB.Where("created_at<?", stop).Or("created_at=? AND id<?", stop, beforeID)
+	}
+	err = DB.Model(&model.Video{}).Select("id", "created_at").Where("author_id IN ?", authorIDs).Where("is_draft=? AND is_hidden=?", false, false).Where(cursor).Order("created_at desc, id desc").Limit(num).Find(&videos).Error
 	if err != nil {
 		return videos, err
 	}
 	return videos, nil
 }
 
-// 读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID)
+// 读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID, IsDraft, IsHidden, PinnedCommentID, CommentPolicy)
 func ReadVideoBasics(ctx context.Context, id uint) (video *model.Video, err error) {
 	DB := _db.WithContext(ctx)
 	video = &model.Video{}
-	err = DB.Model(&model.Video{}).Select("id", "created_at", "updated_at", "title", "author_id").Where("id=?", id).First(video).Error
+	err = DB.Model(&model.Video{}).Select("id", "created_at", "updated_at", "title", "author_id", "is_draft", "is_hidden", "pinned_comment_id", "comment_policy").Where("id=?", id).First(video).Error
 	if err != nil {
 		return nil, err
 	}
@@ -154,3 +185,128 @@ func CheckVideoComments(ctx context.Context, id uint, commentID uint) (isIts boo
 	err := DB.Model(&model.Video{ID: id}).Select("id").Where("id=?", commentID).Limit(1).Association("Comments").Find(&results)
 	return err == nil && len(results) > 0
 }
+
+// 批量读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID, IsDraft, IsHidden, PinnedCommentID, CommentPolicy) 未找到的视频不包含在结果中
+func ReadVideoBasicsBatch(ctx context.Context, ids []uint) (videos []model.Video, err error) {
+	DB := _db.WithContext(ctx)
+	if len(ids) == 0 {
+		return videos, nil
+	}
+	err = DB.Model(&model.Video{}).Select("id", "created_at", "updated_at", "title", "author_id", "is_draft", "is_hidden", "pinned_comment_id", "comment_policy").Where("id IN ?", ids).Find(&videos).Error
+	return videos, err
+}
+
+// 批量读取点赞(用户)数量
+func CountVideoFavoritedBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.Video{}, "favorited_count", ids)
+}
+
+// 批量读取评论数量
+func CountVideoCommentsBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.Video{}, "comments_count", ids)
+}
+
+// 读取转发数量
+func CountVideoReposts(ctx context.Context, id uint) (count int64) {
+	DB := _db.WithContext(ctx)
+	err := DB.Model(&model.Video{ID: id}).Select("RepostsCount").Scan(&count).Error
+	if err != nil {
+		return -1 // 出错
+	}
+	return count
+}
+
+// 批量读取转发数量
+func CountVideoRepostsBatch(ctx context.Context, ids []uint) (counts map[uint]int64, err error) {
+	return countBatch(ctx, &model.Video{}, "reposts_count", ids)
+}
+
+// 创建草稿 (不计入作品数)
+func CreateDraft(ctx context.Context, authorID uint, title string) (video *model.Video, err error) {
+	DB := _db.WithContext(ctx)
+	video = &model.Video{Title: title, AuthorID: authorID, IsDraft: true}
+	err = DB.Model(&model.Video{}).Create(video).Error
+	if err != nil {
+		return nil, err
+	}
+	return video, nil
+}
+
+// 编辑草稿标题
+func UpdateDraftTitle(ctx context.Context, id uint, title string) (err error) {
+	DB := _db.WithContext(ctx)
+	result := DB.Model(&model.Video{}).Where("id=? AND is_draft=?", id, true).Update("title", title)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 { // 不允许凭空编辑
+		return ErrorRecordNotExists
+	}
+	return nil
+}
+
+// 标记视频已有自定义封面
+func SetVideoCustomCover(ctx context.Context, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Model(&model.Video{}).Where("id=?", id).Update("has_custom_cover", true).Error
+}
+
+// 检查视频是否已有自定义封面
+func CheckVideoCustomCover(ctx context.Context, id uint) (hasCustomCover bool, err error) {
+	DB := _db.WithContext(ctx)
+	err = DB.Model(&model.Video{}).Select("has_custom_cover").Where("id=?", id).Scan(&hasCustomCover).Error
+	return hasCustomCover, err
+}
+
+// 发布草稿 (发布时间更新为当前时间)
+func PublishDraft(ctx context.Context, id uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		var results []model.Video
+		err2 := tx.Model(&model.Video{}).Select("id", "author_id").Where("id=? AND is_draft=?", id, true).Limit(1).Find(&results).Error
+		if err2 != nil {
+			return err2
+		}
+		if len(results) == 0 { // 不允许凭空发布
+			return ErrorRecordNotExists
+		}
+		author := &model.User{ID: results[0].AuthorID}
+
+		err2 = tx.Model(&model.Video{ID: id}).Updates(map[string]any{"is_draft": false, "created_at": time.Now()}).Error
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = tx.Model(author).Update("WorksCount", gorm.Expr("works_count+?", 1)).Error
+		if err2 != nil {
+			return err2
+		}
+
+		return nil
+	})
+}
+
+// 置顶视频下的顶层评论(commentID为0时取消置顶) 被置顶的评论须处于该视频下且未被隐藏
+func UpdateVideoPinnedComment(ctx context.Context, id uint, commentID uint) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Transaction(func(tx *gorm.DB) error { // 使用事务
+		if commentID != 0 {
+			var results []model.Comment
+			err2 := tx.Model(&model.Comment{}).Select("id").Where("id=? AND video_id=? AND root_id=? AND is_hidden=?", commentID, id, 0, false).Limit(1).Find(&results).Error
+			if err2 != nil {
+				return err2
+			}
+			if len(results) == 0 { // 不允许凭空置顶
+				return ErrorRecordNotExists
+			}
+		}
+
+		return tx.Model(&model.Video{}).Where("id=?", id).Update("pinned_comment_id", commentID).Error
+	})
+}
+
+// 设置视频评论权限
+func UpdateVideoCommentPolicy(ctx context.Context, id uint, policy int) (err error) {
+	DB := _db.WithContext(ctx)
+	return DB.Model(&model.Video{}).Where("id=?", id).Update("comment_policy", policy).Error
+}
@@ -3,16 +3,24 @@ package oss
 import (
 	"douyin/conf"
 
+	"bytes"
 	"context"
 	"errors"
+	"image"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/disintegration/imaging"
 )
 
 // 自定义错误类型
 var ErrorNotSupported = errors.New("OSS不支持该操作")
+var ErrorImageTooLarge = errors.New("图片文件或尺寸过大")
+
+const maxImageSize = 10 << 20  // 上传图片的最大字节数(10MiB)
+const maxImageDimension = 8192 // 上传图片的最大边长(像素)
 
 // 自定义云处理操作类型
 var OpUpdateCover = 1 // 云切取封面
@@ -53,3 +61,22 @@ func InitOSS() {
 		panic(err)
 	}
 }
+
+// 读取并解码上传的图片 限制读取的字节数并在解码前检查尺寸 防止超大图片在解码时耗尽内存
+func decodeImage(imageStream io.Reader, opts ...imaging.DecodeOption) (img image.Image, err error) {
+	data, err := io.ReadAll(io.LimitReader(imageStream, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, ErrorImageTooLarge
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension {
+		return nil, ErrorImageTooLarge
+	}
+	return imaging.Decode(bytes.NewReader(data), opts...)
+}
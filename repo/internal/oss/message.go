@@ -0,0 +1,76 @@
+package oss
+
+import (
+	"douyin/utility"
+
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/disintegration/imaging"
+)
+
+// 自定义对象扩展名 需包含"."
+const messageImageExt = ".jpg"
+
+// 获取消息图片对象在存储桶内所用名称
+func getMessageImageObjectName(objectID string) (imageName string) {
+	return "message/" + objectID + "_image" + messageImageExt // 模拟message文件夹
+}
+
+// 获取消息图片对象的短期外链
+func GetMessageImage(ctx context.Context, objectID string) (imageURL string, err error) {
+	// 消息图片对象名
+	imageName := getMessageImageObjectName(objectID)
+
+	// 获取URL
+	imageURL, err = _oss.getURL(ctx, imageName)
+	if err != nil {
+		utility.Logger().Errorf("_oss.getURL (message image) err: %v", err)
+		return "", err
+	}
+
+	return imageURL, nil
+}
+
+// 流式上传消息图片对象 自动转换为统一格式(同时去除图片元数据)
+func UploadMessageImageStream(ctx context.Context, objectID string, imageStream io.Reader) (err error) {
+	// 消息图片对象名
+	imageName := getMessageImageObjectName(objectID)
+
+	// 转换格式
+	img, err := decodeImage(imageStream, imaging.AutoOrientation(true))
+	if err != nil {
+		utility.Logger().Errorf("decodeImage (message image) err: %v", err)
+		return err
+	}
+	buf := bytes.NewBuffer(nil)
+	err = imaging.Encode(buf, img, imaging.JPEG) // 与messageImageExt保持一致
+	if err != nil {
+		utility.Logger().Errorf("imaging.Encode (message image) err: %v", err)
+		return err
+	}
+
+	// 上传
+	err = _oss.uploadStream(ctx, imageName, buf, int64(buf.Len()))
+	if err != nil {
+		utility.Logger().Errorf("_oss.uploadStream (message image) err: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// 移除消息图片对象
+func RemoveMessageImage(ctx context.Context, objectID string) (err error) {
+	// 消息图片对象名
+	imageName := getMessageImageObjectName(objectID)
+
+	err = _oss.remove(ctx, imageName)
+	if err != nil {
+		utility.Logger().Errorf("_oss.remove (message image) err: %v", err)
+		return err
+	}
+
+	return nil
+}
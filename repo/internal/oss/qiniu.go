@@ -101,7 +101,12 @@ func (q *qiNiuService) getURL(ctx context.Context, objectName string) (objectURL
 // SDK限制 context不可用
 func (q *qiNiuService) remove(ctx context.Context, objectName string) (err error) {
 	bucketManager := storage.NewBucketManager(q.mac, q.cfg)
-	return bucketManager.Delete(q.bucketName, objectName)
+	err = bucketManager.Delete(q.bucketName, objectName)
+	var errInfo *storage.ErrorInfo
+	if errors.As(err, &errInfo) && errInfo.Code == 612 { // 对象不存在时视为移除成功(与MinIO一致 以便重试)
+		return nil
+	}
+	return err
 }
 
 func (q *qiNiuService) uploadStream(ctx context.Context, objectName string, reader io.Reader, objectSize int64) (err error) {
@@ -4,11 +4,14 @@ import (
 	"douyin/emb"
 	"douyin/utility"
 
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/disintegration/imaging"
 )
 
 // 自定义错误类型
@@ -125,11 +128,16 @@ func UploadVideoStream(ctx context.Context, objectID string, videoStream io.Read
 	return nil
 }
 
-// 更新封面
-func UpdateCover(ctx context.Context, objectID string) (err error) {
+// 更新封面 skip返回true时放弃更新(在设定云处理及上传前各检查一次)
+func UpdateCover(ctx context.Context, objectID string, skip func() bool) (err error) {
 	// 视频对象与封面对象名
 	videoName, coverName := getVideoObjectName(objectID)
 
+	if skip() {
+		utility.Logger().Infof("UpdateCover info: %v - 已有自定义封面 跳过", coverName)
+		return nil
+	}
+
 	// 尝试使用云处理切取封面
 	err = _oss.setOperation(ctx, OpUpdateCover, videoName, coverName)
 	if err != nil {
@@ -162,6 +170,10 @@ func UpdateCover(ctx context.Context, objectID string) (err error) {
 	defer os.Remove(coverPath) // 不保证自动清理成功 但临时数据在本地 易于检测是否仍存在且可被直接覆写
 
 	// 上传
+	if skip() { // 切取期间作者已上传自定义封面
+		utility.Logger().Infof("UpdateCover info: %v - 已有自定义封面 跳过", coverName)
+		return nil
+	}
 	err = _oss.upload(ctx, coverName, coverPath)
 	if err != nil {
 		utility.Logger().Errorf("_oss.upload (cover) err: %v", err)
@@ -171,3 +183,50 @@ func UpdateCover(ctx context.Context, objectID string) (err error) {
 	utility.Logger().Infof("UpdateCover info: %v - 操作成功", coverName)
 	return nil
 }
+
+// 流式上传自定义封面对象 自动转换为封面格式
+func UploadCoverStream(ctx context.Context, objectID string, imageStream io.Reader) (err error) {
+	// 封面对象名
+	_, coverName := getVideoObjectName(objectID)
+
+	// 转换格式
+	img, err := decodeImage(imageStream)
+	if err != nil {
+		utility.Logger().Errorf("decodeImage (cover) err: %v", err)
+		return err
+	}
+	buf := bytes.NewBuffer(nil)
+	err = imaging.Encode(buf, img, imaging.PNG) // 与coverExt保持一致
+	if err != nil {
+		utility.Logger().Errorf("imaging.Encode (cover) err: %v", err)
+		return err
+	}
+
+	// 上传
+	err = _oss.uploadStream(ctx, coverName, buf, int64(buf.Len()))
+	if err != nil {
+		utility.Logger().Errorf("_oss.uploadStream (cover) err: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// 移除视频对象与封面对象
+func RemoveVideo(ctx context.Context, objectID string) (err error) {
+	// 视频对象与封面对象名
+	videoName, coverName := getVideoObjectName(objectID)
+
+	err = _oss.remove(ctx, videoName)
+	if err != nil {
+		utility.Logger().Errorf("_oss.remove (video) err: %v", err)
+		return err
+	}
+	err = _oss.remove(ctx, coverName)
+	if err != nil {
+		utility.Logger().Errorf("_oss.remove (cover) err: %v", err)
+		return err
+	}
+
+	return nil
+}
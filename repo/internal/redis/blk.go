@@ -0,0 +1,208 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserBlocks = "user:blk:"                // 后接三十六进制userID (节约key长度)
+const prefixUserBlockers = prefixUserBlocks + "by:" // 后接三十六进制blockID (节约key长度)
+
+const blocksLoadedBit = 0 // 位图第0位(用户ID不为0)标记已从数据库完整载入 未载入时位图中的0不可信 须回退至数据库
+
+// 从数据库完整载入用户的拉黑列表(并标记已载入)
+func LoadUserBlocks(ctx context.Context, userID uint, blockIDs []uint) (err error) {
+	return loadBits(ctx, prefixUserBlocks+strconv.FormatUint(uint64(userID), 36), blockIDs)
+}
+
+// 从数据库完整载入拉黑了用户的用户列表(并标记已载入)
+func LoadUserBlockers(ctx context.Context, blockID uint, userIDs []uint) (err error) {
+	return loadBits(ctx, prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36), userIDs)
+}
+
+// 检查拉黑关系位图是否已完整载入(isReverse为true时检查反向位图)
+func CheckUserBlocksLoaded(ctx context.Context, userID uint, isReverse bool) (loaded bool, err error) {
+	key := prefixUserBlocks + strconv.FormatUint(uint64(userID), 36)
+	if isReverse {
+		key = prefixUserBlockers + strconv.FormatUint(uint64(userID), 36)
+	}
+	value, err := _redis.GetBit(ctx, key, blocksLoadedBit).Result()
+	if err != nil {
+		return false, err
+	}
+	return value == 1, nil
+}
+
+// 删除双方的正反两向拉黑关系位图(写入失败时使缓存失效 以便下次读取时从数据库重新载入)
+func DelUserBlocks(ctx context.Context, userID uint, blockID uint) (err error) {
+	return _redis.Del(ctx,
+		prefixUserBlocks+strconv.FormatUint(uint64(userID), 36),
+		prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36),
+	).Err()
+}
+
+// 以给定的全部置位重写位图 并标记已载入
+func loadBits(ctx context.Context, key string, ids []uint) (err error) {
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		pipe.Del(ctx, key)
+		for _, id := range ids {
+			pipe.SetBit(ctx, key, int64(id), 1)
+		}
+		pipe.SetBit(ctx, key, blocksLoadedBit, 1)
+		return nil
+	})
+	return err
+}
+
+// 设置拉黑关系(同时写入正反两向主记录 拉黑关系采用直写策略 无变更记录)
+func SetUserBlocksBit(ctx context.Context, userID uint, blockID uint, isBlocking bool) (err error) {
+	if userID == blockID {
+		return ErrorSelfBlock // 默认禁止自己拉黑自己
+	}
+
+	value := 0
+	if isBlocking {
+		value = 1
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		pipe.SetBit(ctx, prefixUserBlocks+strconv.FormatUint(uint64(userID), 36), int64(blockID), value)
+		pipe.SetBit(ctx, prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36), int64(userID), value)
+		return nil
+	})
+	return err
+}
+
+// 读取拉黑关系
+func GetUserBlocks(ctx context.Context, userID uint, blockID uint, distrustProbability float32) (isBlocking bool, err error) {
+	if userID == blockID {
+		return false, nil // 默认自己不拉黑自己
+	}
+
+	distrusted, err := distrust(distrustProbability)
+	if err != nil {
+		return false, err
+	}
+	if distrusted {
+		return false, ErrorRedisNil // 返回查找结果为空, 以供触发一致性同步
+	}
+
+	// 从主记录正常读取
+	key := prefixUserBlocks + strconv.FormatUint(uint64(userID), 36)
+	var loadedCmd, valueCmd *redis.IntCmd
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		loadedCmd = pipe.GetBit(ctx, key, blocksLoadedBit)
+		valueCmd = pipe.GetBit(ctx, key, int64(blockID))
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if loadedCmd.Val() != 1 { // 位图尚未载入
+		return false, ErrorRedisNil
+	}
+	return valueCmd.Val() == 1, nil
+}
+
+// 批量设置拉黑关系(同时写入正反两向主记录 仅用于一致性同步时修正主记录)
+func SetUserBlocksBitBatch(ctx context.Context, userID uint, blockIDs []uint, isBlocking []bool) (err error) {
+	if len(blockIDs) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, blockID := range blockIDs {
+			if userID == blockID {
+				continue // 默认禁止自己拉黑自己
+			}
+			value := 0
+			if isBlocking[i] {
+				value = 1
+			}
+			pipe.SetBit(ctx, prefixUserBlocks+strconv.FormatUint(uint64(userID), 36), int64(blockID), value)
+			pipe.SetBit(ctx, prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36), int64(userID), value)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取拉黑关系(userID是否拉黑了各blockID) 返回值与blockIDs一一对应 exists为false时表示应触发一致性同步
+func GetUserBlocksBatch(ctx context.Context, userID uint, blockIDs []uint, distrustProbability float32) (isBlocking []bool, exists []bool, err error) {
+	return getBitsBatch(ctx, prefixUserBlocks+strconv.FormatUint(uint64(userID), 36), userID, blockIDs, distrustProbability)
+}
+
+// 批量设置反向拉黑关系(各userID是否拉黑了blockID 同时写入正反两向主记录 仅用于一致性同步时修正主记录)
+func SetUserBlockersBitBatch(ctx context.Context, blockID uint, userIDs []uint, isBlocked []bool) (err error) {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, userID := range userIDs {
+			if userID == blockID {
+				continue // 默认禁止自己拉黑自己
+			}
+			value := 0
+			if isBlocked[i] {
+				value = 1
+			}
+			pipe.SetBit(ctx, prefixUserBlocks+strconv.FormatUint(uint64(userID), 36), int64(blockID), value)
+			pipe.SetBit(ctx, prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36), int64(userID), value)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取反向拉黑关系(各userID是否拉黑了blockID) 返回值与userIDs一一对应 exists为false时表示应触发一致性同步
+func GetUserBlockersBatch(ctx context.Context, blockID uint, userIDs []uint, distrustProbability float32) (isBlocked []bool, exists []bool, err error) {
+	return getBitsBatch(ctx, prefixUserBlockers+strconv.FormatUint(uint64(blockID), 36), blockID, userIDs, distrustProbability)
+}
+
+// 批量读取位图中的关系 返回值与ids一一对应 与selfID相同的位置恒为false 位图尚未载入时其余位置均不存在
+func getBitsBatch(ctx context.Context, key string, selfID uint, ids []uint, distrustProbability float32) (values []bool, exists []bool, err error) {
+	values = make([]bool, len(ids))
+	exists = make([]bool, len(ids))
+	if len(ids) == 0 {
+		return values, exists, nil
+	}
+
+	var loadedCmd *redis.IntCmd
+	bitCmds := make([]*redis.IntCmd, 0, len(ids))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		loadedCmd = pipe.GetBit(ctx, key, blocksLoadedBit)
+		for _, id := range ids {
+			bitCmds = append(bitCmds, pipe.GetBit(ctx, key, int64(id)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, id := range ids {
+		if id == selfID {
+			exists[i] = true
+			continue
+		}
+		if loadedCmd.Val() != 1 { // 位图尚未载入
+			continue
+		}
+
+		distrusted, err := distrust(distrustProbability)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distrusted { // 视为查找结果为空, 以供触发一致性同步
+			continue
+		}
+
+		value, err := bitCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		values[i] = value == 1
+		exists[i] = true
+	}
+	return values, exists, nil
+}
@@ -71,6 +71,21 @@ func GetUserBasics(ctx context.Context, userID uint) (user *model.User, err erro
 	return user, nil
 }
 
+// 删除用户基本信息
+func DelUserBasics(ctx context.Context, userID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixUserBasics + strconv.FormatUint(uint64(userID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
 // 设置视频基本信息
 func SetVideoBasics(ctx context.Context, videoID uint, video *model.Video, expiration time.Duration) (err error) {
 	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
@@ -128,6 +143,36 @@ func GetVideoBasics(ctx context.Context, videoID uint) (video *model.Video, err
 	return video, nil
 }
 
+// 删除视频基本信息
+func DelVideoBasics(ctx context.Context, videoID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixVideoBasics + strconv.FormatUint(uint64(videoID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
+// 删除评论基本信息
+func DelCommentBasics(ctx context.Context, commentID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixCommentBasics + strconv.FormatUint(uint64(commentID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
 // 设置评论基本信息
 func SetCommentBasics(ctx context.Context, commentID uint, comment *model.Comment, expiration time.Duration) (err error) {
 	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
@@ -184,3 +229,120 @@ func GetCommentBasics(ctx context.Context, commentID uint) (comment *model.Comme
 	}
 	return comment, nil
 }
+
+// 批量读取哈希(使用管道) 返回值与keys一一对应 结果为空时表示缓存未命中
+func getHashBatch(ctx context.Context, keys []string) (cmds []*redis.MapStringStringCmd, err error) {
+	cmds = make([]*redis.MapStringStringCmd, 0, len(keys))
+	if len(keys) == 0 {
+		return cmds, nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for _, key := range keys {
+			cmds = append(cmds, pipe.HGetAll(ctx, key)) // 返回的错误只会表示异常, 不会为ErrorRedisNil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cmds, nil
+}
+
+// 批量设置哈希(使用管道)
+func setHashBatch(ctx context.Context, keys []string, values []any, expiration time.Duration) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, key := range keys {
+			pipe.HSet(ctx, key, values[i])
+			pipe.Expire(ctx, key, randomExpiration(expiration))
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量设置用户基本信息
+func SetUserBasicsBatch(ctx context.Context, userIDs []uint, users []*model.User, expiration time.Duration) (err error) {
+	values := make([]any, 0, len(users))
+	for _, user := range users {
+		values = append(values, user)
+	}
+	return setHashBatch(ctx, buildKeys(prefixUserBasics, userIDs), values, expiration)
+}
+
+// 批量读取用户基本信息 返回值与userIDs一一对应 为nil时表示缓存未命中
+func GetUserBasicsBatch(ctx context.Context, userIDs []uint) (users []*model.User, err error) {
+	cmds, err := getHashBatch(ctx, buildKeys(prefixUserBasics, userIDs))
+	if err != nil {
+		return nil, err
+	}
+	users = make([]*model.User, len(userIDs))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 { // 不存在
+			continue
+		}
+		user := &model.User{}
+		if cmd.Scan(user) == nil {
+			users[i] = user
+		}
+	}
+	return users, nil
+}
+
+// 批量设置视频基本信息
+func SetVideoBasicsBatch(ctx context.Context, videoIDs []uint, videos []*model.Video, expiration time.Duration) (err error) {
+	values := make([]any, 0, len(videos))
+	for _, video := range videos {
+		values = append(values, video)
+	}
+	return setHashBatch(ctx, buildKeys(prefixVideoBasics, videoIDs), values, expiration)
+}
+
+// 批量读取视频基本信息 返回值与videoIDs一一对应 为nil时表示缓存未命中
+func GetVideoBasicsBatch(ctx context.Context, videoIDs []uint) (videos []*model.Video, err error) {
+	cmds, err := getHashBatch(ctx, buildKeys(prefixVideoBasics, videoIDs))
+	if err != nil {
+		return nil, err
+	}
+	videos = make([]*model.Video, len(videoIDs))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 { // 不存在
+			continue
+		}
+		video := &model.Video{}
+		if cmd.Scan(video) == nil {
+			videos[i] = video
+		}
+	}
+	return videos, nil
+}
+
+// 批量设置评论基本信息
+func SetCommentBasicsBatch(ctx context.Context, commentIDs []uint, comments []*model.Comment, expiration time.Duration) (err error) {
+	values := make([]any, 0, len(comments))
+	for _, comment := range comments {
+		values = append(values, comment)
+	}
+	return setHashBatch(ctx, buildKeys(prefixCommentBasics, commentIDs), values, expiration)
+}
+
+// 批量读取评论基本信息 返回值与commentIDs一一对应 为nil时表示缓存未命中
+func GetCommentBasicsBatch(ctx context.Context, commentIDs []uint) (comments []*model.Comment, err error) {
+	cmds, err := getHashBatch(ctx, buildKeys(prefixCommentBasics, commentIDs))
+	if err != nil {
+		return nil, err
+	}
+	comments = make([]*model.Comment, len(commentIDs))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 { // 不存在
+			continue
+		}
+		comment := &model.Comment{}
+		if cmd.Scan(comment) == nil {
+			comments[i] = comment
+		}
+	}
+	return comments, nil
+}
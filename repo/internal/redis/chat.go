@@ -0,0 +1,63 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserChat = "chat:user:" // 后接三十六进制userID (节约key长度)
+
+// 聊天事件(用于多实例间分发)
+type ChatEvent struct {
+	UserID  uint   // 接收事件的用户ID
+	Payload string // 事件内容
+}
+
+// 聊天事件订阅者(每个实例共用一个连接 按需增减订阅的用户频道)
+type ChatSubscriber struct {
+	pubsub *redis.PubSub
+}
+
+// 发布聊天事件至用户频道
+func PublishUserChat(ctx context.Context, userID uint, payload string) (err error) {
+	return _redis.Publish(ctx, prefixUserChat+strconv.FormatUint(uint64(userID), 36), payload).Err()
+}
+
+// 创建聊天事件订阅者(初始时不订阅任何频道)
+func NewChatSubscriber(ctx context.Context) (subscriber *ChatSubscriber) {
+	return &ChatSubscriber{pubsub: _redis.Subscribe(ctx)}
+}
+
+// 订阅用户频道
+func (s *ChatSubscriber) Subscribe(ctx context.Context, userID uint) (err error) {
+	return s.pubsub.Subscribe(ctx, prefixUserChat+strconv.FormatUint(uint64(userID), 36))
+}
+
+// 取消订阅用户频道
+func (s *ChatSubscriber) Unsubscribe(ctx context.Context, userID uint) (err error) {
+	return s.pubsub.Unsubscribe(ctx, prefixUserChat+strconv.FormatUint(uint64(userID), 36))
+}
+
+// 获取聊天事件通道 订阅者关闭后通道随之关闭
+func (s *ChatSubscriber) Events() (events <-chan ChatEvent) {
+	ch := make(chan ChatEvent)
+	go func() {
+		defer close(ch)
+		for msg := range s.pubsub.Channel() {
+			userID, err := strconv.ParseUint(strings.TrimPrefix(msg.Channel, prefixUserChat), 36, 64)
+			if err != nil {
+				continue
+			}
+			ch <- ChatEvent{UserID: uint(userID), Payload: msg.Payload}
+		}
+	}()
+	return ch
+}
+
+// 关闭订阅者
+func (s *ChatSubscriber) Close() (err error) {
+	return s.pubsub.Close()
+}
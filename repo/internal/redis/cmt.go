@@ -4,12 +4,18 @@ import (
 	"context"
 	"strconv"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-const prefixUserComments = "user:cmt:"                          // 暂只用于构建其他前缀
-const prefixUserCommentsCount = prefixUserComments + "count:"   // 后接三十六进制userID (节约key长度)
-const prefixVideoComments = "video:cmt:"                        // 暂只用于构建其他前缀
-const prefixVideoCommentsCount = prefixVideoComments + "count:" // 后接三十六进制videoID (节约key长度)
+const prefixUserComments = "user:cmt:"                            // 暂只用于构建其他前缀
+const prefixUserCommentsCount = prefixUserComments + "count:"     // 后接三十六进制userID (节约key长度)
+const prefixVideoComments = "video:cmt:"                          // 暂只用于构建其他前缀
+const prefixVideoCommentsCount = prefixVideoComments + "count:"   // 后接三十六进制videoID (节约key长度)
+const prefixVideoCommentsList = prefixVideoComments + "list:"     // 后接三十六进制videoID 以评论ID为分值的顶层评论有序集合 (节约key长度)
+const prefixVideoCommentsHot = prefixVideoComments + "hot:"       // 后接三十六进制videoID 以热度为分值的顶层评论有序集合 (节约key长度)
+const prefixCommentReplies = "comment:rpl:"                       // 暂只用于构建其他前缀
+const prefixCommentRepliesCount = prefixCommentReplies + "count:" // 后接三十六进制commentID (节约key长度)
 
 // 设置用户评论数
 func SetUserCommentsCount(ctx context.Context, userID uint, count int64, expiration time.Duration) (err error) {
@@ -64,3 +70,162 @@ func DelVideoCommentsCount(ctx context.Context, videoID uint, maxWriteTime time.
 
 	return err
 }
+
+// 批量设置用户评论数
+func SetUserCommentsCountBatch(ctx context.Context, userIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserCommentsCount, userIDs), counts, expiration)
+}
+
+// 批量读取用户评论数 返回值与userIDs一一对应 exists为false时表示缓存未命中
+func GetUserCommentsCountBatch(ctx context.Context, userIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserCommentsCount, userIDs))
+}
+
+// 批量设置视频评论数
+func SetVideoCommentsCountBatch(ctx context.Context, videoIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixVideoCommentsCount, videoIDs), counts, expiration)
+}
+
+// 批量读取视频评论数 返回值与videoIDs一一对应 exists为false时表示缓存未命中
+func GetVideoCommentsCountBatch(ctx context.Context, videoIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixVideoCommentsCount, videoIDs))
+}
+
+// 设置评论回复数
+func SetCommentRepliesCount(ctx context.Context, commentID uint, count int64, expiration time.Duration) (err error) {
+	key := prefixCommentRepliesCount + strconv.FormatUint(uint64(commentID), 36)
+	return _redis.SetEx(ctx, key, count, randomExpiration(expiration)).Err()
+}
+
+// 读取评论回复数
+func GetCommentRepliesCount(ctx context.Context, commentID uint) (count int64, err error) {
+	key := prefixCommentRepliesCount + strconv.FormatUint(uint64(commentID), 36)
+	return _redis.Get(ctx, key).Int64()
+}
+
+// 删除评论回复数
+func DelCommentRepliesCount(ctx context.Context, commentID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixCommentRepliesCount + strconv.FormatUint(uint64(commentID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
+// 批量设置评论回复数
+func SetCommentRepliesCountBatch(ctx context.Context, commentIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixCommentRepliesCount, commentIDs), counts, expiration)
+}
+
+// 批量读取评论回复数 返回值与commentIDs一一对应 exists为false时表示缓存未命中
+func GetCommentRepliesCountBatch(ctx context.Context, commentIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixCommentRepliesCount, commentIDs))
+}
+
+// 设置视频顶层评论时间序列表(以评论ID为分值 其顺序即创建顺序)
+func SetVideoCommentsList(ctx context.Context, videoID uint, commentIDs []uint, expiration time.Duration) (err error) {
+	key := prefixVideoCommentsList + strconv.FormatUint(uint64(videoID), 36)
+	scores := make([]float64, len(commentIDs))
+	for i, commentID := range commentIDs {
+		scores[i] = float64(commentID)
+	}
+	return setRank(ctx, key, commentIDs, scores, expiration)
+}
+
+// 读取视频顶层评论时间序列表 从游标(评论ID 为0时表示从头开始 不含游标本身)起向新(forward为true)或向旧查找num条
+func GetVideoCommentsList(ctx context.Context, videoID uint, cursor uint, forward bool, num int) (commentIDs []uint, err error) {
+	args := redis.ZRangeArgs{
+		Key:     prefixVideoCommentsList + strconv.FormatUint(uint64(videoID), 36),
+		ByScore: true,
+		Rev:     !forward,
+		Count:   int64(num),
+	}
+	if forward {
+		args.Start, args.Stop = "("+strconv.FormatUint(uint64(cursor), 10), "+inf"
+	} else if cursor == 0 {
+		args.Start, args.Stop = "(0", "+inf"
+	} else {
+		args.Start, args.Stop = "(0", "("+strconv.FormatUint(uint64(cursor), 10)
+	}
+	return getRank(ctx, args)
+}
+
+// 删除视频顶层评论时间序列表
+func DelVideoCommentsList(ctx context.Context, videoID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixVideoCommentsList + strconv.FormatUint(uint64(videoID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
+// 仅在已存在时执行ZADD 防止向尚未同步的有序集合写入不完整的数据
+var addIfExistsScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if redis.call("EXISTS", key) == 1 then
+		redis.call("ZADD", key, ARGV[i*2-1], ARGV[i*2])
+	end
+end
+return 0
+`)
+
+// 将新的顶层评论加入视频顶层评论时间序列表与热度榜(均仅在已存在时加入)
+func AddVideoComment(ctx context.Context, videoID uint, commentID uint, hotScore float64) (err error) {
+	keys := []string{
+		prefixVideoCommentsList + strconv.FormatUint(uint64(videoID), 36),
+		prefixVideoCommentsHot + strconv.FormatUint(uint64(videoID), 36),
+	}
+	return addIfExistsScript.Run(ctx, _redis, keys, commentID, commentID, hotScore, commentID).Err()
+}
+
+// 更新视频顶层评论热度榜中已有评论的热度(热度须为正数)
+func UpdateVideoCommentHot(ctx context.Context, videoID uint, commentID uint, hotScore float64) (err error) {
+	key := prefixVideoCommentsHot + strconv.FormatUint(uint64(videoID), 36)
+	return _redis.ZAddXX(ctx, key, redis.Z{Score: hotScore, Member: commentID}).Err()
+}
+
+// 设置视频顶层评论热度榜(热度须为正数)
+func SetVideoCommentsHot(ctx context.Context, videoID uint, commentIDs []uint, scores []float64, expiration time.Duration) (err error) {
+	key := prefixVideoCommentsHot + strconv.FormatUint(uint64(videoID), 36)
+	return setRank(ctx, key, commentIDs, scores, expiration)
+}
+
+// 按热度从高到低分页读取视频顶层评论热度榜
+func GetVideoCommentsHot(ctx context.Context, videoID uint, offset int, num int) (commentIDs []uint, err error) {
+	return getRank(ctx, redis.ZRangeArgs{
+		Key:     prefixVideoCommentsHot + strconv.FormatUint(uint64(videoID), 36),
+		Start:   "(0",
+		Stop:    "+inf",
+		ByScore: true,
+		Rev:     true,
+		Offset:  int64(offset),
+		Count:   int64(num),
+	})
+}
+
+// 删除视频顶层评论热度榜
+func DelVideoCommentsHot(ctx context.Context, videoID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixVideoCommentsHot + strconv.FormatUint(uint64(videoID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
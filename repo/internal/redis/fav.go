@@ -139,6 +139,21 @@ func GetUserFavoritesCount(ctx context.Context, userID uint) (count int64, err e
 	return _redis.Get(ctx, key).Int64()
 }
 
+// 删除用户点赞数
+func DelUserFavoritesCount(ctx context.Context, userID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixUserFavoritesCount + strconv.FormatUint(uint64(userID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
 // 设置用户受赞数
 func SetUserFavoritedCount(ctx context.Context, userID uint, count int64, expiration time.Duration) (err error) {
 	key := prefixUserFavoritedCount + strconv.FormatUint(uint64(userID), 36)
@@ -151,6 +166,21 @@ func GetUserFavoritedCount(ctx context.Context, userID uint) (count int64, err e
 	return _redis.Get(ctx, key).Int64()
 }
 
+// 删除用户受赞数
+func DelUserFavoritedCount(ctx context.Context, userID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixUserFavoritedCount + strconv.FormatUint(uint64(userID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
 // 设置视频受赞数
 func SetVideoFavoritedCount(ctx context.Context, userID uint, count int64, expiration time.Duration) (err error) {
 	key := prefixVideoFavoritedCount + strconv.FormatUint(uint64(userID), 36)
@@ -162,3 +192,102 @@ func GetVideoFavoritedCount(ctx context.Context, userID uint) (count int64, err
 	key := prefixVideoFavoritedCount + strconv.FormatUint(uint64(userID), 36)
 	return _redis.Get(ctx, key).Int64()
 }
+
+// 批量设置用户点赞数
+func SetUserFavoritesCountBatch(ctx context.Context, userIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserFavoritesCount, userIDs), counts, expiration)
+}
+
+// 批量读取用户点赞数 返回值与userIDs一一对应 exists为false时表示缓存未命中
+func GetUserFavoritesCountBatch(ctx context.Context, userIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserFavoritesCount, userIDs))
+}
+
+// 批量设置用户受赞数
+func SetUserFavoritedCountBatch(ctx context.Context, userIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserFavoritedCount, userIDs), counts, expiration)
+}
+
+// 批量读取用户受赞数 返回值与userIDs一一对应 exists为false时表示缓存未命中
+func GetUserFavoritedCountBatch(ctx context.Context, userIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserFavoritedCount, userIDs))
+}
+
+// 批量设置视频受赞数
+func SetVideoFavoritedCountBatch(ctx context.Context, videoIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixVideoFavoritedCount, videoIDs), counts, expiration)
+}
+
+// 批量读取视频受赞数 返回值与videoIDs一一对应 exists为false时表示缓存未命中
+func GetVideoFavoritedCountBatch(ctx context.Context, videoIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixVideoFavoritedCount, videoIDs))
+}
+
+// 批量设置点赞关系(仅用于一致性同步时修正主记录)
+func SetUserFavoritesBitBatch(ctx context.Context, userID uint, videoIDs []uint, isFavorite []bool) (err error) {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	key := prefixUserFavorites + strconv.FormatUint(uint64(userID), 36)
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, videoID := range videoIDs {
+			value := 0
+			if isFavorite[i] {
+				value = 1
+			}
+			pipe.SetBit(ctx, key, int64(videoID), value)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取点赞关系 返回值与videoIDs一一对应 exists为false时表示应触发一致性同步
+func GetUserFavoritesBatch(ctx context.Context, userID uint, videoIDs []uint, distrustProbability float32) (isFavorite []bool, exists []bool, err error) {
+	isFavorite = make([]bool, len(videoIDs))
+	exists = make([]bool, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return isFavorite, exists, nil
+	}
+
+	key := prefixUserFavorites + strconv.FormatUint(uint64(userID), 36)
+	deltaCmds := make([]*redis.StringCmd, 0, len(videoIDs))
+	bitCmds := make([]*redis.IntCmd, 0, len(videoIDs))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for _, videoID := range videoIDs {
+			deltaKey := prefixUserFavoritesDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(videoID), 36)
+			deltaCmds = append(deltaCmds, pipe.Get(ctx, deltaKey))
+			bitCmds = append(bitCmds, pipe.GetBit(ctx, key, int64(videoID)))
+		}
+		return nil
+	})
+	if err != nil && err != ErrorRedisNil { // 变更记录不存在时将返回ErrorRedisNil
+		return nil, nil, err
+	}
+
+	for i := range videoIDs {
+		delta, err := deltaCmds[i].Bool()
+		if err == nil { // 若有变更记录存在则直接使用(此时禁用随机不信任缓存)
+			isFavorite[i] = delta
+			exists[i] = true
+			continue
+		}
+
+		distrusted, err := distrust(distrustProbability)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distrusted { // 视为查找结果为空, 以供触发一致性同步
+			continue
+		}
+
+		// 从主记录正常读取
+		value, err := bitCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		isFavorite[i] = value == 1
+		exists[i] = true
+	}
+	return isFavorite, exists, nil
+}
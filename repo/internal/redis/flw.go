@@ -156,3 +156,100 @@ func GetUserFollowersCount(ctx context.Context, followID uint) (count int64, err
 	key := prefixUserFollowersCount + strconv.FormatUint(uint64(followID), 36)
 	return _redis.Get(ctx, key).Int64()
 }
+
+// 批量设置用户关注数
+func SetUserFollowsCountBatch(ctx context.Context, userIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserFollowsCount, userIDs), counts, expiration)
+}
+
+// 批量读取用户关注数 返回值与userIDs一一对应 exists为false时表示缓存未命中
+func GetUserFollowsCountBatch(ctx context.Context, userIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserFollowsCount, userIDs))
+}
+
+// 批量设置用户粉丝数
+func SetUserFollowersCountBatch(ctx context.Context, followIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserFollowersCount, followIDs), counts, expiration)
+}
+
+// 批量读取用户粉丝数 返回值与followIDs一一对应 exists为false时表示缓存未命中
+func GetUserFollowersCountBatch(ctx context.Context, followIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserFollowersCount, followIDs))
+}
+
+// 批量设置关注关系(仅用于一致性同步时修正主记录)
+func SetUserFollowsBitBatch(ctx context.Context, userID uint, followIDs []uint, isFollowing []bool) (err error) {
+	if len(followIDs) == 0 {
+		return nil
+	}
+	key := prefixUserFollows + strconv.FormatUint(uint64(userID), 36)
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, followID := range followIDs {
+			if userID == followID {
+				continue // 默认禁止自己关注自己
+			}
+			value := 0
+			if isFollowing[i] {
+				value = 1
+			}
+			pipe.SetBit(ctx, key, int64(followID), value)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取关注关系 返回值与followIDs一一对应 exists为false时表示应触发一致性同步
+func GetUserFollowsBatch(ctx context.Context, userID uint, followIDs []uint, distrustProbability float32) (isFollowing []bool, exists []bool, err error) {
+	isFollowing = make([]bool, len(followIDs))
+	exists = make([]bool, len(followIDs))
+	if len(followIDs) == 0 {
+		return isFollowing, exists, nil
+	}
+
+	key := prefixUserFollows + strconv.FormatUint(uint64(userID), 36)
+	deltaCmds := make([]*redis.StringCmd, 0, len(followIDs))
+	bitCmds := make([]*redis.IntCmd, 0, len(followIDs))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for _, followID := range followIDs {
+			deltaKey := prefixUserFollowsDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(followID), 36)
+			deltaCmds = append(deltaCmds, pipe.Get(ctx, deltaKey))
+			bitCmds = append(bitCmds, pipe.GetBit(ctx, key, int64(followID)))
+		}
+		return nil
+	})
+	if err != nil && err != ErrorRedisNil { // 变更记录不存在时将返回ErrorRedisNil
+		return nil, nil, err
+	}
+
+	for i, followID := range followIDs {
+		if userID == followID { // 默认自己不关注自己
+			exists[i] = true
+			continue
+		}
+
+		delta, err := deltaCmds[i].Bool()
+		if err == nil { // 若有变更记录存在则直接使用(此时禁用随机不信任缓存)
+			isFollowing[i] = delta
+			exists[i] = true
+			continue
+		}
+
+		distrusted, err := distrust(distrustProbability)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distrusted { // 视为查找结果为空, 以供触发一致性同步
+			continue
+		}
+
+		// 从主记录正常读取
+		value, err := bitCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		isFollowing[i] = value == 1
+		exists[i] = true
+	}
+	return isFollowing, exists, nil
+}
@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserFriends = "user:frd:" // 后接三十六进制userID (节约key长度)
+
+// 设置朋友(互相关注的用户)集合(覆盖原有集合) 以ID 0作为哨兵成员 使空集合同样可被缓存以防止缓存穿透
+func SetUserFriends(ctx context.Context, userID uint, friendIDs []uint, expiration time.Duration) (err error) {
+	key := prefixUserFriends + strconv.FormatUint(uint64(userID), 36)
+	members := make([]any, 0, len(friendIDs)+1)
+	members = append(members, 0)
+	for _, friendID := range friendIDs {
+		members = append(members, friendID)
+	}
+
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		pipe.Del(ctx, key)
+		pipe.SAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, randomExpiration(expiration))
+		return nil
+	})
+	return err
+}
+
+// 读取朋友(互相关注的用户)集合 集合不存在时返回ErrorRedisNil
+func GetUserFriends(ctx context.Context, userID uint) (friendIDs []uint, err error) {
+	key := prefixUserFriends + strconv.FormatUint(uint64(userID), 36)
+	members, err := _redis.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 { // 集合不存在(存在的集合至少含有哨兵成员)
+		return nil, ErrorRedisNil
+	}
+
+	friendIDs = make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil || id == 0 { // 跳过哨兵成员
+			continue
+		}
+		friendIDs = append(friendIDs, uint(id))
+	}
+	return friendIDs, nil
+}
+
+// 删除朋友(互相关注的用户)集合
+func DelUserFriends(ctx context.Context, userID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixUserFriends + strconv.FormatUint(uint64(userID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
@@ -4,9 +4,12 @@ import (
 	"douyin/conf"
 	"douyin/repo/internal/db"
 
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +23,7 @@ const ErrorRedisTxFailed = redis.TxFailedErr // 创建乐观锁失败的别名
 var ErrorRecordExists = db.ErrorRecordExists
 var ErrorRecordNotExists = db.ErrorRecordNotExists
 var ErrorSelfFollow = db.ErrorSelfFollow
+var ErrorSelfBlock = db.ErrorSelfBlock
 
 const randomExpirationRatio = 0.1 // 随机延长过期时间的比例(防止缓存雪崩)
 const maxRetries = 3              // watch乐观锁等的最大重试次数
@@ -54,3 +58,115 @@ func InitRedis() {
 func randomExpiration(expiration time.Duration) (randomized time.Duration) {
 	return expiration + time.Duration(rand.Intn(int(float64(expiration)*randomExpirationRatio))).Abs()
 }
+
+// 批量构建key 后接三十六进制ID (节约key长度)
+func buildKeys(prefix string, ids []uint) (keys []string) {
+	keys = make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, prefix+strconv.FormatUint(uint64(id), 36))
+	}
+	return keys
+}
+
+// 批量读取计数 返回值与keys一一对应 exists为false时表示缓存未命中
+func getCountBatch(ctx context.Context, keys []string) (counts []int64, exists []bool, err error) {
+	counts = make([]int64, len(keys))
+	exists = make([]bool, len(keys))
+	if len(keys) == 0 {
+		return counts, exists, nil
+	}
+
+	values, err := _redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok { // 不存在时为nil
+			continue
+		}
+		count, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			continue // 视为未命中
+		}
+		counts[i] = count
+		exists[i] = true
+	}
+	return counts, exists, nil
+}
+
+// 批量设置计数(使用管道)
+func setCountBatch(ctx context.Context, keys []string, counts []int64, expiration time.Duration) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, key := range keys {
+			pipe.SetEx(ctx, key, counts[i], randomExpiration(expiration))
+		}
+		return nil
+	})
+	return err
+}
+
+// 随机不信任缓存(返回true时应视为缓存未命中)
+func distrust(distrustProbability float32) (distrusted bool, err error) {
+	switch {
+	case distrustProbability == 0:
+		// 强制信任缓存
+		return false, nil
+	case (distrustProbability > 0 && distrustProbability < 1):
+		// 随机不信任缓存
+		return rand.Intn(int(1/distrustProbability)) == 0, nil
+	case distrustProbability == 1:
+		// 强制不信任缓存
+		return true, nil
+	default:
+		return false, errors.New("distrustProbability必须在0-1之间")
+	}
+}
+
+// 设置ID有序集合(覆盖原有集合) 以ID 0作为分值为-1的哨兵成员 使空集合同样可被缓存以防止缓存穿透
+func setRank(ctx context.Context, key string, ids []uint, scores []float64, expiration time.Duration) (err error) {
+	members := make([]redis.Z, 0, len(ids)+1)
+	members = append(members, redis.Z{Score: -1, Member: 0})
+	for i, id := range ids {
+		members = append(members, redis.Z{Score: scores[i], Member: id})
+	}
+
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		pipe.Del(ctx, key)
+		pipe.ZAdd(ctx, key, members...)
+		pipe.Expire(ctx, key, randomExpiration(expiration))
+		return nil
+	})
+	return err
+}
+
+// 按范围读取ID有序集合 集合不存在时返回ErrorRedisNil
+func getRank(ctx context.Context, args redis.ZRangeArgs) (ids []uint, err error) {
+	var existsCmd *redis.IntCmd
+	var rangeCmd *redis.StringSliceCmd
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		existsCmd = pipe.Exists(ctx, args.Key)
+		rangeCmd = pipe.ZRangeArgs(ctx, args)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if existsCmd.Val() == 0 {
+		return nil, ErrorRedisNil
+	}
+
+	members := rangeCmd.Val()
+	ids = make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil || id == 0 { // 跳过哨兵成员
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
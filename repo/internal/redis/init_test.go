@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// 以内存Redis替换客户端
+func runTestRedis(t *testing.T) (server *miniredis.Miniredis) {
+	server = miniredis.RunT(t)
+	old := _redis
+	_redis = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_redis.Close()
+		_redis = old
+	})
+	return server
+}
+
+func TestCountBatch(t *testing.T) {
+	server := runTestRedis(t)
+	ctx := context.Background()
+
+	counts, exists, err := getCountBatch(ctx, nil)
+	if err != nil || len(counts) != 0 || len(exists) != 0 {
+		t.Errorf("getCountBatch(nil) = %v, %v, %v, want empty", counts, exists, err)
+	}
+	if err := setCountBatch(ctx, nil, nil, time.Minute); err != nil {
+		t.Errorf("setCountBatch(nil) = %v", err)
+	}
+
+	if err := setCountBatch(ctx, []string{"c:1", "c:3"}, []int64{5, -1}, time.Minute); err != nil {
+		t.Fatalf("setCountBatch err: %v", err)
+	}
+	server.Set("c:bad", "x")
+
+	// 顺序与keys一致 重复key各自返回 不存在或无法解析时视为未命中
+	counts, exists, err = getCountBatch(ctx, []string{"c:3", "c:2", "c:1", "c:bad", "c:1"})
+	if err != nil {
+		t.Fatalf("getCountBatch err: %v", err)
+	}
+	if want := []int64{-1, 0, 5, 0, 5}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	if want := []bool{true, false, true, false, true}; !reflect.DeepEqual(exists, want) {
+		t.Errorf("exists = %v, want %v", exists, want)
+	}
+
+	// 过期时间随机延长不超过randomExpirationRatio
+	for _, key := range []string{"c:1", "c:3"} {
+		if ttl := server.TTL(key); ttl < time.Minute || ttl > time.Minute+time.Duration(float64(time.Minute)*randomExpirationRatio) {
+			t.Errorf("TTL(%s) = %v, want within [1m, 1m6s]", key, ttl)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserLikes = "user:lik:"                           // 后接三十六进制userID (节约key长度)
+const prefixUserLikesDelta = prefixUserLikes + "delta:"       // 后接三十六进制userID:commentID (节约key长度)
+const prefixCommentLiked = "comment:lik:"                     // 暂只用于构建其他前缀
+const prefixCommentLikedCount = prefixCommentLiked + "count:" // 后接三十六进制commentID (节约key长度)
+
+// 设置评论点赞关系变更记录(并设置相关计数)
+func setUserLikesDelta(ctx context.Context, userID uint, commentID uint, isLiked bool, expiration time.Duration) (err error) {
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		deltaKey := prefixUserLikesDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(commentID), 36)
+		countKey := prefixCommentLikedCount + strconv.FormatUint(uint64(commentID), 36)
+
+		if isLiked {
+			pipe.SetEx(ctx, deltaKey, isLiked, expiration) // 在确定数据库已被写入后过期
+			pipe.Incr(ctx, countKey)
+		} else {
+			pipe.SetEx(ctx, deltaKey, isLiked, expiration) // 在确定数据库已被写入后过期
+			pipe.Decr(ctx, countKey)
+		}
+		pipe.Expire(ctx, countKey, expiration) // 在确定数据库已被写入后(即最后一条变更记录过期后)强制刷新
+
+		return nil
+	})
+	return err
+}
+
+// 读取评论点赞关系变更记录
+func getUserLikesDelta(ctx context.Context, userID uint, commentID uint) (isLiked bool, err error) {
+	key := prefixUserLikesDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(commentID), 36)
+	return _redis.Get(ctx, key).Bool()
+}
+
+// 设置评论点赞关系(仅用于一致性同步时修正主记录)
+func SetUserLikesBit(ctx context.Context, userID uint, commentID uint, isLiked bool) (err error) {
+	key := prefixUserLikes + strconv.FormatUint(uint64(userID), 36)
+	value := 0
+	if isLiked {
+		value = 1
+	}
+	return _redis.SetBit(ctx, key, int64(commentID), value).Err()
+}
+
+// 设置评论点赞关系(仅用于处理用户请求 会导致随机不信任缓存暂时禁用)
+func SetUserLikes(ctx context.Context, userID uint, commentID uint, isLiked bool, maxSyncDelay time.Duration) (err error) {
+	key := prefixUserLikesDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(commentID), 36)
+	value, err := _redis.Get(ctx, key).Bool() // 读取变更记录以过滤重复请求
+	if err != nil && err != ErrorRedisNil {
+		return err
+	}
+	if err != ErrorRedisNil && value && isLiked { // 已设置过相同变更
+		return ErrorRecordExists // 防止重复计数
+	}
+	if err != ErrorRedisNil && !value && !isLiked { // 已设置过相同变更
+		return ErrorRecordNotExists // 防止重复计数
+	}
+
+	// 写入变更记录 在最长同步延迟+1秒时过期以确保缓存已写入(防止因精确到秒向下取整导致的问题) 过期前禁用随机不信任缓存以防错误同步
+	err = setUserLikesDelta(ctx, userID, commentID, isLiked, maxSyncDelay+time.Second)
+	if err != nil {
+		// 一般为事务整体失败
+		return err
+	}
+
+	// 主记录在最大同步延迟后写入以应对可能即将到来的访问 并覆盖此前的所有错误写入 原因参考缓存双删
+	go func() {
+		time.Sleep(maxSyncDelay)
+
+		_ = SetUserLikesBit(ctx, userID, commentID, isLiked)
+	}()
+
+	return nil
+}
+
+// 读取评论点赞关系
+func GetUserLikes(ctx context.Context, userID uint, commentID uint, distrustProbability float32) (isLiked bool, err error) {
+	isLiked, err = getUserLikesDelta(ctx, userID, commentID)
+	if err == nil { // 若有变更记录存在则直接返回(此时禁用随机不信任缓存)
+		return isLiked, nil
+	}
+
+	distrusted, err := distrust(distrustProbability)
+	if err != nil {
+		return false, err
+	}
+	if distrusted {
+		return false, ErrorRedisNil // 返回查找结果为空, 以供触发一致性同步
+	}
+
+	// 从主记录正常读取
+	key := prefixUserLikes + strconv.FormatUint(uint64(userID), 36)
+	value, err := _redis.GetBit(ctx, key, int64(commentID)).Result()
+	if err != nil {
+		return false, err
+	}
+	if value == 1 {
+		return true, nil
+	} else {
+		return false, nil
+	}
+}
+
+// 设置评论受赞数
+func SetCommentLikedCount(ctx context.Context, commentID uint, count int64, expiration time.Duration) (err error) {
+	key := prefixCommentLikedCount + strconv.FormatUint(uint64(commentID), 36)
+	return _redis.SetEx(ctx, key, count, randomExpiration(expiration)).Err()
+}
+
+// 读取评论受赞数
+func GetCommentLikedCount(ctx context.Context, commentID uint) (count int64, err error) {
+	key := prefixCommentLikedCount + strconv.FormatUint(uint64(commentID), 36)
+	return _redis.Get(ctx, key).Int64()
+}
+
+// 批量设置评论受赞数
+func SetCommentLikedCountBatch(ctx context.Context, commentIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixCommentLikedCount, commentIDs), counts, expiration)
+}
+
+// 批量读取评论受赞数 返回值与commentIDs一一对应 exists为false时表示缓存未命中
+func GetCommentLikedCountBatch(ctx context.Context, commentIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixCommentLikedCount, commentIDs))
+}
+
+// 批量设置评论点赞关系(仅用于一致性同步时修正主记录)
+func SetUserLikesBitBatch(ctx context.Context, userID uint, commentIDs []uint, isLiked []bool) (err error) {
+	if len(commentIDs) == 0 {
+		return nil
+	}
+	key := prefixUserLikes + strconv.FormatUint(uint64(userID), 36)
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, commentID := range commentIDs {
+			value := 0
+			if isLiked[i] {
+				value = 1
+			}
+			pipe.SetBit(ctx, key, int64(commentID), value)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取评论点赞关系 返回值与commentIDs一一对应 exists为false时表示应触发一致性同步
+func GetUserLikesBatch(ctx context.Context, userID uint, commentIDs []uint, distrustProbability float32) (isLiked []bool, exists []bool, err error) {
+	isLiked = make([]bool, len(commentIDs))
+	exists = make([]bool, len(commentIDs))
+	if len(commentIDs) == 0 {
+		return isLiked, exists, nil
+	}
+
+	key := prefixUserLikes + strconv.FormatUint(uint64(userID), 36)
+	deltaCmds := make([]*redis.StringCmd, 0, len(commentIDs))
+	bitCmds := make([]*redis.IntCmd, 0, len(commentIDs))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for _, commentID := range commentIDs {
+			deltaKey := prefixUserLikesDelta + strconv.FormatUint(uint64(userID), 36) + ":" + strconv.FormatUint(uint64(commentID), 36)
+			deltaCmds = append(deltaCmds, pipe.Get(ctx, deltaKey))
+			bitCmds = append(bitCmds, pipe.GetBit(ctx, key, int64(commentID)))
+		}
+		return nil
+	})
+	if err != nil && err != ErrorRedisNil { // 变更记录不存在时将返回ErrorRedisNil
+		return nil, nil, err
+	}
+
+	for i := range commentIDs {
+		delta, err := deltaCmds[i].Bool()
+		if err == nil { // 若有变更记录存在则直接使用(此时禁用随机不信任缓存)
+			isLiked[i] = delta
+			exists[i] = true
+			continue
+		}
+
+		distrusted, err := distrust(distrustProbability)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distrusted { // 视为查找结果为空, 以供触发一致性同步
+			continue
+		}
+
+		// 从主记录正常读取
+		value, err := bitCmds[i].Result()
+		if err != nil {
+			continue
+		}
+		isLiked[i] = value == 1
+		exists[i] = true
+	}
+	return isLiked, exists, nil
+}
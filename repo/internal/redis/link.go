@@ -0,0 +1,23 @@
+package redis
+
+import (
+	"context"
+)
+
+const prefixLinkClicks = "link:clk:" // 后接短链接码
+
+// 自增短链接点击量
+func IncrLinkClicks(ctx context.Context, code string) (count int64, err error) {
+	key := prefixLinkClicks + code
+	return _redis.Incr(ctx, key).Result()
+}
+
+// 读取短链接点击量
+func GetLinkClicks(ctx context.Context, code string) (count int64, err error) {
+	key := prefixLinkClicks + code
+	count, err = _redis.Get(ctx, key).Int64()
+	if err == ErrorRedisNil { // 尚无点击
+		return 0, nil
+	}
+	return count, err
+}
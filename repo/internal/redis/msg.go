@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserMessages = "user:msg:"                       // 暂只用于构建其他前缀
+const prefixUserUnreadCount = prefixUserMessages + "unread:" // 后接三十六进制userID (节约key长度)
+const prefixMessages = "msg:"                                // 暂只用于构建其他前缀
+const prefixMessagesDelivered = prefixMessages + "dlv:"      // 后接三十六进制fromUserID:toUserID (节约key长度)
+const prefixMessagesRead = prefixMessages + "read:"          // 后接三十六进制fromUserID:toUserID (节约key长度)
+
+// 推进进度的脚本 仅当新值大于各key现有值时写入 返回第一个key是否被推进
+// ARGV[3]起依次为各key的持久化进度 key不存在时以其为现有值 未提供且有key不存在时不写入并返回-1 以供载入持久化进度后重试
+var advanceScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	if ARGV[i+2] == nil and redis.call("EXISTS", key) == 0 then
+		return -1
+	end
+end
+local advanced = 0
+for i, key in ipairs(KEYS) do
+	local current = tonumber(redis.call("GET", key) or ARGV[i+2])
+	if tonumber(ARGV[1]) > current then
+		redis.call("SET", key, ARGV[1], "EX", ARGV[2])
+		if i == 1 then
+			advanced = 1
+		end
+	end
+end
+return advanced
+`)
+
+// 设置用户未读消息总数
+func SetUserUnreadCount(ctx context.Context, userID uint, count int64, expiration time.Duration) (err error) {
+	key := prefixUserUnreadCount + strconv.FormatUint(uint64(userID), 36)
+	return _redis.SetEx(ctx, key, count, randomExpiration(expiration)).Err()
+}
+
+// 读取用户未读消息总数
+func GetUserUnreadCount(ctx context.Context, userID uint) (count int64, err error) {
+	key := prefixUserUnreadCount + strconv.FormatUint(uint64(userID), 36)
+	return _redis.Get(ctx, key).Int64()
+}
+
+// 删除用户未读消息总数
+func DelUserUnreadCount(ctx context.Context, userID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixUserUnreadCount + strconv.FormatUint(uint64(userID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
+// 构建消息进度key
+func buildMessagesKey(prefix string, fromUserID uint, toUserID uint) (key string) {
+	return prefix + strconv.FormatUint(uint64(fromUserID), 36) + ":" + strconv.FormatUint(uint64(toUserID), 36)
+}
+
+// 执行推进进度的脚本 persistedIDs为nil且有进度不存在时返回ErrorRedisNil
+func advanceMessages(ctx context.Context, keys []string, messageID uint, persistedIDs []uint, expiration time.Duration) (advanced bool, err error) {
+	args := []any{messageID, int64(randomExpiration(expiration).Seconds())}
+	for _, persistedID := range persistedIDs {
+		args = append(args, persistedID)
+	}
+	result, err := advanceScript.Run(ctx, _redis, keys, args...).Int()
+	if err != nil {
+		return false, err
+	}
+	if result == -1 {
+		return false, ErrorRedisNil // 返回查找结果为空, 以供载入持久化进度
+	}
+	return result == 1, nil
+}
+
+// 推进送达进度(fromUserID发给toUserID的消息已送达至messageID) 返回进度是否被推进 进度不存在时须提供持久化的送达进度deliveredID
+func SetMessagesDelivered(ctx context.Context, fromUserID uint, toUserID uint, messageID uint, persisted bool, deliveredID uint, expiration time.Duration) (advanced bool, err error) {
+	keys := []string{buildMessagesKey(prefixMessagesDelivered, fromUserID, toUserID)}
+	var persistedIDs []uint
+	if persisted {
+		persistedIDs = []uint{deliveredID}
+	}
+	return advanceMessages(ctx, keys, messageID, persistedIDs, expiration)
+}
+
+// 推进已读进度(fromUserID发给toUserID的消息已读至messageID 已读即已送达) 返回已读进度是否被推进 进度不存在时须提供持久化的已读与送达进度
+func SetMessagesRead(ctx context.Context, fromUserID uint, toUserID uint, messageID uint, persisted bool, readID uint, deliveredID uint, expiration time.Duration) (advanced bool, err error) {
+	keys := []string{buildMessagesKey(prefixMessagesRead, fromUserID, toUserID), buildMessagesKey(prefixMessagesDelivered, fromUserID, toUserID)}
+	var persistedIDs []uint
+	if persisted {
+		persistedIDs = []uint{readID, deliveredID}
+	}
+	return advanceMessages(ctx, keys, messageID, persistedIDs, expiration)
+}
+
+// 批量读取送达与已读进度 返回值与fromUserIDs及toUserIDs一一对应 无记录时对应0
+func GetMessagesProgressBatch(ctx context.Context, fromUserIDs []uint, toUserIDs []uint) (deliveredIDs []uint, readIDs []uint, err error) {
+	deliveredIDs = make([]uint, len(fromUserIDs))
+	readIDs = make([]uint, len(fromUserIDs))
+	if len(fromUserIDs) == 0 {
+		return deliveredIDs, readIDs, nil
+	}
+
+	deliveredCmds := make([]*redis.StringCmd, 0, len(fromUserIDs))
+	readCmds := make([]*redis.StringCmd, 0, len(fromUserIDs))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i := range fromUserIDs {
+			deliveredCmds = append(deliveredCmds, pipe.Get(ctx, buildMessagesKey(prefixMessagesDelivered, fromUserIDs[i], toUserIDs[i])))
+			readCmds = append(readCmds, pipe.Get(ctx, buildMessagesKey(prefixMessagesRead, fromUserIDs[i], toUserIDs[i])))
+		}
+		return nil
+	})
+	if err != nil && err != ErrorRedisNil {
+		return nil, nil, err
+	}
+
+	for i := range fromUserIDs {
+		if id, err := deliveredCmds[i].Uint64(); err == nil {
+			deliveredIDs[i] = uint(id)
+		}
+		if id, err := readCmds[i].Uint64(); err == nil {
+			readIDs[i] = uint(id)
+		}
+	}
+	return deliveredIDs, readIDs, nil
+}
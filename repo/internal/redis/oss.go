@@ -12,6 +12,8 @@ const prefixUserAvatarURL = prefixUserOSS + "avatar:"           // 后接objectI
 const prefixUserBackgroundImageURL = prefixUserOSS + "bgimage:" // 后接objectID
 const prefixVideoOSS = "video:oss:"                             // 暂只用于构建其他前缀
 const prefixVideoURL = prefixVideoOSS                           // 后接objectID
+const prefixMessageOSS = "message:oss:"                         // 暂只用于构建其他前缀
+const prefixMessageImageURL = prefixMessageOSS + "image:"       // 后接objectID
 
 // 设置头像对象外链
 func SetUserAvatarURL(ctx context.Context, objectID string, avatarURL string, urlExpiration time.Duration) (err error) {
@@ -100,3 +102,120 @@ func GetVideoURL(ctx context.Context, objectID string) (videoURL string, coverUR
 	}
 	return videoOSS.VideoURL, videoOSS.CoverURL, nil
 }
+
+// 批量读取字符串 返回值与keys一一对应 exists为false时表示缓存未命中
+func getStringBatch(ctx context.Context, keys []string) (values []string, exists []bool, err error) {
+	values = make([]string, len(keys))
+	exists = make([]bool, len(keys))
+	if len(keys) == 0 {
+		return values, exists, nil
+	}
+
+	results, err := _redis.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, result := range results {
+		str, ok := result.(string)
+		if !ok { // 不存在时为nil
+			continue
+		}
+		values[i] = str
+		exists[i] = true
+	}
+	return values, exists, nil
+}
+
+// 批量设置字符串(使用管道)
+func setStringBatch(ctx context.Context, keys []string, values []string, expiration time.Duration) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, key := range keys {
+			pipe.SetEx(ctx, key, values[i], expiration)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量构建以objectID结尾的key
+func buildObjectKeys(prefix string, objectIDs []string) (keys []string) {
+	keys = make([]string, 0, len(objectIDs))
+	for _, objectID := range objectIDs {
+		keys = append(keys, prefix+objectID)
+	}
+	return keys
+}
+
+// 批量设置头像对象外链
+func SetUserAvatarURLBatch(ctx context.Context, objectIDs []string, avatarURLs []string, urlExpiration time.Duration) (err error) {
+	return setStringBatch(ctx, buildObjectKeys(prefixUserAvatarURL, objectIDs), avatarURLs, urlExpiration)
+}
+
+// 批量读取头像对象外链 返回值与objectIDs一一对应 exists为false时表示缓存未命中
+func GetUserAvatarURLBatch(ctx context.Context, objectIDs []string) (avatarURLs []string, exists []bool, err error) {
+	return getStringBatch(ctx, buildObjectKeys(prefixUserAvatarURL, objectIDs))
+}
+
+// 批量设置个人页背景图对象外链
+func SetUserBackgroundImageURLBatch(ctx context.Context, objectIDs []string, backgroundImageURLs []string, urlExpiration time.Duration) (err error) {
+	return setStringBatch(ctx, buildObjectKeys(prefixUserBackgroundImageURL, objectIDs), backgroundImageURLs, urlExpiration)
+}
+
+// 批量读取个人页背景图对象外链 返回值与objectIDs一一对应 exists为false时表示缓存未命中
+func GetUserBackgroundImageURLBatch(ctx context.Context, objectIDs []string) (backgroundImageURLs []string, exists []bool, err error) {
+	return getStringBatch(ctx, buildObjectKeys(prefixUserBackgroundImageURL, objectIDs))
+}
+
+// 批量设置消息图片对象外链
+func SetMessageImageURLBatch(ctx context.Context, objectIDs []string, imageURLs []string, urlExpiration time.Duration) (err error) {
+	return setStringBatch(ctx, buildObjectKeys(prefixMessageImageURL, objectIDs), imageURLs, urlExpiration)
+}
+
+// 批量读取消息图片对象外链 返回值与objectIDs一一对应 exists为false时表示缓存未命中
+func GetMessageImageURLBatch(ctx context.Context, objectIDs []string) (imageURLs []string, exists []bool, err error) {
+	return getStringBatch(ctx, buildObjectKeys(prefixMessageImageURL, objectIDs))
+}
+
+// 批量设置视频对象及封面对象外链
+func SetVideoURLBatch(ctx context.Context, objectIDs []string, videoURLs []string, coverURLs []string, urlExpiration time.Duration) (err error) {
+	if len(objectIDs) == 0 {
+		return nil
+	}
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for i, objectID := range objectIDs {
+			key := prefixVideoURL + objectID
+			value := &videoOSS{VideoURL: videoURLs[i], CoverURL: coverURLs[i]}
+
+			pipe.HSet(ctx, key, value)
+			pipe.Expire(ctx, key, urlExpiration)
+		}
+		return nil
+	})
+	return err
+}
+
+// 批量读取视频对象及封面对象外链 返回值与objectIDs一一对应 exists为false时表示缓存未命中
+func GetVideoURLBatch(ctx context.Context, objectIDs []string) (videoURLs []string, coverURLs []string, exists []bool, err error) {
+	cmds, err := getHashBatch(ctx, buildObjectKeys(prefixVideoURL, objectIDs))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	videoURLs = make([]string, len(objectIDs))
+	coverURLs = make([]string, len(objectIDs))
+	exists = make([]bool, len(objectIDs))
+	for i, cmd := range cmds {
+		if len(cmd.Val()) == 0 { // 不存在
+			continue
+		}
+		videoOSS := &videoOSS{}
+		if cmd.Scan(videoOSS) == nil {
+			videoURLs[i] = videoOSS.VideoURL
+			coverURLs[i] = videoOSS.CoverURL
+			exists[i] = true
+		}
+	}
+	return videoURLs, coverURLs, exists, nil
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserRecommends = "user:rcm:" // 后接三十六进制userID (节约key长度)
+const keyRecommendActive = "rcm:active"  // 近期请求过好友推荐的用户 分数为最近请求时间
+
+// 记录用户最近一次请求好友推荐的时间
+func SetRecommendActive(ctx context.Context, userID uint, at time.Time) (err error) {
+	return _redis.ZAdd(ctx, keyRecommendActive, redis.Z{Score: float64(at.Unix()), Member: userID}).Err()
+}
+
+// 读取自since起请求过好友推荐的用户 同时移除更早的记录
+func GetRecommendActive(ctx context.Context, since time.Time) (userIDs []uint, err error) {
+	var rangeCmd *redis.StringSliceCmd
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		pipe.ZRemRangeByScore(ctx, keyRecommendActive, "-inf", "("+strconv.FormatInt(since.Unix(), 10))
+		rangeCmd = pipe.ZRange(ctx, keyRecommendActive, 0, -1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	members := rangeCmd.Val()
+	userIDs = make([]uint, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, uint(id))
+	}
+	return userIDs, nil
+}
+
+// 设置用户好友推荐榜(覆盖原有推荐 推荐分须为正数)
+func SetUserRecommends(ctx context.Context, userID uint, recommendIDs []uint, scores []float64, expiration time.Duration) (err error) {
+	key := prefixUserRecommends + strconv.FormatUint(uint64(userID), 36)
+	return setRank(ctx, key, recommendIDs, scores, expiration)
+}
+
+// 按推荐分从高到低读取用户好友推荐榜前num名
+func GetUserRecommends(ctx context.Context, userID uint, num int) (recommendIDs []uint, err error) {
+	return getRank(ctx, redis.ZRangeArgs{
+		Key:     prefixUserRecommends + strconv.FormatUint(uint64(userID), 36),
+		Start:   "(0",
+		Stop:    "+inf",
+		ByScore: true,
+		Rev:     true,
+		Count:   int64(num),
+	})
+}
@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const prefixVideoReposts = "video:rpt:"                       // 暂只用于构建其他前缀
+const prefixVideoRepostsCount = prefixVideoReposts + "count:" // 后接三十六进制videoID (节约key长度)
+
+// 设置视频转发数
+func SetVideoRepostsCount(ctx context.Context, videoID uint, count int64, expiration time.Duration) (err error) {
+	key := prefixVideoRepostsCount + strconv.FormatUint(uint64(videoID), 36)
+	return _redis.SetEx(ctx, key, count, randomExpiration(expiration)).Err()
+}
+
+// 读取视频转发数
+func GetVideoRepostsCount(ctx context.Context, videoID uint) (count int64, err error) {
+	key := prefixVideoRepostsCount + strconv.FormatUint(uint64(videoID), 36)
+	return _redis.Get(ctx, key).Int64()
+}
+
+// 删除视频转发数
+func DelVideoRepostsCount(ctx context.Context, videoID uint, maxWriteTime time.Duration) (err error) {
+	key := prefixVideoRepostsCount + strconv.FormatUint(uint64(videoID), 36)
+	err = _redis.Del(ctx, key).Err()
+
+	// 缓存双删
+	go func() {
+		time.Sleep(maxWriteTime)
+
+		_ = _redis.Del(ctx, key).Err()
+	}()
+
+	return err
+}
+
+// 批量设置视频转发数
+func SetVideoRepostsCountBatch(ctx context.Context, videoIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixVideoRepostsCount, videoIDs), counts, expiration)
+}
+
+// 批量读取视频转发数 返回值与videoIDs一一对应 exists为false时表示缓存未命中
+func GetVideoRepostsCountBatch(ctx context.Context, videoIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixVideoRepostsCount, videoIDs))
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prefixUserSeen = "user:seen:" // 后接三十六进制userID:windowID (节约key长度)
+
+// 获取当前及上一周期的已看视频记录key 每条记录最少保留一个周期, 最多保留两个周期
+func getUserSeenKeys(userID uint, window time.Duration) (currentKey string, previousKey string) {
+	windowID := time.Now().UnixNano() / int64(window)
+	prefix := prefixUserSeen + strconv.FormatUint(uint64(userID), 36) + ":"
+	return prefix + strconv.FormatInt(windowID, 36), prefix + strconv.FormatInt(windowID-1, 36)
+}
+
+// 批量设置已看视频
+func SetUserSeenBatch(ctx context.Context, userID uint, videoIDs []uint, window time.Duration) (err error) {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	currentKey, _ := getUserSeenKeys(userID, window)
+	_, err = _redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error { // 使用事务
+		for _, videoID := range videoIDs {
+			pipe.SetBit(ctx, currentKey, int64(videoID), 1)
+		}
+		pipe.Expire(ctx, currentKey, 2*window) // 在下一周期结束后过期
+		return nil
+	})
+	return err
+}
+
+// 批量读取已看视频 返回值与videoIDs一一对应
+func GetUserSeenBatch(ctx context.Context, userID uint, videoIDs []uint, window time.Duration) (isSeen []bool, err error) {
+	isSeen = make([]bool, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return isSeen, nil
+	}
+	currentKey, previousKey := getUserSeenKeys(userID, window)
+	currentCmds := make([]*redis.IntCmd, 0, len(videoIDs))
+	previousCmds := make([]*redis.IntCmd, 0, len(videoIDs))
+	_, err = _redis.Pipelined(ctx, func(pipe redis.Pipeliner) error { // 使用管道
+		for _, videoID := range videoIDs {
+			currentCmds = append(currentCmds, pipe.GetBit(ctx, currentKey, int64(videoID)))
+			previousCmds = append(previousCmds, pipe.GetBit(ctx, previousKey, int64(videoID)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range videoIDs {
+		isSeen[i] = currentCmds[i].Val() == 1 || previousCmds[i].Val() == 1
+	}
+	return isSeen, nil
+}
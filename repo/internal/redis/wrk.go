@@ -35,3 +35,13 @@ func DelUserWorksCount(ctx context.Context, userID uint, maxWriteTime time.Durat
 
 	return err
 }
+
+// 批量设置用户作品数
+func SetUserWorksCountBatch(ctx context.Context, userIDs []uint, counts []int64, expiration time.Duration) (err error) {
+	return setCountBatch(ctx, buildKeys(prefixUserWorksCount, userIDs), counts, expiration)
+}
+
+// 批量读取用户作品数 返回值与userIDs一一对应 exists为false时表示缓存未命中
+func GetUserWorksCountBatch(ctx context.Context, userIDs []uint) (counts []int64, exists []bool, err error) {
+	return getCountBatch(ctx, buildKeys(prefixUserWorksCount, userIDs))
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"douyin/repo/internal/redis"
+
+	"context"
+)
+
+// 自增短链接点击量 (仅记录于缓存)
+func IncrLinkClicks(ctx context.Context, code string) (count int64, err error) {
+	return redis.IncrLinkClicks(ctx, code)
+}
+
+// 读取短链接点击量 (仅记录于缓存)
+func GetLinkClicks(ctx context.Context, code string) (count int64, err error) {
+	return redis.GetLinkClicks(ctx, code)
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+	"douyin/utility"
+
+	"context"
+	"strings"
+)
+
+// 创建或替换提及对象中的提及(并为新被提及的用户创建通知) 无法对应到已有用户或与提及者之间存在拉黑关系的提及将被忽略
+func CreateMentions(ctx context.Context, actorID uint, targetType int, targetID uint, spans []utility.MentionSpan) (mentions []model.Mention, err error) {
+	usernames := make([]string, 0, len(spans))
+	for _, span := range spans {
+		usernames = append(usernames, span.Username)
+	}
+	users, err := db.FindUsersByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make(map[string]uint, len(users)) // 数据库按不区分大小写的排序规则匹配用户名 故以小写用户名为键
+	foundIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs[strings.ToLower(user.Username)] = user.ID
+		foundIDs = append(foundIDs, user.ID)
+	}
+
+	// 忽略与提及者之间存在拉黑关系的用户
+	isBlocking := CheckUserBlocksBatch(ctx, actorID, foundIDs)
+	isBlocked := CheckUserBlockersBatch(ctx, actorID, foundIDs)
+	for i, user := range users {
+		if isBlocking[i] || isBlocked[i] {
+			delete(userIDs, strings.ToLower(user.Username))
+		}
+	}
+
+	mentions = make([]model.Mention, 0, len(spans))
+	for _, span := range spans {
+		userID, ok := userIDs[strings.ToLower(span.Username)]
+		if !ok {
+			continue
+		}
+		mentions = append(mentions, model.Mention{TargetType: targetType, TargetID: targetID, UserID: userID, Offset: span.Offset, Length: span.Length})
+	}
+	err = db.CreateMentions(ctx, actorID, targetType, targetID, mentions)
+	if err != nil {
+		return nil, err
+	}
+	return mentions, nil
+}
+
+// 批量读取提及对象中的提及 按位置排序 //TODO
+func FindMentionsBatch(ctx context.Context, targetType int, targetIDs []uint) (mentions []model.Mention, err error) {
+	return db.FindMentionsBatch(ctx, targetType, targetIDs)
+}
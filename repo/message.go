@@ -6,6 +6,8 @@ import (
 	"douyin/repo/internal/redis"
 
 	"context"
+	"strconv"
+	"time"
 )
 
 // 获取消息主键最大值
@@ -13,17 +15,244 @@ func MaxMessageID(ctx context.Context) (id uint, err error) {
 	return redis.GetMessageMaxID(ctx)
 }
 
-// 创建消息 //TODO
-func CreateMessage(ctx context.Context, fromUserID uint, toUserID uint, content string) (message *model.Message, err error) {
-	message, err = db.CreateMessage(ctx, fromUserID, toUserID, content)
+// 创建消息(同时更新会话并为接收者增加未读消息数)
+func CreateMessage(ctx context.Context, fromUserID uint, toUserID uint, msgType int, content string, payload string) (message *model.Message, err error) {
+	message, err = db.CreateMessage(ctx, fromUserID, toUserID, msgType, content, payload)
 	if err != nil {
 		return nil, err
 	}
 	_ = redis.IncrMessageMaxID(ctx)
+	_ = redis.DelUserUnreadCount(ctx, toUserID, maxRWTime)
 	return message, nil
 }
 
-// 根据聊天双方ID和创建时间查找消息列表(num==-1时取消数量限制) (select: *) //TODO
+// 根据聊天双方ID和创建时间查找消息列表(num==-1时取消数量限制 不含隐藏消息、撤回消息及User1ID已删除的消息) (select: *) //TODO
 func FindMessagesByCreatedAt(ctx context.Context, User1ID uint, User2ID uint, createdAt int64, forward bool, num int) (messages []model.Message, err error) {
 	return db.FindMessagesByCreatedAt(ctx, User1ID, User2ID, createdAt, forward, num)
 }
+
+// 读取消息基本信息 (select: ID, CreatedAt, UpdatedAt, Content, FromUserID, ToUserID, IsHidden, IsRecalled, Status, Type, Payload) //TODO
+func ReadMessageBasics(ctx context.Context, id uint) (message *model.Message, err error) {
+	return db.ReadMessageBasics(ctx, id)
+}
+
+// 批量读取消息基本信息 (select: ID, CreatedAt, UpdatedAt, Content, FromUserID, ToUserID, IsHidden, IsRecalled, Status, Type, Payload) 返回值与ids一一对应 不存在或读取失败时对应nil //TODO
+func ReadMessageBasicsBatch(ctx context.Context, ids []uint) (messages []*model.Message, err error) {
+	records, err := db.ReadMessageBasicsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	recordMap := make(map[uint]*model.Message, len(records))
+	for i := range records {
+		recordMap[records[i].ID] = &records[i]
+	}
+	messages = make([]*model.Message, len(ids))
+	for i, id := range ids {
+		messages[i] = recordMap[id]
+	}
+	return messages, nil
+}
+
+// 批量查找用户与各对方用户之间最近一条消息(不含隐藏消息、撤回消息及用户已删除的消息) 返回值与otherIDs一一对应 无消息往来或读取失败时对应nil
+func FindLastMessagesBatch(ctx context.Context, id uint, otherIDs []uint) (messages []*model.Message) {
+	messages = make([]*model.Message, len(otherIDs))
+	lastMessageIDs, err := db.FindConversationsLastMessageBatch(ctx, id, otherIDs)
+	if err != nil {
+		return messages
+	}
+	messageIDs := make([]uint, 0, len(lastMessageIDs))
+	for _, messageID := range lastMessageIDs {
+		if messageID != 0 {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+	records, err := db.ReadMessageBasicsBatch(ctx, messageIDs)
+	if err != nil {
+		return messages
+	}
+	deletedIDs, err := db.FindDeletedMessageIDs(ctx, id, messageIDs)
+	if err != nil {
+		return messages
+	}
+	recordMap := make(map[uint]*model.Message, len(records))
+	for i := range records {
+		recordMap[records[i].ID] = &records[i]
+	}
+
+	for i, otherID := range otherIDs {
+		messageID, ok := lastMessageIDs[otherID]
+		if ok && messageID == 0 { // 双方无消息往来
+			continue
+		}
+		message := recordMap[messageID]
+		_, isDeleted := deletedIDs[messageID]
+		if ok && message != nil && !message.IsHidden && !message.IsRecalled && !isDeleted {
+			messages[i] = message
+			continue
+		}
+
+		// 会话不存在(早于会话记录的历史消息)或最近一条消息已被隐藏、撤回 按双方最近一条可见消息修正会话记录(对双方生效)
+		if !ok || message == nil || message.IsHidden || message.IsRecalled {
+			_ = db.RefreshConversationLastMessage(ctx, id, otherID)
+		}
+
+		// 回退至逐对查找(仅对该用户删除的消息不改变双方共用的会话记录)
+		records, err := db.FindMessagesByCreatedAt(ctx, id, otherID, time.Now().Unix()+1, false, 1)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		messages[i] = &records[0]
+	}
+	return messages
+}
+
+// 按最近活跃时间倒序分页查找用户的会话(不含无消息往来的会话) (select: *) //TODO
+func FindUserConversations(ctx context.Context, id uint, offset int, num int) (conversations []model.Conversation, err error) {
+	return db.FindUserConversations(ctx, id, offset, num)
+}
+
+// 查找fromUserID发给toUserID的最近一条消息ID(不含撤回消息 无时为0) //TODO
+func FindLastMessageID(ctx context.Context, fromUserID uint, toUserID uint) (id uint, err error) {
+	return db.FindLastMessageID(ctx, fromUserID, toUserID)
+}
+
+// 将会话标记为已读(清零用户在会话中的未读消息数)
+func ReadConversation(ctx context.Context, id uint, otherID uint) (err error) {
+	err = db.ClearConversationUnread(ctx, id, otherID)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelUserUnreadCount(ctx, id, maxRWTime)
+	return nil
+}
+
+// 读取用户未读消息总数
+func CountUserUnread(ctx context.Context, id uint) (count int64) {
+	count, err := redis.GetUserUnreadCount(ctx, id)
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			time.Sleep(maxRWTime)
+			count, err = redis.GetUserUnreadCount(ctx, id) // 重试
+		} else {
+			return count
+		}
+	}
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			return 0
+		} else {
+			return count
+		}
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		_ = redis.SetUserUnreadCount(ctx, id, -1, emptyExpiration) // 防止缓存穿透与缓存击穿
+		record, err := db.CountUserUnread(ctx, id)
+		if err == nil {
+			_ = redis.SetUserUnreadCount(ctx, id, record, cacheExpiration)
+			return record
+		} else {
+			return -1
+		}
+	} else {
+		return -1
+	}
+}
+
+// 确认消息已送达(fromUserID发给toUserID且ID不超过messageID的消息) 仅在进度推进时加入同步队列写回数据库
+func DeliverMessages(ctx context.Context, fromUserID uint, toUserID uint, messageID uint) (advanced bool, err error) {
+	advanced, err = redis.SetMessagesDelivered(ctx, fromUserID, toUserID, messageID, false, 0, cacheExpiration)
+	if err == redis.ErrorRedisNil { // 进度缓存缺失 载入已持久化的进度后重试 防止旧进度被视为推进
+		deliveredID, _, err2 := db.FindMessagesProgress(ctx, fromUserID, toUserID)
+		if err2 != nil {
+			return false, err2
+		}
+		advanced, err = redis.SetMessagesDelivered(ctx, fromUserID, toUserID, messageID, true, deliveredID, cacheExpiration)
+	}
+	if err != nil || !advanced {
+		return false, err
+	}
+
+	// 加入同步队列
+	syncQueue.Push("dlv:" + strconv.FormatUint(uint64(fromUserID), 10) + ":" + strconv.FormatUint(uint64(toUserID), 10) + ":" + strconv.FormatUint(uint64(messageID), 10))
+	return true, nil
+}
+
+// 确认消息已读(fromUserID发给toUserID且ID不超过messageID的消息) 仅在进度推进时加入同步队列写回数据库并重置未读消息数
+func ReadMessages(ctx context.Context, fromUserID uint, toUserID uint, messageID uint) (advanced bool, err error) {
+	advanced, err = redis.SetMessagesRead(ctx, fromUserID, toUserID, messageID, false, 0, 0, cacheExpiration)
+	if err == redis.ErrorRedisNil { // 进度缓存缺失 载入已持久化的进度后重试 防止旧进度被视为推进而重置未读消息数
+		deliveredID, readID, err2 := db.FindMessagesProgress(ctx, fromUserID, toUserID)
+		if err2 != nil {
+			return false, err2
+		}
+		advanced, err = redis.SetMessagesRead(ctx, fromUserID, toUserID, messageID, true, readID, deliveredID, cacheExpiration)
+	}
+	if err != nil || !advanced {
+		return false, err
+	}
+
+	// 加入同步队列
+	syncQueue.Push("read:" + strconv.FormatUint(uint64(fromUserID), 10) + ":" + strconv.FormatUint(uint64(toUserID), 10) + ":" + strconv.FormatUint(uint64(messageID), 10))
+
+	err = db.ResetConversationUnread(ctx, toUserID, fromUserID, messageID)
+	if err != nil {
+		return true, err
+	}
+	_ = redis.DelUserUnreadCount(ctx, toUserID, maxRWTime)
+	return true, nil
+}
+
+// 批量读取送达与已读进度 返回值与fromUserIDs及toUserIDs一一对应 无记录或读取失败时对应0(此时以数据库中的消息状态为准)
+func ReadMessagesProgressBatch(ctx context.Context, fromUserIDs []uint, toUserIDs []uint) (deliveredIDs []uint, readIDs []uint) {
+	deliveredIDs, readIDs, err := redis.GetMessagesProgressBatch(ctx, fromUserIDs, toUserIDs)
+	if err != nil {
+		return make([]uint, len(fromUserIDs)), make([]uint, len(fromUserIDs))
+	}
+	return deliveredIDs, readIDs
+}
+
+// 撤回消息(须在发送后的可撤回时限内) 接收者尚未读到该消息时同时减少其未读消息数
+func RecallMessage(ctx context.Context, id uint) (err error) {
+	message, err := ReadMessageBasics(ctx, id) // 读取基本信息以获取发送时间与聊天双方
+	if err != nil {
+		return err
+	}
+	if time.Since(message.CreatedAt) > messageRecallWindow {
+		return ErrorRecallWindowExpired
+	}
+	_, readIDs := ReadMessagesProgressBatch(ctx, []uint{message.FromUserID}, []uint{message.ToUserID})
+	unread := message.Status < model.MessageStatusRead && message.ID > readIDs[0]
+	err = db.RecallMessage(ctx, id, message.FromUserID, message.ToUserID, unread)
+	if err != nil {
+		return err
+	}
+	if unread {
+		_ = redis.DelUserUnreadCount(ctx, message.ToUserID, maxRWTime)
+	}
+	return nil
+}
+
+// 为用户删除消息(仅对该用户隐藏) 用户为接收者且尚未读到该消息时同时减少其未读消息数
+func DeleteMessageForUser(ctx context.Context, userID uint, id uint) (err error) {
+	message, err := ReadMessageBasics(ctx, id) // 读取基本信息以获取聊天双方与消息状态
+	if err != nil {
+		return err
+	}
+	otherID := message.FromUserID
+	if otherID == userID {
+		otherID = message.ToUserID
+	}
+	unread := false
+	if message.ToUserID == userID && !message.IsRecalled && !message.IsHidden { // 撤回或隐藏时已减少过未读消息数
+		_, readIDs := ReadMessagesProgressBatch(ctx, []uint{message.FromUserID}, []uint{message.ToUserID})
+		unread = message.Status < model.MessageStatusRead && message.ID > readIDs[0]
+	}
+	err = db.CreateMessageDeletion(ctx, userID, otherID, id, unread)
+	if err != nil {
+		return err
+	}
+	if unread {
+		_ = redis.DelUserUnreadCount(ctx, userID, maxRWTime)
+	}
+	return nil
+}
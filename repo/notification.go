@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+
+	"context"
+)
+
+// 分页查找用户收到的通知 按时间倒序 //TODO
+func FindUserNotifications(ctx context.Context, userID uint, offset int, num int) (notifications []model.Notification, err error) {
+	return db.FindUserNotifications(ctx, userID, offset, num)
+}
+
+// 读取用户未读通知数量 //TODO
+func CountUserUnreadNotifications(ctx context.Context, userID uint) (count int64) {
+	return db.CountUserUnreadNotifications(ctx, userID)
+}
+
+// 将用户的通知标记为已读(id为0时标记全部) //TODO
+func UpdateNotificationsRead(ctx context.Context, userID uint, id uint) (err error) {
+	return db.UpdateNotificationsRead(ctx, userID, id)
+}
@@ -1,11 +1,13 @@
 package repo
 
 import (
+	"douyin/repo/internal/db"
 	"douyin/repo/internal/oss"
 	"douyin/repo/internal/redis"
 
 	"context"
 	"io"
+	"strconv"
 	"time"
 )
 
@@ -46,9 +48,26 @@ func UploadVideoStream(ctx context.Context, objectID string, videoStream io.Read
 	return oss.UploadVideoStream(ctx, objectID, videoStream, videoSize)
 }
 
-// 更新封面
-func UpdateCover(ctx context.Context, objectID string) (err error) {
-	return oss.UpdateCover(ctx, objectID)
+// 自动切取并更新封面(视频已有自定义封面时跳过 不覆盖作者上传的封面)
+func UpdateCover(ctx context.Context, id uint) (err error) {
+	return oss.UpdateCover(ctx, strconv.FormatUint(uint64(id), 10), func() bool {
+		hasCustomCover, err := db.CheckVideoCustomCover(ctx, id)
+		return err != nil || hasCustomCover // 无法确认时同样跳过
+	})
+}
+
+// 流式上传自定义封面对象(先标记自定义封面 以阻止进行中的自动切取覆盖该封面)
+func UploadCoverStream(ctx context.Context, id uint, imageStream io.Reader) (err error) {
+	err = db.SetVideoCustomCover(ctx, id)
+	if err != nil {
+		return err
+	}
+	return oss.UploadCoverStream(ctx, strconv.FormatUint(uint64(id), 10), imageStream)
+}
+
+// 移除视频对象与封面对象
+func RemoveVideo(ctx context.Context, objectID string) (err error) {
+	return oss.RemoveVideo(ctx, objectID)
 }
 
 // 获取头像对象的短期外链
@@ -124,3 +143,142 @@ func GetBackgroundImage(ctx context.Context, objectID string) (backgroundImageUR
 func UploadBackgroundImageStream(ctx context.Context, objectID string) (err error) {
 	return oss.UploadBackgroundImageStream(ctx, objectID)
 }
+
+// 批量获取视频对象与封面对象的短期外链 返回值与objectIDs一一对应 获取失败时对应外链为空
+func GetVideoBatch(ctx context.Context, objectIDs []string) (videoURLs []string, coverURLs []string) {
+	videoURLs, coverURLs, exists, err := redis.GetVideoURLBatch(ctx, objectIDs)
+	if err != nil {
+		return make([]string, len(objectIDs)), make([]string, len(objectIDs))
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	for i := range objectIDs {
+		if exists[i] && videoURLs[i] == "" {
+			emptyIndexes = append(emptyIndexes, i)
+		}
+	}
+	if len(emptyIndexes) > 0 {
+		time.Sleep(maxRWTime)
+		retryIDs := make([]string, 0, len(emptyIndexes))
+		for _, i := range emptyIndexes {
+			retryIDs = append(retryIDs, objectIDs[i])
+		}
+		retriedVideoURLs, retriedCoverURLs, retryExists, err := redis.GetVideoURLBatch(ctx, retryIDs) // 重试
+		for j, i := range emptyIndexes {
+			if err == nil {
+				videoURLs[i], coverURLs[i], exists[i] = retriedVideoURLs[j], retriedCoverURLs[j], retryExists[j]
+			}
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	var missIDs []string
+	for i := range objectIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+			missIDs = append(missIDs, objectIDs[i])
+		}
+	}
+	if len(missIDs) == 0 {
+		return videoURLs, coverURLs
+	}
+	placeholders := make([]string, len(missIDs))
+	_ = redis.SetVideoURLBatch(ctx, missIDs, placeholders, placeholders, emptyExpiration) // 防止缓存穿透与缓存击穿
+	var foundIDs, foundVideoURLs, foundCoverURLs []string
+	for j, i := range missIndexes {
+		newVideoURL, newCoverURL, err := oss.GetVideo(ctx, missIDs[j]) // 外链由本地签名生成 无需批量请求
+		if err != nil {
+			continue
+		}
+		videoURLs[i], coverURLs[i] = newVideoURL, newCoverURL
+		foundIDs = append(foundIDs, missIDs[j])
+		foundVideoURLs = append(foundVideoURLs, newVideoURL)
+		foundCoverURLs = append(foundCoverURLs, newCoverURL)
+	}
+	_ = redis.SetVideoURLBatch(ctx, foundIDs, foundVideoURLs, foundCoverURLs, urlExpiration)
+	return videoURLs, coverURLs
+}
+
+// 批量获取对象外链的通用流程 返回值与objectIDs一一对应 获取失败时对应外链为空
+func getURLBatch(ctx context.Context, objectIDs []string,
+	getCache func(ctx context.Context, objectIDs []string) (urls []string, exists []bool, err error),
+	setCache func(ctx context.Context, objectIDs []string, urls []string, urlExpiration time.Duration) (err error),
+	getOSS func(ctx context.Context, objectID string) (url string, err error),
+) (urls []string) {
+	urls, exists, err := getCache(ctx, objectIDs)
+	if err != nil {
+		return make([]string, len(objectIDs))
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	var retryIDs []string
+	for i := range objectIDs {
+		if exists[i] && urls[i] == "" {
+			emptyIndexes = append(emptyIndexes, i)
+			retryIDs = append(retryIDs, objectIDs[i])
+		}
+	}
+	if len(retryIDs) > 0 {
+		time.Sleep(maxRWTime)
+		retried, retryExists, err := getCache(ctx, retryIDs) // 重试
+		for j, i := range emptyIndexes {
+			if err == nil {
+				urls[i], exists[i] = retried[j], retryExists[j]
+			}
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	var missIDs []string
+	for i := range objectIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+			missIDs = append(missIDs, objectIDs[i])
+		}
+	}
+	if len(missIDs) == 0 {
+		return urls
+	}
+	_ = setCache(ctx, missIDs, make([]string, len(missIDs)), emptyExpiration) // 防止缓存穿透与缓存击穿
+	var foundIDs, foundURLs []string
+	for j, i := range missIndexes {
+		newURL, err := getOSS(ctx, missIDs[j]) // 外链由本地签名生成 无需批量请求
+		if err != nil {
+			continue
+		}
+		urls[i] = newURL
+		foundIDs = append(foundIDs, missIDs[j])
+		foundURLs = append(foundURLs, newURL)
+	}
+	_ = setCache(ctx, foundIDs, foundURLs, urlExpiration)
+	return urls
+}
+
+// 批量获取头像对象的短期外链 返回值与objectIDs一一对应 获取失败时对应外链为空
+func GetAvatarBatch(ctx context.Context, objectIDs []string) (avatarURLs []string) {
+	return getURLBatch(ctx, objectIDs, redis.GetUserAvatarURLBatch, redis.SetUserAvatarURLBatch, oss.GetAvatar)
+}
+
+// 批量获取个人页背景图对象的短期外链 返回值与objectIDs一一对应 获取失败时对应外链为空
+func GetBackgroundImageBatch(ctx context.Context, objectIDs []string) (backgroundImageURLs []string) {
+	return getURLBatch(ctx, objectIDs, redis.GetUserBackgroundImageURLBatch, redis.SetUserBackgroundImageURLBatch, oss.GetBackgroundImage)
+}
+
+// 流式上传消息图片对象
+func UploadMessageImageStream(ctx context.Context, objectID string, imageStream io.Reader) (err error) {
+	return oss.UploadMessageImageStream(ctx, objectID, imageStream)
+}
+
+// 移除消息图片对象
+func RemoveMessageImage(ctx context.Context, objectID string) (err error) {
+	return oss.RemoveMessageImage(ctx, objectID)
+}
+
+// 批量获取消息图片对象的短期外链 返回值与objectIDs一一对应 获取失败时对应外链为空
+func GetMessageImageBatch(ctx context.Context, objectIDs []string) (imageURLs []string) {
+	return getURLBatch(ctx, objectIDs, redis.GetMessageImageURLBatch, redis.SetMessageImageURLBatch, oss.GetMessageImage)
+}
@@ -0,0 +1,135 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/redis"
+	"douyin/utility"
+
+	"context"
+	"sort"
+	"time"
+)
+
+const defaultRecommendSize = 50                  // 未配置推荐人数时为每名用户预计算的推荐人数
+const recommendActiveWindow = time.Hour * 24 * 7 // 仅为该时长内请求过好友推荐的用户预计算 其余用户在请求时按需计算
+
+// 各推荐信号的权重
+const (
+	recommendWeightSecondDegree   = 3 // 二度关注(关注的人也关注了候选用户)
+	recommendWeightCommonFollows  = 2 // 共同关注
+	recommendWeightSharedFavorite = 1 // 共同点赞
+)
+
+// 查找用户的好友推荐 按推荐分从高到低 缓存未命中时立即计算
+func FindUserRecommends(ctx context.Context, id uint, num int) (recommendIDs []uint, err error) {
+	_ = redis.SetRecommendActive(ctx, id, time.Now()) // 纳入预计算范围
+	recommendIDs, err = redis.GetUserRecommends(ctx, id, num)
+	if err == nil {
+		return recommendIDs, nil
+	}
+
+	// 缓存未命中 立即计算并写回
+	recommendIDs, err = syncUserRecommends(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendIDs) > num {
+		recommendIDs = recommendIDs[:num]
+	}
+	return recommendIDs, nil
+}
+
+// 计算用户的好友推荐并写入缓存
+func syncUserRecommends(ctx context.Context, id uint) (recommendIDs []uint, err error) {
+	recommendIDs, scores, err := computeUserRecommends(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	expiration := cacheExpiration
+	if recommendInterval > 0 {
+		expiration = recommendInterval * 2 // 保证在下次预计算完成前不过期
+	}
+	_ = redis.SetUserRecommends(ctx, id, recommendIDs, scores, expiration)
+	return recommendIDs, nil
+}
+
+// 计算用户的好友推荐 结果已排除自己、已关注及存在拉黑关系的用户 按推荐分从高到低
+func computeUserRecommends(ctx context.Context, id uint) (recommendIDs []uint, scores []float64, err error) {
+	limit := recommendSize * 4 // 各信号多取一些候选用户 以弥补过滤造成的损失
+	secondDegree, err := db.CountSecondDegreeFollows(ctx, id, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	commonFollows, err := db.CountCommonFollows(ctx, id, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedFavorites, err := db.CountSharedFavorites(ctx, id, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 加权汇总推荐分
+	scoreMap := make(map[uint]float64, len(secondDegree)+len(commonFollows)+len(sharedFavorites))
+	for candidateID, count := range secondDegree {
+		scoreMap[candidateID] += float64(count * recommendWeightSecondDegree)
+	}
+	for candidateID, count := range commonFollows {
+		scoreMap[candidateID] += float64(count * recommendWeightCommonFollows)
+	}
+	for candidateID, count := range sharedFavorites {
+		scoreMap[candidateID] += float64(count * recommendWeightSharedFavorite)
+	}
+	delete(scoreMap, id)
+	candidateIDs := make([]uint, 0, len(scoreMap))
+	for candidateID := range scoreMap {
+		candidateIDs = append(candidateIDs, candidateID)
+	}
+
+	// 排除已关注及存在拉黑关系的用户
+	isFollowing := CheckUserFollowsBatch(ctx, id, candidateIDs)
+	isBlocking := CheckUserBlocksBatch(ctx, id, candidateIDs)
+	isBlocked := CheckUserBlockersBatch(ctx, id, candidateIDs)
+	recommendIDs = make([]uint, 0, len(candidateIDs))
+	for i, candidateID := range candidateIDs {
+		if !isFollowing[i] && !isBlocking[i] && !isBlocked[i] {
+			recommendIDs = append(recommendIDs, candidateID)
+		}
+	}
+
+	// 按推荐分从高到低排序 分数相同时较新的用户优先
+	sort.Slice(recommendIDs, func(i, j int) bool {
+		if scoreMap[recommendIDs[i]] != scoreMap[recommendIDs[j]] {
+			return scoreMap[recommendIDs[i]] > scoreMap[recommendIDs[j]]
+		}
+		return recommendIDs[i] > recommendIDs[j]
+	})
+	if len(recommendIDs) > recommendSize {
+		recommendIDs = recommendIDs[:recommendSize]
+	}
+	scores = make([]float64, len(recommendIDs))
+	for i, recommendID := range recommendIDs {
+		scores[i] = scoreMap[recommendID]
+	}
+	return recommendIDs, scores, nil
+}
+
+func recommendTask() { // 好友推荐预计算任务(仅针对近期活跃用户)
+	startTime := time.Now()
+	successCount, failCount := 0, 0
+	ids, err := redis.GetRecommendActive(context.TODO(), startTime.Add(-recommendActiveWindow))
+	if err != nil {
+		utility.Logger().Errorf("repo.recommendTask (GetRecommendActive) err: %v", err)
+		return
+	}
+	for _, id := range ids {
+		_, err := syncUserRecommends(context.TODO(), id)
+		if err != nil {
+			utility.Logger().Errorf("repo.recommendTask (syncUserRecommends) err: %v", err)
+			failCount++
+			continue
+		}
+		successCount++
+	}
+	utility.Logger().Infof("repo.recommendTask info: %v名用户计算成功, %v名用户计算失败, 耗时%v", successCount, failCount, time.Since(startTime))
+}
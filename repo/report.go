@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+	"douyin/repo/internal/redis"
+
+	"context"
+)
+
+// 删除被隐藏/恢复内容的缓存
+func delContentCache(ctx context.Context, targetType int, targetID uint) {
+	switch targetType {
+	case model.ReportTargetVideo:
+		_ = redis.DelVideoBasics(ctx, targetID, maxRWTime)
+	case model.ReportTargetComment: // 顶层评论列表及热度榜不含隐藏评论 须一并删除
+		comment, err := ReadCommentBasics(ctx, targetID)
+		_ = redis.DelCommentBasics(ctx, targetID, maxRWTime)
+		if err == nil && comment.RootID == 0 {
+			_ = redis.DelVideoCommentsList(ctx, comment.VideoID, maxRWTime)
+			_ = redis.DelVideoCommentsHot(ctx, comment.VideoID, maxRWTime)
+		}
+	case model.ReportTargetSignature:
+		_ = redis.DelUserBasics(ctx, targetID, maxRWTime)
+	case model.ReportTargetMessage: // 消息无基本信息缓存 但接收者的未读消息数可能改变
+		message, err := ReadMessageBasics(ctx, targetID)
+		if err == nil {
+			_ = redis.DelUserUnreadCount(ctx, message.ToUserID, maxRWTime)
+		}
+	}
+}
+
+// 创建举报 待处理举报数达到阈值时自动隐藏内容
+func CreateReport(ctx context.Context, reporterID uint, targetType int, targetID uint, reason int, detail string) (report *model.Report, err error) {
+	report, openCount, err := db.CreateReport(ctx, reporterID, targetType, targetID, reason, detail)
+	if err != nil {
+		return nil, err
+	}
+	if reportThreshold > 0 && openCount >= reportThreshold {
+		err = db.SetContentHidden(ctx, targetType, targetID, true)
+		if err != nil {
+			return report, err
+		}
+		delContentCache(ctx, targetType, targetID)
+	}
+	return report, nil
+}
+
+// 创建系统送审记录并隐藏内容
+func CreateReview(ctx context.Context, targetType int, targetID uint, detail string) (report *model.Report, err error) {
+	report, err = db.CreateReview(ctx, targetType, targetID, detail)
+	if err != nil {
+		return nil, err
+	}
+	delContentCache(ctx, targetType, targetID)
+	return report, nil
+}
+
+// 处理举报 同一对象的全部待处理举报将一并处理 已处理时隐藏内容 已驳回时恢复被自动隐藏或送审隐藏的内容 (返回 select: *)
+func HandleReports(ctx context.Context, id uint, handlerID uint, state int) (report *model.Report, err error) {
+	report, err = db.HandleReports(ctx, id, handlerID, state)
+	if err != nil {
+		return nil, err
+	}
+	delContentCache(ctx, report.TargetType, report.TargetID) // 处理或驳回均可能改变隐藏状态
+	return report, nil
+}
+
+// 根据状态按时间顺序分页查找举报列表 (select: *) //TODO
+func FindReportsByState(ctx context.Context, state int, offset int, num int) (reports []model.Report, err error) {
+	return db.FindReportsByState(ctx, state, offset, num)
+}
@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+	"douyin/repo/internal/redis"
+
+	"context"
+)
+
+// 创建转发
+func CreateRepost(ctx context.Context, userID uint, videoID uint, content string) (repost *model.Repost, err error) {
+	repost, err = db.CreateRepost(ctx, userID, videoID, content)
+	if err != nil {
+		return nil, err
+	}
+	_ = redis.DelVideoRepostsCount(ctx, videoID, maxRWTime)
+	return repost, nil
+}
+
+// 删除转发
+func DeleteRepost(ctx context.Context, userID uint, videoID uint) (err error) {
+	err = db.DeleteRepost(ctx, userID, videoID)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoRepostsCount(ctx, videoID, maxRWTime)
+	return nil
+}
+
+// 根据转发用户ID和(创建时间, ID)复合游标倒序查找转发列表 beforeID为0时包含createdAt时刻的全部转发 (select: *) //TODO
+func FindRepostsByCreatedAt(ctx context.Context, userIDs []uint, createdAt int64, beforeID uint, num int) (reposts []model.Repost, err error) {
+	return db.FindRepostsByCreatedAt(ctx, userIDs, createdAt, beforeID, num)
+}
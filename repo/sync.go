@@ -2,6 +2,7 @@ package repo
 
 import (
 	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
 	"douyin/repo/internal/redis"
 	"douyin/utility"
 
@@ -94,6 +95,38 @@ func syncTask() { // 回写策略的缓存持久化/一致性同步任务
 				}
 			}
 
+			if split[0] == "lik" { // 同步评论点赞变更
+				userID, err := strconv.ParseUint(split[1], 10, 64)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为用户ID", split[1])
+					continue
+				}
+				commentID, err := strconv.ParseUint(split[2], 10, 64)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为评论ID", split[2])
+					continue
+				}
+				isLiked := split[3]
+
+				if isLiked == "1" {
+					err := db.CreateUserLikes(context.TODO(), uint(userID), uint(commentID))
+					if err != nil {
+						utility.Logger().Errorf("repo.syncTask (CreateUserLikes) err: %v", err)
+					} else {
+						successCount++
+					}
+				} else if isLiked == "0" {
+					err := db.DeleteUserLikes(context.TODO(), uint(userID), uint(commentID))
+					if err != nil {
+						utility.Logger().Errorf("repo.syncTask (DeleteUserLikes) err: %v", err)
+					} else {
+						successCount++
+					}
+				} else {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为评论点赞信息", isLiked)
+				}
+			}
+
 			if split[0] == "flw" { // 同步关注变更
 				userID, err := strconv.ParseUint(split[1], 10, 64)
 				if err != nil {
@@ -125,6 +158,35 @@ func syncTask() { // 回写策略的缓存持久化/一致性同步任务
 					utility.Logger().Errorf("repo.syncTask err: %v无法识别为关注信息", isFollowing)
 				}
 			}
+
+			if split[0] == "dlv" || split[0] == "read" { // 同步消息送达/已读进度
+				fromUserID, err := strconv.ParseUint(split[1], 10, 64)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为发送者ID", split[1])
+					continue
+				}
+				toUserID, err := strconv.ParseUint(split[2], 10, 64)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为接收者ID", split[2])
+					continue
+				}
+				messageID, err := strconv.ParseUint(split[3], 10, 64)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask err: %v无法识别为消息ID", split[3])
+					continue
+				}
+
+				status := model.MessageStatusDelivered
+				if split[0] == "read" {
+					status = model.MessageStatusRead
+				}
+				err = db.UpdateMessagesStatus(context.TODO(), uint(fromUserID), uint(toUserID), uint(messageID), status)
+				if err != nil {
+					utility.Logger().Errorf("repo.syncTask (UpdateMessagesStatus) err: %v", err)
+				} else {
+					successCount++
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"douyin/repo/internal/db"
+	"douyin/repo/internal/db/model"
+	"douyin/repo/internal/oss"
+	"douyin/repo/internal/redis"
+	"douyin/utility"
+
+	"context"
+	"strconv"
+	"time"
+)
+
+const purgeBatchSize = 100 // 单次清除任务最多处理的回收站条目数
+
+// 将视频移入回收站(条目归属视频作者)
+func TrashVideo(ctx context.Context, id uint) (err error) {
+	video, err2 := ReadVideoBasics(ctx, id) // 读取基本信息以获取作者ID (必须在删除前进行)
+	err = db.TrashVideo(ctx, id)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
+	if err2 == nil { // 若此前成功获取到作者ID
+		_ = redis.DelUserWorksCount(ctx, video.AuthorID, maxRWTime)
+	}
+	return nil
+}
+
+// 将评论移入回收站(条目归属评论作者) 其下的全部回复将被一并删除
+func TrashComment(ctx context.Context, id uint) (err error) {
+	deleted, err := db.TrashComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	delCommentsCache(ctx, deleted)
+	return nil
+}
+
+// 查找用户回收站中未过期的条目 按删除时间倒序 //TODO
+func FindUserTrash(ctx context.Context, ownerID uint, offset int, num int) (items []model.Trash, err error) {
+	return db.FindUserTrash(ctx, ownerID, time.Now().Add(-trashRetention), offset, num)
+}
+
+// 获取回收站条目的过期时间
+func TrashExpireTime(trash *model.Trash) (expireTime time.Time) {
+	return trash.CreatedAt.Add(trashRetention)
+}
+
+// 恢复回收站条目(仅限内容作者本人且未过期)
+func RestoreTrash(ctx context.Context, id uint, ownerID uint) (trash *model.Trash, err error) {
+	trash, restored, err := db.RestoreTrash(ctx, id, ownerID, time.Now().Add(-trashRetention))
+	if err != nil {
+		return nil, err
+	}
+	if trash.TargetType == model.TrashTargetVideo {
+		_ = redis.DelVideoBasics(ctx, trash.TargetID, maxRWTime)
+		_ = redis.DelUserWorksCount(ctx, trash.OwnerID, maxRWTime) // 条目归属视频作者
+	} else if len(restored) > 0 {
+		delCommentsCache(ctx, restored)
+	}
+	return trash, nil
+}
+
+func purgeTask() { // 回收站过期内容清除任务
+	items, err := db.FindExpiredTrash(context.TODO(), time.Now().Add(-trashRetention), purgeBatchSize)
+	if err != nil {
+		utility.Logger().Errorf("repo.purgeTask (FindExpiredTrash) err: %v", err)
+		return
+	}
+
+	successCount := 0
+	for _, item := range items {
+		if item.TargetType == model.TrashTargetComment {
+			err := db.PurgeComment(context.TODO(), item.ID, item.TargetID)
+			if err != nil {
+				utility.Logger().Errorf("repo.purgeTask (PurgeComment) err: %v", err)
+				continue
+			}
+			_ = redis.DelCommentBasics(context.TODO(), item.TargetID, maxRWTime)
+			successCount++
+		} else if item.TargetType == model.TrashTargetVideo {
+			favoriterIDs, commenterIDs, err := db.PurgeVideo(context.TODO(), item.TargetID)
+			if err != nil {
+				utility.Logger().Errorf("repo.purgeTask (PurgeVideo) err: %v", err)
+				continue
+			}
+			for _, userID := range favoriterIDs {
+				_ = redis.SetUserFavoritesBit(context.TODO(), userID, item.TargetID, false)
+				_ = redis.DelUserFavoritesCount(context.TODO(), userID, maxRWTime)
+			}
+			for _, userID := range commenterIDs {
+				_ = redis.DelUserCommentsCount(context.TODO(), userID, maxRWTime)
+			}
+			_ = redis.DelUserFavoritedCount(context.TODO(), item.OwnerID, maxRWTime) // 条目归属视频作者
+			_ = redis.DelVideoRepostsCount(context.TODO(), item.TargetID, maxRWTime) // 转发已随视频一并清除
+			_ = redis.DelVideoCommentsCount(context.TODO(), item.TargetID, maxRWTime)
+			_ = redis.DelVideoCommentsList(context.TODO(), item.TargetID, maxRWTime)
+			_ = redis.DelVideoCommentsHot(context.TODO(), item.TargetID, maxRWTime)
+			_ = redis.DelVideoBasics(context.TODO(), item.TargetID, maxRWTime)
+
+			// 移除视频对应的OSS对象(失败时保留回收站条目 由下次清除任务重试)
+			err = oss.RemoveVideo(context.TODO(), strconv.FormatUint(uint64(item.TargetID), 10))
+			if err != nil {
+				utility.Logger().Errorf("repo.purgeTask (RemoveVideo) err: %v", err)
+				continue
+			}
+			err = db.DeleteTrash(context.TODO(), item.ID)
+			if err != nil {
+				utility.Logger().Errorf("repo.purgeTask (DeleteTrash) err: %v", err)
+				continue
+			}
+			successCount++
+		} else {
+			utility.Logger().Errorf("repo.purgeTask err: %v无法识别为回收站对象类型", item.TargetType)
+		}
+	}
+
+	if len(items) > 0 {
+		utility.Logger().Infof("repo.purgeTask info: %v项清除成功", successCount)
+	}
+}
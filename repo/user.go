@@ -35,7 +35,7 @@ func CheckUserLogin(ctx context.Context, username string, password string) (id u
 	return db.CheckUserLogin(ctx, username, password)
 }
 
-// 读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature)
+// 读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature, SignatureHidden, IsPrivate)
 func ReadUserBasics(ctx context.Context, id uint) (user *model.User, err error) {
 	user, err = redis.GetUserBasics(ctx, id)
 	if err == nil { // 命中缓存
@@ -67,11 +67,36 @@ func ReadUserBasics(ctx context.Context, id uint) (user *model.User, err error)
 	}
 }
 
-// 读取作品(视频)列表 (select: Works.ID) //TODO
+// 编辑个人签名 (同时设置新签名的隐藏状态)
+func UpdateUserSignature(ctx context.Context, id uint, signature string, hidden bool) (err error) {
+	err = db.UpdateUserSignature(ctx, id, signature, hidden)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelUserBasics(ctx, id, maxRWTime)
+	return nil
+}
+
+// 设置账号是否私密
+func UpdateUserPrivacy(ctx context.Context, id uint, isPrivate bool) (err error) {
+	err = db.UpdateUserPrivacy(ctx, id, isPrivate)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelUserBasics(ctx, id, maxRWTime)
+	return nil
+}
+
+// 读取作品(视频)列表 (select: Works.ID) 不含草稿及隐藏视频 //TODO
 func ReadUserWorks(ctx context.Context, id uint) (videos []model.Video, err error) {
 	return db.ReadUserWorks(ctx, id)
 }
 
+// 读取草稿列表 (select: Works.ID) //TODO
+func ReadUserDrafts(ctx context.Context, id uint) (videos []model.Video, err error) {
+	return db.ReadUserDrafts(ctx, id)
+}
+
 // 读取作品(视频)数量
 func CountUserWorks(ctx context.Context, id uint) (count int64) {
 	count, err := redis.GetUserWorksCount(ctx, id)
@@ -212,6 +237,47 @@ func CheckUserFavorites(ctx context.Context, id uint, videoID uint) (isFavorite
 	}
 }
 
+// 创建评论点赞关系
+func CreateUserLikes(ctx context.Context, id uint, commentID uint) (err error) {
+	// 加入同步队列
+	syncQueue.Push("lik:" + strconv.FormatUint(uint64(id), 10) + ":" + strconv.FormatUint(uint64(commentID), 10) + ":1")
+
+	err = redis.SetUserLikes(ctx, id, commentID, true, syncInterval+maxRWTime*time.Duration(syncQueue.Len())) // 因串行同步而生的临时解决方案 //TODO
+	if err != nil {
+		return err
+	}
+	updateCommentHot(ctx, commentID)
+	return nil
+}
+
+// 删除评论点赞关系
+func DeleteUserLikes(ctx context.Context, id uint, commentID uint) (err error) {
+	// 加入同步队列
+	syncQueue.Push("lik:" + strconv.FormatUint(uint64(id), 10) + ":" + strconv.FormatUint(uint64(commentID), 10) + ":0")
+
+	err = redis.SetUserLikes(ctx, id, commentID, false, syncInterval+maxRWTime*time.Duration(syncQueue.Len())) // 因串行同步而生的临时解决方案 //TODO
+	if err != nil {
+		return err
+	}
+	updateCommentHot(ctx, commentID)
+	return nil
+}
+
+// 检查评论点赞关系
+func CheckUserLikes(ctx context.Context, id uint, commentID uint) (isLiked bool) {
+	isLiked, err := redis.GetUserLikes(ctx, id, commentID, distrustProbability)
+	if err == nil { // 命中缓存
+		return isLiked
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		record := db.CheckUserLikes(ctx, id, commentID)
+		_ = redis.SetUserLikesBit(ctx, id, commentID, record) // 立即修正缓存主记录
+		return record
+	} else {
+		return false
+	}
+}
+
 // 读取评论列表 (select: Comments.ID) //TODO
 func ReadUserComments(ctx context.Context, id uint) (comments []model.Comment, err error) {
 	return db.ReadUserComments(ctx, id)
@@ -259,7 +325,13 @@ func CreateUserFollows(ctx context.Context, id uint, followID uint) (err error)
 	// 加入同步队列
 	syncQueue.Push("flw:" + strconv.FormatUint(uint64(id), 10) + ":" + strconv.FormatUint(uint64(followID), 10) + ":1")
 
-	return redis.SetUserFollows(ctx, id, followID, true, syncInterval+maxRWTime*time.Duration(syncQueue.Len())) // 因串行同步而生的临时解决方案 //TODO
+	maxSyncDelay := syncInterval + maxRWTime*time.Duration(syncQueue.Len()) // 因串行同步而生的临时解决方案 //TODO
+	err = redis.SetUserFollows(ctx, id, followID, true, maxSyncDelay)
+	if err != nil {
+		return err
+	}
+	delUserFriendsCache(ctx, id, followID, maxSyncDelay)
+	return nil
 }
 
 // 删除关注关系
@@ -267,7 +339,13 @@ func DeleteUserFollows(ctx context.Context, id uint, followID uint) (err error)
 	// 加入同步队列
 	syncQueue.Push("flw:" + strconv.FormatUint(uint64(id), 10) + ":" + strconv.FormatUint(uint64(followID), 10) + ":0")
 
-	return redis.SetUserFollows(ctx, id, followID, false, syncInterval+maxRWTime*time.Duration(syncQueue.Len())) // 因串行同步而生的临时解决方案 //TODO
+	maxSyncDelay := syncInterval + maxRWTime*time.Duration(syncQueue.Len()) // 因串行同步而生的临时解决方案 //TODO
+	err = redis.SetUserFollows(ctx, id, followID, false, maxSyncDelay)
+	if err != nil {
+		return err
+	}
+	delUserFriendsCache(ctx, id, followID, maxSyncDelay)
+	return nil
 }
 
 // 读取关注(用户)列表 (select: Follows.ID) //TODO
@@ -275,6 +353,26 @@ func ReadUserFollows(ctx context.Context, id uint) (users []model.User, err erro
 	return db.ReadUserFollows(ctx, id)
 }
 
+// 读取朋友(互相关注的用户)ID列表
+func ReadUserFriends(ctx context.Context, id uint) (friendIDs []uint, err error) {
+	friendIDs, err = redis.GetUserFriends(ctx, id)
+	if err == redis.ErrorRedisNil { // 启动同步
+		friendIDs, err = db.ReadUserFriends(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		_ = redis.SetUserFriends(ctx, id, friendIDs, cacheExpiration)
+		return friendIDs, nil
+	}
+	return friendIDs, err
+}
+
+// 删除关注双方的朋友集合缓存 第二次删除在关注关系同步至数据库后进行
+func delUserFriendsCache(ctx context.Context, id uint, followID uint, maxSyncDelay time.Duration) {
+	_ = redis.DelUserFriends(ctx, id, maxSyncDelay+maxRWTime)
+	_ = redis.DelUserFriends(ctx, followID, maxSyncDelay+maxRWTime)
+}
+
 // 读取关注(用户)数量
 func CountUserFollows(ctx context.Context, id uint) (count int64) {
 	count, err := redis.GetUserFollowsCount(ctx, id)
@@ -368,3 +466,210 @@ func ReadUserMessages(ctx context.Context, id uint) (messages []model.Message, e
 func CountUserMessages(ctx context.Context, id uint) (count int64) {
 	return db.CountUserMessages(ctx, id)
 }
+
+// 批量读取用户基本信息 (select: ID, CreatedAt, UpdatedAt, Username, Signature, SignatureHidden, IsPrivate) 返回值与ids一一对应 不存在或读取失败时对应nil
+func ReadUserBasicsBatch(ctx context.Context, ids []uint) (users []*model.User, err error) {
+	users, err = redis.GetUserBasicsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	for i, user := range users {
+		if user != nil && user.ID == 0 {
+			emptyIndexes = append(emptyIndexes, i)
+		}
+	}
+	if len(emptyIndexes) > 0 {
+		time.Sleep(maxRWTime)
+		retried, err := redis.GetUserBasicsBatch(ctx, pickIDs(ids, emptyIndexes)) // 重试
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range emptyIndexes {
+			users[i] = retried[j]
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i, user := range users {
+		if user == nil {
+			missIndexes = append(missIndexes, i)
+		} else if user.ID == 0 { // 命中空对象
+			users[i] = nil
+		}
+	}
+	if len(missIndexes) == 0 {
+		return users, nil
+	}
+	missIDs := pickIDs(ids, missIndexes)
+	placeholders := make([]*model.User, len(missIDs))
+	for j := range placeholders {
+		placeholders[j] = &model.User{}
+	}
+	_ = redis.SetUserBasicsBatch(ctx, missIDs, placeholders, emptyExpiration) // 防止缓存穿透与缓存击穿
+	records, err := db.ReadUserBasicsBatch(ctx, missIDs)
+	if err != nil {
+		return users, nil // 同步失败的用户对应nil
+	}
+	recordMap := make(map[uint]*model.User, len(records))
+	for k := range records {
+		recordMap[records[k].ID] = &records[k]
+	}
+	foundIDs := make([]uint, 0, len(records))
+	found := make([]*model.User, 0, len(records))
+	for j, i := range missIndexes {
+		if record, ok := recordMap[missIDs[j]]; ok {
+			users[i] = record
+			foundIDs = append(foundIDs, missIDs[j])
+			found = append(found, record)
+		}
+	}
+	_ = redis.SetUserBasicsBatch(ctx, foundIDs, found, cacheExpiration)
+	return users, nil
+}
+
+// 批量读取作品(视频)数量 返回值与ids一一对应
+func CountUserWorksBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserWorksCountBatch, redis.SetUserWorksCountBatch, db.CountUserWorksBatch)
+}
+
+// 批量读取点赞(视频)数量 返回值与ids一一对应
+func CountUserFavoritesBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserFavoritesCountBatch, redis.SetUserFavoritesCountBatch, db.CountUserFavoritesBatch)
+}
+
+// 批量读取获赞数量 返回值与ids一一对应
+func CountUserFavoritedBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserFavoritedCountBatch, redis.SetUserFavoritedCountBatch, db.CountUserFavoritedBatch)
+}
+
+// 批量检查点赞关系 返回值与videoIDs一一对应
+func CheckUserFavoritesBatch(ctx context.Context, id uint, videoIDs []uint) (isFavorite []bool) {
+	isFavorite, exists, err := redis.GetUserFavoritesBatch(ctx, id, videoIDs, distrustProbability)
+	if err != nil {
+		return make([]bool, len(videoIDs))
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range videoIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return isFavorite
+	}
+	missIDs := pickIDs(videoIDs, missIndexes)
+	records, err := db.CheckUserFavoritesBatch(ctx, id, missIDs)
+	if err != nil {
+		return isFavorite // 同步失败的视频对应false
+	}
+	values := make([]bool, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]]
+		isFavorite[i] = values[j]
+	}
+	_ = redis.SetUserFavoritesBitBatch(ctx, id, missIDs, values) // 立即修正缓存主记录
+	return isFavorite
+}
+
+// 批量检查评论点赞关系 返回值与commentIDs一一对应
+func CheckUserLikesBatch(ctx context.Context, id uint, commentIDs []uint) (isLiked []bool) {
+	isLiked, exists, err := redis.GetUserLikesBatch(ctx, id, commentIDs, distrustProbability)
+	if err != nil {
+		return make([]bool, len(commentIDs))
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range commentIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return isLiked
+	}
+	missIDs := pickIDs(commentIDs, missIndexes)
+	records, err := db.CheckUserLikesBatch(ctx, id, missIDs)
+	if err != nil {
+		return isLiked // 同步失败的评论对应false
+	}
+	values := make([]bool, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]]
+		isLiked[i] = values[j]
+	}
+	_ = redis.SetUserLikesBitBatch(ctx, id, missIDs, values) // 立即修正缓存主记录
+	return isLiked
+}
+
+// 批量读取评论数量 返回值与ids一一对应
+func CountUserCommentsBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserCommentsCountBatch, redis.SetUserCommentsCountBatch, db.CountUserCommentsBatch)
+}
+
+// 批量读取关注(用户)数量 返回值与ids一一对应
+func CountUserFollowsBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserFollowsCountBatch, redis.SetUserFollowsCountBatch, db.CountUserFollowsBatch)
+}
+
+// 批量读取粉丝(用户)数量 返回值与ids一一对应
+func CountUserFollowersBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetUserFollowersCountBatch, redis.SetUserFollowersCountBatch, db.CountUserFollowersBatch)
+}
+
+// 批量检查关注关系 返回值与followIDs一一对应
+func CheckUserFollowsBatch(ctx context.Context, id uint, followIDs []uint) (isFollowing []bool) {
+	isFollowing, exists, err := redis.GetUserFollowsBatch(ctx, id, followIDs, distrustProbability)
+	if err != nil {
+		return make([]bool, len(followIDs))
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i := range followIDs {
+		if !exists[i] {
+			missIndexes = append(missIndexes, i)
+		}
+	}
+	if len(missIndexes) == 0 {
+		return isFollowing
+	}
+	missIDs := pickIDs(followIDs, missIndexes)
+	records, err := db.CheckUserFollowsBatch(ctx, id, missIDs)
+	if err != nil {
+		return isFollowing // 同步失败的用户对应false
+	}
+	values := make([]bool, len(missIDs))
+	for j, i := range missIndexes {
+		values[j] = records[missIDs[j]]
+		isFollowing[i] = values[j]
+	}
+	_ = redis.SetUserFollowsBitBatch(ctx, id, missIDs, values) // 立即修正缓存主记录
+	return isFollowing
+}
+
+// 记录已看视频(seenWindow为0时不记录)
+func SetUserSeen(ctx context.Context, id uint, videoIDs []uint) (err error) {
+	if seenWindow == 0 {
+		return nil
+	}
+	return redis.SetUserSeenBatch(ctx, id, videoIDs, seenWindow)
+}
+
+// 批量检查是否已看过视频 返回值与videoIDs一一对应 (seenWindow为0时均视为未看过)
+func CheckUserSeenBatch(ctx context.Context, id uint, videoIDs []uint) (isSeen []bool) {
+	if seenWindow == 0 {
+		return make([]bool, len(videoIDs))
+	}
+	isSeen, err := redis.GetUserSeenBatch(ctx, id, videoIDs, seenWindow)
+	if err != nil {
+		return make([]bool, len(videoIDs)) // 出错时不过滤
+	}
+	return isSeen
+}
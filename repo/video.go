@@ -32,18 +32,58 @@ func DeleteVideo(ctx context.Context, id uint, permanently bool) (err error) {
 	if err != nil {
 		return err
 	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
 	if err2 == nil { // 若此前成功获取到作者ID
 		_ = redis.DelUserWorksCount(ctx, video.AuthorID, maxRWTime)
 	}
 	return nil
 }
 
-// 根据创建时间查找视频列表(num==-1时取消数量限制) (select: ID, CreatedAt) //TODO
+// 创建草稿 (不计入作品数)
+func CreateDraft(ctx context.Context, authorID uint, title string) (video *model.Video, err error) {
+	video, err = db.CreateDraft(ctx, authorID, title)
+	if err != nil {
+		return nil, err
+	}
+	_ = redis.IncrVideoMaxID(ctx)
+	return video, nil
+}
+
+// 编辑草稿标题
+func UpdateDraftTitle(ctx context.Context, id uint, title string) (err error) {
+	err = db.UpdateDraftTitle(ctx, id, title)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
+	return nil
+}
+
+// 发布草稿
+func PublishDraft(ctx context.Context, id uint) (err error) {
+	video, err2 := ReadVideoBasics(ctx, id) // 读取基本信息以获取作者ID (必须在发布前进行)
+	err = db.PublishDraft(ctx, id)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
+	if err2 == nil { // 若此前成功获取到作者ID
+		_ = redis.DelUserWorksCount(ctx, video.AuthorID, maxRWTime)
+	}
+	return nil
+}
+
+// 根据创建时间查找视频列表(num==-1时取消数量限制 不含草稿及隐藏视频) (select: ID, CreatedAt) //TODO
 func FindVideosByCreatedAt(ctx context.Context, createdAt int64, forward bool, num int) (videos []model.Video, err error) {
 	return db.FindVideosByCreatedAt(ctx, createdAt, forward, num)
 }
 
-// 读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID)
+// 根据作者ID和(创建时间, ID)复合游标倒序查找视频列表(不含草稿及隐藏视频) beforeID为0时包含createdAt时刻的全部视频 (select: ID, CreatedAt) //TODO
+func FindVideosByAuthorsAndCreatedAt(ctx context.Context, authorIDs []uint, createdAt int64, beforeID uint, num int) (videos []model.Video, err error) {
+	return db.FindVideosByAuthorsAndCreatedAt(ctx, authorIDs, createdAt, beforeID, num)
+}
+
+// 读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID, IsDraft, IsHidden, PinnedCommentID, CommentPolicy)
 func ReadVideoBasics(ctx context.Context, id uint) (video *model.Video, err error) {
 	video, err = redis.GetVideoBasics(ctx, id)
 	if err == nil { // 命中缓存
@@ -149,7 +189,138 @@ func CountVideoComments(ctx context.Context, id uint) (count int64) {
 	}
 }
 
+// 读取转发数量
+func CountVideoReposts(ctx context.Context, id uint) (count int64) {
+	count, err := redis.GetVideoRepostsCount(ctx, id)
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			time.Sleep(maxRWTime)
+			count, err = redis.GetVideoRepostsCount(ctx, id) // 重试
+		} else {
+			return count
+		}
+	}
+	if err == nil { // 命中缓存
+		if count == -1 { // 命中空对象
+			return 0
+		} else {
+			return count
+		}
+	}
+	if err == redis.ErrorRedisNil { // 启动同步
+		_ = redis.SetVideoRepostsCount(ctx, id, -1, emptyExpiration) // 防止缓存穿透与缓存击穿
+		record := db.CountVideoReposts(ctx, id)
+		if record >= 0 {
+			_ = redis.SetVideoRepostsCount(ctx, id, record, cacheExpiration)
+			return record
+		} else {
+			return -1
+		}
+	} else {
+		return -1
+	}
+}
+
 // 检查评论所属 //TODO
 func CheckVideoComments(ctx context.Context, id uint, commentID uint) (isIts bool) {
 	return db.CheckVideoComments(ctx, id, commentID)
 }
+
+// 批量读取视频基本信息 (select: ID, CreatedAt, UpdatedAt, Title, AuthorID, IsDraft, IsHidden, PinnedCommentID, CommentPolicy) 返回值与ids一一对应 不存在或读取失败时对应nil
+func ReadVideoBasicsBatch(ctx context.Context, ids []uint) (videos []*model.Video, err error) {
+	videos, err = redis.GetVideoBasicsBatch(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 命中空对象时重试
+	var emptyIndexes []int
+	for i, video := range videos {
+		if video != nil && video.ID == 0 {
+			emptyIndexes = append(emptyIndexes, i)
+		}
+	}
+	if len(emptyIndexes) > 0 {
+		time.Sleep(maxRWTime)
+		retried, err := redis.GetVideoBasicsBatch(ctx, pickIDs(ids, emptyIndexes)) // 重试
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range emptyIndexes {
+			videos[i] = retried[j]
+		}
+	}
+
+	// 启动同步
+	var missIndexes []int
+	for i, video := range videos {
+		if video == nil {
+			missIndexes = append(missIndexes, i)
+		} else if video.ID == 0 { // 命中空对象
+			videos[i] = nil
+		}
+	}
+	if len(missIndexes) == 0 {
+		return videos, nil
+	}
+	missIDs := pickIDs(ids, missIndexes)
+	placeholders := make([]*model.Video, len(missIDs))
+	for j := range placeholders {
+		placeholders[j] = &model.Video{}
+	}
+	_ = redis.SetVideoBasicsBatch(ctx, missIDs, placeholders, emptyExpiration) // 防止缓存穿透与缓存击穿
+	records, err := db.ReadVideoBasicsBatch(ctx, missIDs)
+	if err != nil {
+		return videos, nil // 同步失败的视频对应nil
+	}
+	recordMap := make(map[uint]*model.Video, len(records))
+	for k := range records {
+		recordMap[records[k].ID] = &records[k]
+	}
+	foundIDs := make([]uint, 0, len(records))
+	found := make([]*model.Video, 0, len(records))
+	for j, i := range missIndexes {
+		if record, ok := recordMap[missIDs[j]]; ok {
+			videos[i] = record
+			foundIDs = append(foundIDs, missIDs[j])
+			found = append(found, record)
+		}
+	}
+	_ = redis.SetVideoBasicsBatch(ctx, foundIDs, found, cacheExpiration)
+	return videos, nil
+}
+
+// 批量读取点赞(用户)数量 返回值与ids一一对应
+func CountVideoFavoritedBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetVideoFavoritedCountBatch, redis.SetVideoFavoritedCountBatch, db.CountVideoFavoritedBatch)
+}
+
+// 批量读取评论数量 返回值与ids一一对应
+func CountVideoCommentsBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetVideoCommentsCountBatch, redis.SetVideoCommentsCountBatch, db.CountVideoCommentsBatch)
+}
+
+// 批量读取转发数量 返回值与ids一一对应
+func CountVideoRepostsBatch(ctx context.Context, ids []uint) (counts []int64) {
+	return countBatch(ctx, ids, redis.GetVideoRepostsCountBatch, redis.SetVideoRepostsCountBatch, db.CountVideoRepostsBatch)
+}
+
+// 置顶视频下的顶层评论(commentID为0时取消置顶)
+func UpdateVideoPinnedComment(ctx context.Context, id uint, commentID uint) (err error) {
+	err = db.UpdateVideoPinnedComment(ctx, id, commentID)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
+	return nil
+}
+
+// 设置视频评论权限
+func UpdateVideoCommentPolicy(ctx context.Context, id uint, policy int) (err error) {
+	err = db.UpdateVideoCommentPolicy(ctx, id, policy)
+	if err != nil {
+		return err
+	}
+	_ = redis.DelVideoBasics(ctx, id, maxRWTime)
+	return nil
+}
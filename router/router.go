@@ -24,19 +24,76 @@ func NewRouter() *gin.Engine {
 			context.JSON(http.StatusOK, "success")
 		})
 
-		rootAPI.GET("/feed", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFeed) // 应用限流中间件, jwt鉴权中间件
+		rootAPI.GET("/feed", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFeed)                    // 应用限流中间件, jwt鉴权中间件
+		rootAPI.POST("/feed/watch/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTWatch)           // 应用限流中间件, jwt鉴权中间件(强制)
+		rootAPI.GET("/feed/following/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETFollowingFeed) // 应用限流中间件, jwt鉴权中间件(强制)
 
 		userAPI := rootAPI.Group("user")
 		{
-			userAPI.POST("/register/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), api.POSTUserRegister)                // 应用限流中间件
-			userAPI.POST("/login/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), api.POSTUserLogin)                      // 应用限流中间件
-			userAPI.GET("/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETUserInfo) // 应用限流中间件, jwt鉴权中间件(强制)
+			userAPI.POST("/register/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), api.POSTUserRegister)                                 // 应用限流中间件
+			userAPI.POST("/login/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), api.POSTUserLogin)                                       // 应用限流中间件
+			userAPI.GET("/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETUserInfo)                  // 应用限流中间件, jwt鉴权中间件(强制)
+			userAPI.POST("/signature/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTUserSignature) // 应用限流中间件, jwt鉴权中间件(强制)
+			userAPI.POST("/privacy/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTUserPrivacy)     // 应用限流中间件, jwt鉴权中间件(强制)
 		}
 
 		publishAPI := rootAPI.Group("publish")
 		{
-			publishAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTPublish)  // 应用限流中间件, jwt鉴权中间件(强制)
-			publishAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETPublishList) // 应用限流中间件, jwt鉴权中间件
+			publishAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTPublish)       // 应用限流中间件, jwt鉴权中间件(强制)
+			publishAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETPublishList)      // 应用限流中间件, jwt鉴权中间件
+			publishAPI.POST("/delete/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTPublishDelete) // 应用限流中间件, jwt鉴权中间件(强制)
+		}
+
+		draftAPI := rootAPI.Group("draft")
+		{
+			draftAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTDraft)         // 应用限流中间件, jwt鉴权中间件(强制)
+			draftAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETDraftList)         // 应用限流中间件, jwt鉴权中间件(强制)
+			draftAPI.POST("/publish/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTDraftPublish) // 应用限流中间件, jwt鉴权中间件(强制)
+		}
+
+		trashAPI := rootAPI.Group("trash")
+		{
+			trashAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETTrashList)         // 应用限流中间件, jwt鉴权中间件(强制)
+			trashAPI.POST("/restore/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTTrashRestore) // 应用限流中间件, jwt鉴权中间件(强制)
+		}
+
+		collectionAPI := rootAPI.Group("collection")
+		{
+			collectionAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCollection)             // 应用限流中间件, jwt鉴权中间件(强制)
+			collectionAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETCollectionList)            // 应用限流中间件, jwt鉴权中间件
+			collectionAPI.POST("/video/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCollectionItem)   // 应用限流中间件, jwt鉴权中间件(强制)
+			collectionAPI.POST("/video/move/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCollectionMove)     // 应用限流中间件, jwt鉴权中间件(强制)
+			collectionAPI.GET("/video/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETCollectionVideoList) // 应用限流中间件, jwt鉴权中间件
+		}
+
+		shareAPI := rootAPI.Group("share")
+		{
+			shareAPI.GET("/link/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETShareLink) // 应用限流中间件, jwt鉴权中间件
+			shareAPI.GET("/resolve/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), api.GETShareResolve)                          // 应用限流中间件
+		}
+
+		repostAPI := rootAPI.Group("repost")
+		{
+			repostAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTRepost)  // 应用限流中间件, jwt鉴权中间件(强制)
+			repostAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETRepostList) // 应用限流中间件, jwt鉴权中间件
+		}
+
+		reportAPI := rootAPI.Group("report")
+		{
+			reportAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTReport) // 应用限流中间件, jwt鉴权中间件(强制)
+		}
+
+		moderationAPI := rootAPI.Group("moderation")
+		{
+			moderationAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), midware.MiddlewareModerator(), api.GETModerationList)                       // 应用限流中间件, jwt鉴权中间件(强制), 审核员鉴权中间件
+			moderationAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), midware.MiddlewareModerator(), api.POSTModeration)                       // 应用限流中间件, jwt鉴权中间件(强制), 审核员鉴权中间件
+			moderationAPI.GET("/comment/revision/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), midware.MiddlewareModerator(), api.GETCommentRevisionList) // 应用限流中间件, jwt鉴权中间件(强制), 审核员鉴权中间件
+		}
+
+		notificationAPI := rootAPI.Group("notification")
+		{
+			notificationAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETNotificationList)   // 应用限流中间件, jwt鉴权中间件(强制)
+			notificationAPI.POST("/read/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTNotificationRead) // 应用限流中间件, jwt鉴权中间件(强制)
 		}
 
 		favoriteAPI := rootAPI.Group("favorite")
@@ -47,22 +104,39 @@ func NewRouter() *gin.Engine {
 
 		commentAPI := rootAPI.Group("comment")
 		{
-			commentAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTComment)  // 应用限流中间件, jwt鉴权中间件(强制)
-			commentAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETCommentList) // 应用限流中间件, jwt鉴权中间件
+			commentAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTComment)             // 应用限流中间件, jwt鉴权中间件(强制)
+			commentAPI.GET("/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETCommentList)            // 应用限流中间件, jwt鉴权中间件
+			commentAPI.GET("/reply/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETCommentReplyList) // 应用限流中间件, jwt鉴权中间件
+			commentAPI.POST("/like/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCommentLike)    // 应用限流中间件, jwt鉴权中间件(强制)
+			commentAPI.POST("/pin/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCommentPin)             // 应用限流中间件, jwt鉴权中间件(强制)
+			commentAPI.POST("/policy/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCommentPolicy)       // 应用限流中间件, jwt鉴权中间件(强制)
+			commentAPI.POST("/edit/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTCommentEdit)           // 应用限流中间件, jwt鉴权中间件(强制)
 		}
 
 		relationAPI := rootAPI.Group("relation")
 		{
-			relationAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTFollow)             // 应用限流中间件, jwt鉴权中间件(强制)
-			relationAPI.GET("/follow/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFollowList)     // 应用限流中间件, jwt鉴权中间件
-			relationAPI.GET("/follower/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFollowerList) // 应用限流中间件, jwt鉴权中间件
-			relationAPI.GET("/friend/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETFriendList)      // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTFollow)                      // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.GET("/follow/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFollowList)              // 应用限流中间件, jwt鉴权中间件
+			relationAPI.GET("/follower/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(false), api.GETFollowerList)          // 应用限流中间件, jwt鉴权中间件
+			relationAPI.POST("/follower/remove/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTRemoveFollower)     // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.GET("/friend/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETFriendList)               // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.POST("/block/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTBlock)                 // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.GET("/block/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETBlockList)                 // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.GET("/request/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETFollowRequestList)       // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.POST("/request/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTFollowRequestAction) // 应用限流中间件, jwt鉴权中间件(强制)
+			relationAPI.GET("/recommend/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETRecommendList)         // 应用限流中间件, jwt鉴权中间件(强制)
 		}
 
 		messageAPI := rootAPI.Group("message")
 		{
-			messageAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTMessage) // 应用限流中间件, jwt鉴权中间件(强制)
-			messageAPI.GET("/chat/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETMessageList) // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.POST("/action/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTMessage)                     // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.GET("/chat/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETMessageList)                     // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.POST("/ack/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTMessageAck)                     // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.POST("/delete/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTMessageDelete)               // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.GET("/conversation/list/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETConversationList)   // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.POST("/conversation/read/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.POSTConversationRead) // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.GET("/unread/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETUnreadCount)                   // 应用限流中间件, jwt鉴权中间件(强制)
+			messageAPI.GET("/stream/", midware.MiddlewareRateLimitWithRedis(rateLimit, time.Second), midware.MiddlewareAuth(true), api.GETChatStream)                    // 应用限流中间件, jwt鉴权中间件(强制)
 		}
 	}
 
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 自定义错误类型
+var ErrorUserBlocked = errors.New("双方之间存在拉黑关系")
+
+// 检查两名用户之间是否存在任一方向的拉黑关系
+func checkBlocked(userID uint, otherID uint) (isBlocked bool) {
+	return repo.CheckUserBlocks(context.TODO(), userID, otherID) || repo.CheckUserBlocks(context.TODO(), otherID, userID)
+}
+
+// 批量检查用户与各用户之间是否存在任一方向的拉黑关系 返回值与otherIDs一一对应
+func checkBlockedBatch(userID uint, otherIDs []uint) (isBlocked []bool) {
+	isBlocking := repo.CheckUserBlocksBatch(context.TODO(), userID, otherIDs)
+	isBlockedBy := repo.CheckUserBlockersBatch(context.TODO(), userID, otherIDs)
+	isBlocked = make([]bool, len(otherIDs))
+	for i := range otherIDs {
+		isBlocked[i] = isBlocking[i] || isBlockedBy[i]
+	}
+	return isBlocked
+}
+
+// 过滤与请求用户之间存在拉黑关系的用户 未登录时不过滤
+func filterBlocked(ctx *gin.Context, userIDs []uint) (filtered []uint) {
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
+	if req_id == nil {
+		return userIDs
+	}
+	isBlocked := checkBlockedBatch(req_id.(uint), userIDs)
+	filtered = make([]uint, 0, len(userIDs))
+	for i, userID := range userIDs {
+		if !isBlocked[i] {
+			filtered = append(filtered, userID)
+		}
+	}
+	return filtered
+}
+
+// 拉黑/取消拉黑
+func Block(ctx *gin.Context, req *request.BlockReq) (resp *response.BlockResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 拉黑/取消拉黑
+	if req.Action_Type == 1 {
+		// 拉黑 双方之间的关注关系将被解除
+		err = repo.CreateUserBlocks(context.TODO(), req_id.(uint), req.To_User_ID)
+		if err != nil {
+			utility.Logger().Errorf("CreateUserBlocks err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		// 取消拉黑
+		err = repo.DeleteUserBlocks(context.TODO(), req_id.(uint), req.To_User_ID)
+		if err != nil {
+			utility.Logger().Errorf("DeleteUserBlocks err: %v", err)
+			return nil, err
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.BlockResp{}, nil
+}
+
+// 获取拉黑列表
+func BlockList(ctx *gin.Context, req *request.BlockListReq) (resp *response.BlockListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 读取请求用户拉黑列表
+	blocks, err := repo.ReadUserBlocks(context.TODO(), req_id.(uint))
+	if err != nil {
+		utility.Logger().Errorf("ReadUserBlocks err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.BlockListResp{User_List: make([]response.User, 0, len(blocks))} // 初始化响应
+	blockIDs := make([]uint, 0, len(blocks))
+	for _, block := range blocks {
+		blockIDs = append(blockIDs, block.ID)
+	}
+	blockInfos := readUserInfoBatch(ctx, blockIDs) // 批量读取被拉黑用户信息
+	for _, blockInfo := range blockInfos {
+		if blockInfo == nil {
+			continue // 跳过读取失败的用户
+		}
+
+		// 将该用户加入列表
+		resp.User_List = append(resp.User_List, *blockInfo)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// 聊天事件类型
+const (
+	chatEventMessage   = "message"   // 新消息
+	chatEventTyping    = "typing"    // 正在输入
+	chatEventDelivered = "delivered" // 已送达
+	chatEventRead      = "read"      // 已读
+	chatEventRecall    = "recall"    // 撤回消息
+	chatEventDelete    = "delete"    // 仅为自己删除消息(仅推送给请求用户的连接)
+)
+
+const chatMaxPayloadBytes = 4096 // 客户端上行事件的最大长度
+
+// 推送聊天事件给各用户在所有实例上的连接(失败时仅记录错误 客户端可回退至轮询)
+func pushChatEvent(event *response.ChatEvent, userIDs ...uint) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		utility.Logger().Errorf("json.Marshal err: %v", err)
+		return
+	}
+	for _, userID := range userIDs {
+		err = repo.PublishChatEvent(context.TODO(), userID, string(payload))
+		if err != nil {
+			utility.Logger().Errorf("PublishChatEvent err: %v", err)
+		}
+	}
+}
+
+// 处理客户端上行的聊天事件(转发正在输入事件 确认送达与已读 不合法的事件将被忽略)
+func handleChatEvent(userID uint, event *request.ChatEvent) {
+	if event.To_User_ID == 0 || event.To_User_ID == userID || checkBlocked(userID, event.To_User_ID) {
+		return
+	}
+
+	switch event.Type {
+	case chatEventTyping:
+		pushChatEvent(&response.ChatEvent{
+			Type:         chatEventTyping,
+			From_User_ID: userID,
+			To_User_ID:   event.To_User_ID,
+			Create_Time:  time.Now().UnixMilli(),
+		}, event.To_User_ID)
+	case chatEventDelivered, chatEventRead:
+		if event.Message_ID == 0 {
+			return
+		}
+		_ = ackMessages(userID, event.To_User_ID, event.Message_ID, event.Type)
+	}
+}
+
+// 建立聊天WebSocket连接 推送新消息、正在输入及已读事件 (升级成功后阻塞至连接关闭)
+func ChatStream(ctx *gin.Context, req *request.ChatStreamReq) (err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return errors.New("无法获取请求用户ID")
+	}
+
+	// 监听请求用户的聊天事件
+	events, cancel, err := repo.ListenChatEvents(context.TODO(), req_id.(uint))
+	if err != nil {
+		utility.Logger().Errorf("ListenChatEvents err: %v", err)
+		return err
+	}
+	defer cancel()
+
+	server := websocket.Server{Handler: func(conn *websocket.Conn) { // 不校验Origin 以支持非浏览器客户端
+		conn.MaxPayloadBytes = chatMaxPayloadBytes
+
+		// 读取客户端上行事件 连接关闭时结束
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				var data []byte
+				err := websocket.Message.Receive(conn, &data)
+				if err != nil {
+					return
+				}
+				event := &request.ChatEvent{}
+				if json.Unmarshal(data, event) != nil {
+					continue // 忽略无法解析的事件
+				}
+				handleChatEvent(req_id.(uint), event)
+			}
+		}()
+
+		// 推送下行事件 连接关闭或分发中心关闭时结束
+		for {
+			select {
+			case payload, ok := <-events:
+				if !ok {
+					return
+				}
+				if websocket.Message.Send(conn, payload) != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}}
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+
+	return nil
+}
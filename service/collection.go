@@ -0,0 +1,233 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const collectionKindSeries = 1 // 合集(仅可收录自己的作品)
+const collectionKindFolder = 2 // 收藏夹(可收录任意已发布视频)
+const collectionPageSize = 30  // 合集视频列表单页默认返回的视频数量
+
+// 自定义错误类型
+var ErrorCollectionInaccessible = errors.New("合集不存在或无权访问")
+
+// 检查合集是否属于指定用户
+func checkUserCollections(userID uint, collectionID uint) (isIts bool) {
+	collection, err := repo.ReadCollectionBasics(context.TODO(), collectionID)
+	return err == nil && collection.OwnerID == userID
+}
+
+// 创建/编辑/删除合集
+func Collection(ctx *gin.Context, req *request.CollectionReq) (resp *response.CollectionResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	resp = &response.CollectionResp{} // 初始化响应
+	if req.Action_Type == 1 {
+		// 创建合集
+		kind := req.Kind
+		if kind == 0 {
+			kind = collectionKindFolder // 默认为收藏夹
+		}
+		collection, err := repo.CreateCollection(context.TODO(), req_id.(uint), req.Name, kind, req.Visibility != 2) // 默认为公开
+		if err != nil {
+			utility.Logger().Errorf("CreateCollection err: %v", err)
+			return nil, err
+		}
+		resp.Collection_ID = collection.ID
+	} else if req.Action_Type == 2 {
+		// 编辑合集
+		if !checkUserCollections(req_id.(uint), req.Collection_ID) { // 若非请求用户的合集则拒绝编辑
+			return nil, ErrorCollectionInaccessible
+		}
+
+		updates := make(map[string]any, 2)
+		if req.Name != "" {
+			updates["name"] = req.Name
+		}
+		if req.Visibility != 0 {
+			updates["is_public"] = req.Visibility == 1
+		}
+		err = repo.UpdateCollection(context.TODO(), req.Collection_ID, updates)
+		if err != nil {
+			utility.Logger().Errorf("UpdateCollection err: %v", err)
+			return nil, err
+		}
+		resp.Collection_ID = req.Collection_ID
+	} else if req.Action_Type == 3 {
+		// 删除合集
+		if !checkUserCollections(req_id.(uint), req.Collection_ID) { // 若非请求用户的合集则拒绝删除
+			return nil, ErrorCollectionInaccessible
+		}
+
+		err = repo.DeleteCollection(context.TODO(), req.Collection_ID, false)
+		if err != nil {
+			utility.Logger().Errorf("DeleteCollection err: %v", err)
+			return nil, err
+		}
+		resp.Collection_ID = req.Collection_ID
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return resp, nil
+}
+
+// 获取用户合集列表
+func CollectionList(ctx *gin.Context, req *request.CollectionListReq) (resp *response.CollectionListResp, err error) {
+	// 获取请求用户ID
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
+
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取目标用户合集列表 本人请求时包含私密合集
+	includePrivate := req_id != nil && req_id.(uint) == req.User_ID
+	collections, err := repo.ReadUserCollections(context.TODO(), req.User_ID, includePrivate)
+	if err != nil {
+		utility.Logger().Errorf("ReadUserCollections err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.CollectionListResp{Collection_List: make([]response.Collection, 0, len(collections))} // 初始化响应
+	for _, collection := range collections {
+		// 将该合集加入列表
+		resp.Collection_List = append(resp.Collection_List, response.Collection{
+			ID:          collection.ID,
+			Owner_ID:    collection.OwnerID,
+			Name:        collection.Name,
+			Kind:        collection.Kind,
+			Is_Public:   collection.IsPublic,
+			Video_Count: collection.ItemsCount,
+		})
+	}
+
+	return resp, nil
+}
+
+// 向合集添加/移除视频
+func CollectionItem(ctx *gin.Context, req *request.CollectionItemReq) (resp *response.CollectionItemResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 读取合集基本信息
+	collection, err := repo.ReadCollectionBasics(context.TODO(), req.Collection_ID)
+	if err != nil || collection.OwnerID != req_id.(uint) { // 若非请求用户的合集则拒绝操作
+		return nil, ErrorCollectionInaccessible
+	}
+
+	if req.Action_Type == 1 {
+		// 添加视频
+		video, err := repo.ReadVideoBasics(context.TODO(), req.Video_ID)
+		if err != nil || video.IsDraft || video.IsHidden { // 不允许收录草稿或被隐藏的视频
+			return nil, ErrorVideoInaccessible
+		}
+		if collection.Kind == collectionKindSeries && video.AuthorID != req_id.(uint) { // 合集仅可收录自己的作品
+			return nil, ErrorVideoInaccessible
+		}
+
+		err = repo.CreateCollectionItem(context.TODO(), req.Collection_ID, req.Video_ID)
+		if err != nil {
+			utility.Logger().Errorf("CreateCollectionItem err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		// 移除视频
+		err = repo.DeleteCollectionItem(context.TODO(), req.Collection_ID, req.Video_ID)
+		if err != nil {
+			utility.Logger().Errorf("DeleteCollectionItem err: %v", err)
+			return nil, err
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.CollectionItemResp{}, nil
+}
+
+// 调整合集中视频的顺序
+func CollectionMove(ctx *gin.Context, req *request.CollectionMoveReq) (resp *response.CollectionMoveResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	if !checkUserCollections(req_id.(uint), req.Collection_ID) { // 若非请求用户的合集则拒绝操作
+		return nil, ErrorCollectionInaccessible
+	}
+
+	err = repo.MoveCollectionItem(context.TODO(), req.Collection_ID, req.Video_ID, req.Position)
+	if err != nil {
+		utility.Logger().Errorf("MoveCollectionItem err: %v", err)
+		return nil, err
+	}
+
+	return &response.CollectionMoveResp{}, nil
+}
+
+// 分页获取合集中的视频列表
+func CollectionVideoList(ctx *gin.Context, req *request.CollectionVideoListReq) (resp *response.CollectionVideoListResp, err error) {
+	// 获取请求用户ID
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
+
+	// 读取合集基本信息 私密合集仅本人可见
+	collection, err := repo.ReadCollectionBasics(context.TODO(), req.Collection_ID)
+	if err != nil || (!collection.IsPublic && (req_id == nil || req_id.(uint) != collection.OwnerID)) {
+		return nil, ErrorCollectionInaccessible
+	}
+
+	// 私密账号的合集仅对本人及已关注者可见
+	err = checkUserVisible(ctx, collection.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分页读取合集条目
+	items, hasMore, nextOffset, err := readPage(repo.ReadCollectionItems, req.Collection_ID, req.Offset, req.Count, collectionPageSize)
+	if err != nil {
+		utility.Logger().Errorf("ReadCollectionItems err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.CollectionVideoListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	resp.Video_List = make([]response.Video, 0, len(items))
+	videoIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		videoIDs = append(videoIDs, item.VideoID)
+	}
+	videoInfos := readVideoInfoBatch(ctx, videoIDs) // 批量读取视频信息
+	for _, videoInfo := range videoInfos {
+		if videoInfo == nil {
+			continue // 跳过读取失败(如已删除)的视频
+		}
+
+		// 将该视频加入列表
+		resp.Video_List = append(resp.Video_List, *videoInfo)
+	}
+
+	return resp, nil
+}
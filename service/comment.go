@@ -8,13 +8,59 @@ import (
 
 	"context"
 	"errors"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const commentPageSize = 30 // 评论列表单页默认返回的评论数量
+const replyPageSize = 30   // 回复列表单页默认返回的回复数量
+
+const commentSortNewest = 1 // 按时间从新到旧
+const commentSortOldest = 2 // 按时间从旧到新
+const commentSortHot = 3    // 按热度从高到低
+
+const commentPolicyOff = 2       // 关闭评论
+const commentPolicyFollowers = 3 // 仅粉丝可评论
+const commentPolicyFriends = 4   // 仅互相关注的好友可评论
+
 // 自定义错误类型
 var ErrorCommentInaccessible = errors.New("评论不存在或无权访问")
+var ErrorCommentRestricted = errors.New("视频作者已限制评论")
+var ErrorCommentEditExpired = errors.New("评论已超出可编辑时限")
+
+// 检查用户能否在视频下发表评论 视频作者本人不受评论权限限制
+func checkCommentAllowed(userID uint, videoID uint) (err error) {
+	video, err := repo.ReadVideoBasics(context.TODO(), videoID)
+	if err != nil {
+		return ErrorVideoInaccessible
+	}
+	if video.AuthorID == userID {
+		return nil
+	}
+	if checkBlocked(userID, video.AuthorID) { // 双方之间存在拉黑关系时拒绝评论
+		return ErrorUserBlocked
+	}
+
+	switch video.CommentPolicy {
+	case commentPolicyOff:
+		return ErrorCommentRestricted
+	case commentPolicyFollowers:
+		if !repo.CheckUserFollows(context.TODO(), userID, video.AuthorID) {
+			return ErrorCommentRestricted
+		}
+	case commentPolicyFriends:
+		if !repo.CheckUserFollows(context.TODO(), userID, video.AuthorID) || !repo.CheckUserFollows(context.TODO(), video.AuthorID, userID) {
+			return ErrorCommentRestricted
+		}
+	}
+	return nil
+}
+
+// 检查请求用户是否为视频作者
+func checkVideoAuthor(userID uint, videoID uint) (isAuthor bool) {
+	video, err := repo.ReadVideoBasics(context.TODO(), videoID)
+	return err == nil && video.AuthorID == userID
+}
 
 // 评论/删除评论
 func Comment(ctx *gin.Context, req *request.CommentReq) (resp *response.CommentResp, err error) {
@@ -29,12 +75,40 @@ func Comment(ctx *gin.Context, req *request.CommentReq) (resp *response.CommentR
 	resp = &response.CommentResp{} // 初始化响应
 	if req.Action_Type == 1 {
 		// 创建评论
+		if !checkVideoPublished(req.Video_ID) { // 不允许评论草稿或不存在的视频
+			return nil, ErrorVideoInaccessible
+		}
+		err = checkCommentAllowed(req_id.(uint), req.Video_ID) // 检查视频作者设置的评论权限
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Parent_ID != 0 { // 回复时所回复的评论须处于请求视频下且未被隐藏
+			parent, err := repo.ReadCommentBasics(context.TODO(), req.Parent_ID)
+			if err != nil || parent.VideoID != req.Video_ID || parent.IsHidden {
+				return nil, ErrorCommentInaccessible
+			}
+			if checkBlocked(req_id.(uint), parent.AuthorID) { // 与所回复评论的作者之间存在拉黑关系时拒绝回复
+				return nil, ErrorUserBlocked
+			}
+		}
+
+		// 过滤评论敏感词
+		content, needReview, err := filterText(req.Comment_Text)
+		if err != nil {
+			return nil, err
+		}
+
 		// 存储评论信息
-		comment, err := repo.CreateComment(context.TODO(), req_id.(uint), req.Video_ID, req.Comment_Text)
+		comment, err := repo.CreateComment(context.TODO(), req_id.(uint), req.Video_ID, content, req.Parent_ID)
 		if err != nil {
 			utility.Logger().Errorf("CreateComment err: %v", err)
 			return nil, err
 		}
+		if needReview { // 评论包含送审类敏感词时送审
+			sendToReview(reportTargetComment, comment.ID)
+		}
+		createMentions(req_id.(uint), mentionTargetComment, comment.ID, content, false) // 解析评论中的提及
 
 		// 读取评论信息 根据API文档强制要求将其加入响应
 		commentInfo, err := readCommentInfo(ctx, comment.ID)
@@ -50,13 +124,16 @@ func Comment(ctx *gin.Context, req *request.CommentReq) (resp *response.CommentR
 		// 删除评论信息
 		isReqUsers := repo.CheckUserComments(context.TODO(), req_id.(uint), req.Comment_ID)
 		isReqVideos := repo.CheckVideoComments(context.TODO(), req.Video_ID, req.Comment_ID)
-		if !isReqUsers || !isReqVideos { // 若非请求用户创建或非处于请求视频下则拒绝删除
+		if !isReqVideos { // 若非处于请求视频下则拒绝删除
+			return nil, ErrorCommentInaccessible
+		}
+		if !isReqUsers && !checkVideoAuthor(req_id.(uint), req.Video_ID) { // 若既非请求用户创建 请求用户也非视频作者则拒绝删除
 			return nil, ErrorCommentInaccessible
 		}
 
-		err = repo.DeleteComment(context.TODO(), req.Comment_ID, true) // 永久删除
+		err = repo.TrashComment(context.TODO(), req.Comment_ID) // 移入回收站 其下的回复一并删除
 		if err != nil {
-			utility.Logger().Errorf("DeleteComment err: %v", err)
+			utility.Logger().Errorf("TrashComment err: %v", err)
 			return nil, err
 		}
 	} else {
@@ -67,22 +144,64 @@ func Comment(ctx *gin.Context, req *request.CommentReq) (resp *response.CommentR
 	return resp, nil
 }
 
-// 获取评论列表
+// 分页获取评论列表
 func CommentList(ctx *gin.Context, req *request.CommentListReq) (resp *response.CommentListResp, err error) {
-	// 读取目标视频评论列表
-	comments, err := repo.FindCommentsByCreatedAt(context.TODO(), req.Video_ID, time.Now().Unix(), false, -1) // 倒序向过去查找 不限数量
+	count := req.Count
+	if count == 0 {
+		count = commentPageSize
+	}
+
+	// 按排序方式读取目标视频顶层评论列表(多读取一条以判断是否还有更多)
+	var commentIDs []uint
+	switch req.Sort_Type {
+	case 0, commentSortNewest: // 游标为上一页最后一条评论的ID
+		commentIDs, err = repo.FindVideoCommentsList(context.TODO(), req.Video_ID, uint(req.Cursor), false, count+1)
+	case commentSortOldest: // 游标为上一页最后一条评论的ID
+		commentIDs, err = repo.FindVideoCommentsList(context.TODO(), req.Video_ID, uint(req.Cursor), true, count+1)
+	case commentSortHot: // 游标为分页偏移量
+		commentIDs, err = repo.FindVideoCommentsHot(context.TODO(), req.Video_ID, int(req.Cursor), count+1)
+	default:
+		utility.Logger().Errorf("Invalid sort_type err: %v", req.Sort_Type)
+		return nil, errors.New("排序方式有误")
+	}
 	if err != nil {
-		utility.Logger().Errorf("FindCommentsByCreatedAt err: %v", err)
+		utility.Logger().Errorf("FindVideoComments err: %v", err)
 		return nil, err
 	}
 
-	resp = &response.CommentListResp{Comment_List: make([]response.Comment, 0, len(comments))} // 初始化响应
-	for _, comment := range comments {
-		// 读取评论信息
-		commentInfo, err := readCommentInfo(ctx, comment.ID)
-		if err != nil {
-			utility.Logger().Errorf("readCommentInfo err: %v", err)
-			continue // 跳过本条评论
+	resp = &response.CommentListResp{Next_Cursor: req.Cursor} // 初始化响应
+	if len(commentIDs) > count {
+		commentIDs = commentIDs[:count]
+		resp.Has_More = true
+	}
+	if req.Sort_Type == commentSortHot {
+		resp.Next_Cursor += int64(len(commentIDs))
+	} else if len(commentIDs) > 0 {
+		resp.Next_Cursor = int64(commentIDs[len(commentIDs)-1])
+	}
+
+	// 读取置顶评论 置顶评论仅出现在首页顶部
+	var pinnedID uint
+	video, err := repo.ReadVideoBasics(context.TODO(), req.Video_ID)
+	if err == nil {
+		pinnedID = video.PinnedCommentID
+	}
+	resp.Comment_List = make([]response.Comment, 0, len(commentIDs)+1)
+	if pinnedID != 0 && req.Cursor == 0 {
+		pinnedInfos := readCommentInfoBatch(ctx, []uint{pinnedID})
+		if pinnedInfos[0] != nil {
+			pinnedInfos[0].Is_Pinned = true
+			resp.Comment_List = append(resp.Comment_List, *pinnedInfos[0])
+		}
+	}
+
+	commentInfos := readCommentInfoBatch(ctx, commentIDs) // 批量读取评论信息
+	for _, commentInfo := range commentInfos {
+		if commentInfo == nil {
+			continue // 跳过读取失败的评论
+		}
+		if commentInfo.ID == pinnedID {
+			continue // 跳过已置顶的评论
 		}
 
 		// 将该评论加入列表
@@ -91,3 +210,218 @@ func CommentList(ctx *gin.Context, req *request.CommentListReq) (resp *response.
 
 	return resp, nil
 }
+
+// 分页获取顶层评论下的回复列表
+func CommentReplyList(ctx *gin.Context, req *request.CommentReplyListReq) (resp *response.CommentReplyListResp, err error) {
+	// 读取顶层评论基本信息 仅允许展开未被隐藏的顶层评论
+	root, err := repo.ReadCommentBasics(context.TODO(), req.Comment_ID)
+	if err != nil || root.RootID != 0 || root.IsHidden || !checkVideoPublished(root.VideoID) {
+		return nil, ErrorCommentInaccessible
+	}
+
+	// 分页读取回复列表
+	replies, hasMore, nextOffset, err := readPage(repo.FindCommentReplies, req.Comment_ID, req.Offset, req.Count, replyPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindCommentReplies err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.CommentReplyListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	resp.Reply_List = make([]response.Comment, 0, len(replies))
+	replyIDs := make([]uint, 0, len(replies))
+	for _, reply := range replies {
+		replyIDs = append(replyIDs, reply.ID)
+	}
+	replyInfos := readCommentInfoBatch(ctx, replyIDs) // 批量读取回复信息
+	for _, replyInfo := range replyInfos {
+		if replyInfo == nil {
+			continue // 跳过读取失败的回复
+		}
+
+		// 将该回复加入列表
+		resp.Reply_List = append(resp.Reply_List, *replyInfo)
+	}
+
+	return resp, nil
+}
+
+// 评论点赞/取消赞
+func CommentLike(ctx *gin.Context, req *request.CommentLikeReq) (resp *response.CommentLikeResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 存储点赞信息
+	if req.Action_Type == 1 {
+		// 点赞 不允许点赞被隐藏或不存在的评论
+		comment, err := repo.ReadCommentBasics(context.TODO(), req.Comment_ID)
+		if err != nil || comment.IsHidden || !checkVideoPublished(comment.VideoID) {
+			return nil, ErrorCommentInaccessible
+		}
+		err = repo.CreateUserLikes(context.TODO(), req_id.(uint), req.Comment_ID)
+		if err != nil {
+			utility.Logger().Errorf("CreateUserLikes err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		// 取消赞
+		err = repo.DeleteUserLikes(context.TODO(), req_id.(uint), req.Comment_ID)
+		if err != nil {
+			utility.Logger().Errorf("DeleteUserLikes err: %v", err)
+			return nil, err
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.CommentLikeResp{}, nil
+}
+
+// 置顶/取消置顶评论
+func CommentPin(ctx *gin.Context, req *request.CommentPinReq) (resp *response.CommentPinResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 仅视频作者可置顶评论
+	if !checkVideoAuthor(req_id.(uint), req.Video_ID) {
+		return nil, ErrorVideoInaccessible
+	}
+
+	// 存储置顶信息
+	if req.Action_Type == 1 {
+		// 置顶 仅可置顶该视频下未被隐藏的顶层评论
+		comment, err := repo.ReadCommentBasics(context.TODO(), req.Comment_ID)
+		if err != nil || comment.VideoID != req.Video_ID || comment.RootID != 0 || comment.IsHidden {
+			return nil, ErrorCommentInaccessible
+		}
+		err = repo.UpdateVideoPinnedComment(context.TODO(), req.Video_ID, req.Comment_ID)
+		if err != nil {
+			utility.Logger().Errorf("UpdateVideoPinnedComment err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		// 取消置顶
+		err = repo.UpdateVideoPinnedComment(context.TODO(), req.Video_ID, 0)
+		if err != nil {
+			utility.Logger().Errorf("UpdateVideoPinnedComment err: %v", err)
+			return nil, err
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.CommentPinResp{}, nil
+}
+
+// 设置评论权限
+func CommentPolicy(ctx *gin.Context, req *request.CommentPolicyReq) (resp *response.CommentPolicyResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 仅视频作者可设置评论权限
+	if !checkVideoAuthor(req_id.(uint), req.Video_ID) {
+		return nil, ErrorVideoInaccessible
+	}
+
+	// 存储评论权限
+	err = repo.UpdateVideoCommentPolicy(context.TODO(), req.Video_ID, req.Comment_Policy)
+	if err != nil {
+		utility.Logger().Errorf("UpdateVideoCommentPolicy err: %v", err)
+		return nil, err
+	}
+
+	return &response.CommentPolicyResp{}, nil
+}
+
+// 编辑评论
+func CommentEdit(ctx *gin.Context, req *request.CommentEditReq) (resp *response.CommentEditResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 仅评论作者可编辑评论
+	comment, err := repo.ReadCommentBasics(context.TODO(), req.Comment_ID)
+	if err != nil || comment.AuthorID != req_id.(uint) {
+		return nil, ErrorCommentInaccessible
+	}
+
+	// 过滤评论敏感词
+	content, needReview, err := filterText(req.Comment_Text)
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储评论信息
+	err = repo.UpdateComment(context.TODO(), req.Comment_ID, req_id.(uint), content)
+	if err == repo.ErrorEditWindowExpired {
+		return nil, ErrorCommentEditExpired
+	}
+	if err != nil {
+		utility.Logger().Errorf("UpdateComment err: %v", err)
+		return nil, err
+	}
+	if needReview { // 评论包含送审类敏感词时送审
+		sendToReview(reportTargetComment, req.Comment_ID)
+	}
+	createMentions(req_id.(uint), mentionTargetComment, req.Comment_ID, content, true) // 重新解析评论中的提及
+
+	// 读取评论信息
+	resp = &response.CommentEditResp{} // 初始化响应
+	commentInfo, err := readCommentInfo(ctx, req.Comment_ID)
+	if err != nil {
+		// 响应为编辑成功 但评论信息将为空
+		utility.Logger().Errorf("readCommentInfo err: %v", err)
+	} else {
+		resp.Comment = *commentInfo
+	}
+
+	return resp, nil
+}
+
+// 获取评论修订记录(仅审核员)
+func CommentRevisionList(ctx *gin.Context, req *request.CommentRevisionListReq) (resp *response.CommentRevisionListResp, err error) {
+	// 读取评论基本信息 审核员可查看被隐藏的评论
+	comment, err := repo.ReadCommentBasics(context.TODO(), req.Comment_ID)
+	if err != nil {
+		return nil, ErrorCommentInaccessible
+	}
+
+	// 读取修订记录
+	revisions, err := repo.ReadCommentRevisions(context.TODO(), req.Comment_ID)
+	if err != nil {
+		utility.Logger().Errorf("ReadCommentRevisions err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.CommentRevisionListResp{ // 初始化响应
+		Content:       comment.Content,
+		Revision_List: make([]response.CommentRevision, 0, len(revisions)),
+	}
+	for _, revision := range revisions {
+		resp.Revision_List = append(resp.Revision_List, response.CommentRevision{
+			ID:          revision.ID,
+			Editor_ID:   revision.EditorID,
+			Content:     revision.Content,
+			Create_Time: revision.CreatedAt.UnixMilli(),
+		})
+	}
+
+	return resp, nil
+}
@@ -14,120 +14,322 @@ import (
 
 // 读取指定用户信息 返回用户信息响应结构体
 func readUserInfo(ctx *gin.Context, userID uint) (userInfo *response.User, err error) {
+	userInfo = readUserInfoBatch(ctx, []uint{userID})[0] // 与批量读取共用同一流程
+	if userInfo == nil {
+		return nil, ErrorUserNotExists
+	}
+	return userInfo, nil
+}
+
+// 获取对外展示的个人签名 被隐藏时为空
+func visibleSignature(signature string, hidden bool) string {
+	if hidden {
+		return ""
+	}
+	return signature
+}
+
+// 读取指定评论信息 返回评论信息响应结构体
+func readCommentInfo(ctx *gin.Context, commentID uint) (commentInfo *response.Comment, err error) {
+	commentInfo = readCommentInfoBatch(ctx, []uint{commentID})[0] // 与批量读取共用同一流程 被隐藏或作者存在拉黑关系时同样不可见
+	if commentInfo == nil {
+		return nil, ErrorCommentInaccessible
+	}
+	return commentInfo, nil
+}
+
+// 批量读取指定用户信息 返回值与userIDs一一对应 读取失败时对应nil
+func readUserInfoBatch(ctx *gin.Context, userIDs []uint) (userInfos []*response.User) {
 	// 获取请求用户ID
 	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
 
 	// 读取目标用户基本信息
-	user, err := repo.ReadUserBasics(context.TODO(), userID)
+	uniqueIDs := distinctIDs(userIDs)
+	users, err := repo.ReadUserBasicsBatch(context.TODO(), uniqueIDs)
 	if err != nil {
-		utility.Logger().Errorf("ReadUserBasics err: %v", err)
-		return nil, err
+		utility.Logger().Errorf("ReadUserBasicsBatch err: %v", err)
+		return make([]*response.User, len(userIDs))
+	}
+	existingIDs := make([]uint, 0, len(uniqueIDs))
+	objectIDs := make([]string, 0, len(uniqueIDs))
+	for i, user := range users {
+		if user == nil {
+			utility.Logger().Errorf("ReadUserBasicsBatch err: 用户%v不存在或读取失败", uniqueIDs[i])
+			continue // 跳过该用户
+		}
+		existingIDs = append(existingIDs, uniqueIDs[i])
+		objectIDs = append(objectIDs, strconv.FormatUint(uint64(uniqueIDs[i]), 10))
 	}
 
-	followCount := uint(repo.CountUserFollows(context.TODO(), userID))      // 统计关注数
-	followerCount := uint(repo.CountUserFollowers(context.TODO(), userID))  // 统计粉丝数
-	workCount := uint(repo.CountUserWorks(context.TODO(), userID))          // 统计作品数
-	favoriteCount := uint(repo.CountUserFavorites(context.TODO(), userID))  // 统计点赞数
-	favoritedCount := uint(repo.CountUserFavorited(context.TODO(), userID)) // 统计获赞数
+	followCounts := repo.CountUserFollowsBatch(context.TODO(), existingIDs)      // 统计关注数
+	followerCounts := repo.CountUserFollowersBatch(context.TODO(), existingIDs)  // 统计粉丝数
+	workCounts := repo.CountUserWorksBatch(context.TODO(), existingIDs)          // 统计作品数
+	favoriteCounts := repo.CountUserFavoritesBatch(context.TODO(), existingIDs)  // 统计点赞数
+	favoritedCounts := repo.CountUserFavoritedBatch(context.TODO(), existingIDs) // 统计获赞数
 
 	// 检查是否被请求用户关注
-	isFollow := false
+	isFollows := make([]bool, len(existingIDs))
 	if req_id != nil {
-		isFollow = repo.CheckUserFollows(context.TODO(), req_id.(uint), uint(userID))
+		isFollows = repo.CheckUserFollowsBatch(context.TODO(), req_id.(uint), existingIDs)
 	}
 
-	// 获取头像及个人页背景图URL
-	avatarURL, _ := repo.GetAvatar(context.TODO(), strconv.FormatUint(uint64(userID), 10))
-	if err != nil {
-		utility.Logger().Errorf("GetAvatar err: %v", err) // 允许无法获取 仅记录错误
+	// 获取头像及个人页背景图URL 允许无法获取
+	avatarURLs := repo.GetAvatarBatch(context.TODO(), objectIDs)
+	backgroundImageURLs := repo.GetBackgroundImageBatch(context.TODO(), objectIDs)
+
+	infoMap := make(map[uint]*response.User, len(existingIDs))
+	j := 0 // existingIDs中的下标
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+		infoMap[existingIDs[j]] = &response.User{
+			ID:               existingIDs[j],
+			Name:             user.Username,
+			Follow_Count:     uint(followCounts[j]),
+			Follower_Count:   uint(followerCounts[j]),
+			Is_Follow:        isFollows[j],
+			Avatar:           avatarURLs[j],
+			Background_Image: backgroundImageURLs[j],
+			Signature:        visibleSignature(user.Signature, user.SignatureHidden),
+			Total_Favorited:  uint(favoritedCounts[j]),
+			Work_Count:       uint(workCounts[j]),
+			Favorite_Count:   uint(favoriteCounts[j]),
+			Is_Private:       user.IsPrivate,
+		}
+		j++
 	}
-	backgroundImageURL, _ := repo.GetBackgroundImage(context.TODO(), strconv.FormatUint(uint64(userID), 10))
-	if err != nil {
-		utility.Logger().Errorf("GetBackgroundImage err: %v", err) // 允许无法获取 仅记录错误
-	}
-
-	return &response.User{
-		ID:               userID,
-		Name:             user.Username,
-		Follow_Count:     followCount,
-		Follower_Count:   followerCount,
-		Is_Follow:        isFollow,
-		Avatar:           avatarURL,
-		Background_Image: backgroundImageURL,
-		Signature:        user.Signature,
-		Total_Favorited:  favoritedCount,
-		Work_Count:       workCount,
-		Favorite_Count:   favoriteCount,
-	}, nil
+	return expandInfos(userIDs, infoMap)
 }
 
-// 读取指定视频信息 返回视频信息响应结构体
-func readVideoInfo(ctx *gin.Context, videoID uint) (videoInfo *response.Video, err error) {
+// 批量读取指定视频信息 返回值与videoIDs一一对应 读取失败时对应nil
+func readVideoInfoBatch(ctx *gin.Context, videoIDs []uint) (videoInfos []*response.Video) {
+	return readVideoInfos(ctx, videoIDs, false)
+}
+
+// 批量读取指定视频信息 includeHidden为true时保留被隐藏的视频(仅供作者本人查看自己的草稿)
+func readVideoInfos(ctx *gin.Context, videoIDs []uint, includeHidden bool) (videoInfos []*response.Video) {
 	// 获取请求用户ID
 	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
 
 	// 读取目标视频基本信息
-	video, err := repo.ReadVideoBasics(context.TODO(), videoID)
+	uniqueIDs := distinctIDs(videoIDs)
+	videos, err := repo.ReadVideoBasicsBatch(context.TODO(), uniqueIDs)
 	if err != nil {
-		utility.Logger().Errorf("ReadVideoBasics err: %v", err)
-		return nil, err
+		utility.Logger().Errorf("ReadVideoBasicsBatch err: %v", err)
+		return make([]*response.Video, len(videoIDs))
+	}
+	blocked := make([]bool, len(videos)) // 作者是否与请求用户之间存在拉黑关系
+	if req_id != nil {
+		videoAuthorIDs := make([]uint, len(videos))
+		for i, video := range videos {
+			if video != nil {
+				videoAuthorIDs[i] = video.AuthorID
+			}
+		}
+		blocked = checkBlockedBatch(req_id.(uint), videoAuthorIDs)
+	}
+	var indexes []int // 视频存在的下标
+	existingIDs := make([]uint, 0, len(uniqueIDs))
+	objectIDs := make([]string, 0, len(uniqueIDs))
+	authorIDs := make([]uint, 0, len(uniqueIDs))
+	for i, video := range videos {
+		if video == nil {
+			utility.Logger().Errorf("ReadVideoBasicsBatch err: 视频%v不存在或读取失败", uniqueIDs[i])
+			continue // 跳过本条视频
+		}
+		if video.IsHidden && !includeHidden {
+			continue // 跳过被举报隐藏的视频
+		}
+		if blocked[i] {
+			continue // 跳过与请求用户之间存在拉黑关系的作者的视频
+		}
+		indexes = append(indexes, i)
+		existingIDs = append(existingIDs, uniqueIDs[i])
+		objectIDs = append(objectIDs, strconv.FormatUint(uint64(uniqueIDs[i]), 10))
+		authorIDs = append(authorIDs, video.AuthorID)
 	}
 
-	favoritedCount := uint(repo.CountVideoFavorited(context.TODO(), videoID)) // 统计获赞数
-	commentCount := uint(repo.CountVideoComments(context.TODO(), videoID))    // 统计评论数
+	favoritedCounts := repo.CountVideoFavoritedBatch(context.TODO(), existingIDs) // 统计获赞数
+	commentCounts := repo.CountVideoCommentsBatch(context.TODO(), existingIDs)    // 统计评论数
+	repostCounts := repo.CountVideoRepostsBatch(context.TODO(), existingIDs)      // 统计转发数
 
 	// 获取视频及封面URL
-	videoURL, coverURL, err := repo.GetVideo(context.TODO(), strconv.FormatUint(uint64(videoID), 10))
-	if err != nil {
-		utility.Logger().Errorf("GetVideo err: %v", err)
-		return nil, err
-	}
+	videoURLs, coverURLs := repo.GetVideoBatch(context.TODO(), objectIDs)
 
 	// 检查是否被请求用户点赞
-	isFavorite := false
+	isFavorites := make([]bool, len(existingIDs))
 	if req_id != nil {
-		isFavorite = repo.CheckUserFavorites(context.TODO(), req_id.(uint), videoID)
+		isFavorites = repo.CheckUserFavoritesBatch(context.TODO(), req_id.(uint), existingIDs)
 	}
 
 	// 读取作者信息
-	authorInfo, err := readUserInfo(ctx, video.AuthorID)
-	if err != nil {
-		utility.Logger().Errorf("readUserInfo err: %v", err)
-		return nil, err
-	}
-
-	return &response.Video{
-		ID:             videoID,
-		Author:         *authorInfo,
-		Play_URL:       videoURL,
-		Cover_URL:      coverURL,
-		Favorite_Count: favoritedCount,
-		Comment_Count:  commentCount,
-		Is_Favorite:    isFavorite,
-		Title:          video.Title,
-	}, nil
+	authorInfos := readUserInfoBatch(ctx, authorIDs)
+
+	// 读取标题中的提及
+	mentions := readMentionsBatch(mentionTargetVideo, existingIDs)
+
+	infoMap := make(map[uint]*response.Video, len(indexes))
+	for j, i := range indexes {
+		if videoURLs[j] == "" || coverURLs[j] == "" {
+			utility.Logger().Errorf("GetVideoBatch err: 视频%v外链获取失败", uniqueIDs[i])
+			continue // 跳过本条视频
+		}
+		if authorInfos[j] == nil {
+			utility.Logger().Errorf("readUserInfoBatch err: 视频%v作者信息读取失败", uniqueIDs[i])
+			continue // 跳过本条视频
+		}
+
+		infoMap[uniqueIDs[i]] = &response.Video{
+			ID:             uniqueIDs[i],
+			Author:         *authorInfos[j],
+			Play_URL:       videoURLs[j],
+			Cover_URL:      coverURLs[j],
+			Favorite_Count: uint(favoritedCounts[j]),
+			Comment_Count:  uint(commentCounts[j]),
+			Repost_Count:   uint(repostCounts[j]),
+			Is_Favorite:    isFavorites[j],
+			Title:          videos[i].Title,
+			Comment_Policy: videos[i].CommentPolicy,
+			Mentions:       mentions[j],
+		}
+	}
+	return expandInfos(videoIDs, infoMap)
 }
 
-// 读取指定评论信息 返回评论信息响应结构体
-func readCommentInfo(ctx *gin.Context, commentID uint) (commentInfo *response.Comment, err error) {
+// 批量读取指定评论信息 返回值与commentIDs一一对应 读取失败时对应nil
+func readCommentInfoBatch(ctx *gin.Context, commentIDs []uint) (commentInfos []*response.Comment) {
+	// 获取请求用户ID
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
+
 	// 读取目标评论基本信息
-	comment, err := repo.ReadCommentBasics(context.TODO(), commentID)
+	uniqueIDs := distinctIDs(commentIDs)
+	comments, err := repo.ReadCommentBasicsBatch(context.TODO(), uniqueIDs)
 	if err != nil {
-		utility.Logger().Errorf("ReadCommentBasics err: %v", err)
-		return nil, err
+		utility.Logger().Errorf("ReadCommentBasicsBatch err: %v", err)
+		return make([]*response.Comment, len(commentIDs))
+	}
+	blocked := make([]bool, len(comments)) // 作者是否与请求用户之间存在拉黑关系
+	if req_id != nil {
+		commentAuthorIDs := make([]uint, len(comments))
+		for i, comment := range comments {
+			if comment != nil {
+				commentAuthorIDs[i] = comment.AuthorID
+			}
+		}
+		blocked = checkBlockedBatch(req_id.(uint), commentAuthorIDs)
+	}
+	var indexes []int // 评论存在的下标
+	authorIDs := make([]uint, 0, len(uniqueIDs))
+	for i, comment := range comments {
+		if comment == nil {
+			utility.Logger().Errorf("ReadCommentBasicsBatch err: 评论%v不存在或读取失败", uniqueIDs[i])
+			continue // 跳过本条评论
+		}
+		if comment.IsHidden {
+			continue // 跳过被举报隐藏的评论
+		}
+		if blocked[i] {
+			continue // 跳过与请求用户之间存在拉黑关系的作者的评论
+		}
+		indexes = append(indexes, i)
+		authorIDs = append(authorIDs, comment.AuthorID)
 	}
 
 	// 读取作者信息
-	authorInfo, err := readUserInfo(ctx, comment.AuthorID)
+	authorInfos := readUserInfoBatch(ctx, authorIDs)
+
+	// 统计顶层评论回复数
+	rootIDs := make([]uint, 0, len(indexes))
+	for _, i := range indexes {
+		if comments[i].RootID == 0 {
+			rootIDs = append(rootIDs, uniqueIDs[i])
+		}
+	}
+	replyCounts := make(map[uint]int64, len(rootIDs))
+	for k, count := range repo.CountCommentRepliesBatch(context.TODO(), rootIDs) {
+		replyCounts[rootIDs[k]] = count
+	}
+
+	// 统计点赞数
+	existingIDs := make([]uint, 0, len(indexes))
+	for _, i := range indexes {
+		existingIDs = append(existingIDs, uniqueIDs[i])
+	}
+	likeCounts := repo.CountCommentLikedBatch(context.TODO(), existingIDs)
+
+	// 检查是否被请求用户点赞
+	isLikeds := make([]bool, len(existingIDs))
+	if req_id != nil {
+		isLikeds = repo.CheckUserLikesBatch(context.TODO(), req_id.(uint), existingIDs)
+	}
+
+	// 读取评论中的提及
+	mentions := readMentionsBatch(mentionTargetComment, existingIDs)
+
+	infoMap := make(map[uint]*response.Comment, len(indexes))
+	for j, i := range indexes {
+		if authorInfos[j] == nil {
+			utility.Logger().Errorf("readUserInfoBatch err: 评论%v作者信息读取失败", uniqueIDs[i])
+			continue // 跳过本条评论
+		}
+
+		comment := comments[i]
+		infoMap[uniqueIDs[i]] = &response.Comment{
+			ID:          uniqueIDs[i],
+			User:        *authorInfos[j],
+			Content:     comment.Content,
+			Create_Date: fmt.Sprintf("%02d-%02d", comment.CreatedAt.Month(), comment.CreatedAt.Day()), // mm-dd
+			Parent_ID:   comment.ParentID,
+			Root_ID:     comment.RootID,
+			Reply_Count: replyCounts[uniqueIDs[i]],
+			Like_Count:  likeCounts[j],
+			Is_Liked:    isLikeds[j],
+			Is_Edited:   comment.IsEdited,
+			Mentions:    mentions[j],
+		}
+	}
+	return expandInfos(commentIDs, infoMap)
+}
+
+// 按偏移量分页读取列表(多读取一条以判断是否还有更多) count为0时使用defaultCount
+func readPage[K any, T any](read func(ctx context.Context, key K, offset int, num int) ([]T, error),
+	key K, offset int, count int, defaultCount int) (items []T, hasMore bool, nextOffset int, err error) {
+	if count == 0 {
+		count = defaultCount
+	}
+	items, err = read(context.TODO(), key, offset, count+1)
 	if err != nil {
-		utility.Logger().Errorf("readUserInfo err: %v", err)
-		return nil, err
+		return nil, false, offset, err
+	}
+	if len(items) > count {
+		items = items[:count]
+		hasMore = true
 	}
+	return items, hasMore, offset + len(items), nil
+}
 
-	return &response.Comment{
-		ID:          commentID,
-		User:        *authorInfo,
-		Content:     comment.Content,
-		Create_Date: fmt.Sprintf("%02d-%02d", comment.CreatedAt.Month(), comment.CreatedAt.Day()), // mm-dd
-	}, nil
+// 去除重复ID 保留各ID首次出现的顺序
+func distinctIDs(ids []uint) (uniqueIDs []uint) {
+	uniqueIDs = make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+	return uniqueIDs
+}
+
+// 按ids展开读取结果 返回值与ids一一对应 infoMap中不存在时对应nil 重复ID各自持有独立结构体
+func expandInfos[T any](ids []uint, infoMap map[uint]*T) (infos []*T) {
+	infos = make([]*T, len(ids))
+	for i, id := range ids {
+		if info, ok := infoMap[id]; ok {
+			copied := *info
+			infos[i] = &copied
+		}
+	}
+	return infos
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"douyin/service/type/response"
+
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReadPage(t *testing.T) {
+	list := []int{1, 2, 3, 4, 5}
+	read := func(ctx context.Context, key uint, offset int, num int) ([]int, error) {
+		if key != 7 {
+			t.Errorf("read key = %d, want 7", key)
+		}
+		if offset >= len(list) {
+			return nil, nil
+		}
+		if offset+num > len(list) {
+			num = len(list) - offset
+		}
+		return list[offset : offset+num], nil
+	}
+
+	tests := []struct {
+		offset, count  int
+		want           []int
+		wantMore       bool
+		wantNextOffset int
+	}{
+		{0, 2, []int{1, 2}, true, 2},
+		{2, 2, []int{3, 4}, true, 4},
+		{4, 2, []int{5}, false, 5},
+		{3, 2, []int{4, 5}, false, 5}, // 恰好读完时无更多
+		{5, 2, nil, false, 5},
+		{0, 0, []int{1, 2, 3}, true, 3}, // count为0时使用默认值
+	}
+	for _, tt := range tests {
+		items, hasMore, nextOffset, err := readPage(read, 7, tt.offset, tt.count, 3)
+		if err != nil || hasMore != tt.wantMore || nextOffset != tt.wantNextOffset || !equalInts(items, tt.want) {
+			t.Errorf("readPage(offset=%d, count=%d) = %v, %v, %d, %v, want %v, %v, %d",
+				tt.offset, tt.count, items, hasMore, nextOffset, err, tt.want, tt.wantMore, tt.wantNextOffset)
+		}
+	}
+
+	readErr := errors.New("read failed")
+	_, hasMore, nextOffset, err := readPage(func(context.Context, uint, int, int) ([]int, error) { return nil, readErr }, 7, 4, 2, 3)
+	if err != readErr || hasMore || nextOffset != 4 {
+		t.Errorf("readPage(error) = %v, %d, %v, want false, 4, %v", hasMore, nextOffset, err, readErr)
+	}
+}
+
+func equalInts(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDistinctIDs(t *testing.T) {
+	got := distinctIDs([]uint{3, 1, 3, 2, 1})
+	if want := []uint{3, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("distinctIDs = %v, want %v", got, want)
+	}
+	if got := distinctIDs(nil); len(got) != 0 {
+		t.Errorf("distinctIDs(nil) = %v, want empty", got)
+	}
+}
+
+func TestExpandInfos(t *testing.T) {
+	infoMap := map[uint]*response.User{
+		1: {ID: 1, Name: "a"},
+		3: {ID: 3, Name: "c"},
+	}
+	ids := []uint{3, 2, 1, 3}
+
+	infos := expandInfos(ids, infoMap)
+	if len(infos) != len(ids) {
+		t.Fatalf("len(expandInfos) = %d, want %d", len(infos), len(ids))
+	}
+	for i, id := range ids {
+		if _, ok := infoMap[id]; !ok {
+			if infos[i] != nil {
+				t.Errorf("infos[%d] = %+v, want nil for missing ID %d", i, infos[i], id)
+			}
+			continue
+		}
+		if infos[i] == nil || infos[i].ID != id {
+			t.Errorf("infos[%d] = %+v, want ID %d", i, infos[i], id)
+		}
+	}
+
+	// 重复ID各自持有独立结构体 修改其一不影响其他条目及原结果
+	infos[0].Is_Follow = true
+	if infos[3].Is_Follow || infoMap[3].Is_Follow {
+		t.Error("duplicate IDs share the same struct")
+	}
+}
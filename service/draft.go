@@ -0,0 +1,174 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 自定义错误类型
+var ErrorDraftInaccessible = errors.New("草稿不存在或无权访问")
+var ErrorVideoInaccessible = errors.New("视频不存在或无权访问")
+
+// 检查草稿是否属于指定用户
+func checkUserDrafts(userID uint, draftID uint) (isIts bool) {
+	video, err := repo.ReadVideoBasics(context.TODO(), draftID)
+	return err == nil && video.IsDraft && video.AuthorID == userID
+}
+
+// 检查视频是否已公开发布且未被隐藏
+func checkVideoPublished(videoID uint) (isPublished bool) {
+	video, err := repo.ReadVideoBasics(context.TODO(), videoID)
+	return err == nil && !video.IsDraft && !video.IsHidden
+}
+
+// 创建/编辑/删除草稿
+func Draft(ctx *gin.Context, req *request.DraftReq) (resp *response.DraftResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	resp = &response.DraftResp{} // 初始化响应
+	if req.Action_Type == 1 {
+		// 创建草稿
+		if req.Data == nil || req.Title == "" {
+			return nil, errors.New("缺少视频数据或标题")
+		}
+		draftID, err := createVideo(req_id.(uint), req.Data, req.Title, true)
+		if err != nil {
+			return nil, err
+		}
+		resp.Draft_ID = draftID
+	} else if req.Action_Type == 2 {
+		// 编辑草稿
+		if !checkUserDrafts(req_id.(uint), req.Draft_ID) { // 若非请求用户的草稿则拒绝编辑
+			return nil, ErrorDraftInaccessible
+		}
+
+		// 编辑标题
+		if req.Title != "" {
+			title, needReview, err := filterText(req.Title) // 过滤标题敏感词
+			if err != nil {
+				return nil, err
+			}
+			err = repo.UpdateDraftTitle(context.TODO(), req.Draft_ID, title)
+			if err != nil {
+				utility.Logger().Errorf("UpdateDraftTitle err: %v", err)
+				return nil, err
+			}
+			if needReview { // 标题包含送审类敏感词时送审
+				sendToReview(reportTargetVideo, req.Draft_ID)
+			}
+		}
+
+		// 更换封面
+		if req.Cover != nil {
+			coverStream, err := req.Cover.Open()
+			if err != nil {
+				utility.Logger().Errorf("file.Open err: %v", err)
+				return nil, err
+			}
+			defer coverStream.Close() // 不保证自动关闭成功
+
+			err = repo.UploadCoverStream(context.TODO(), req.Draft_ID, coverStream)
+			if err == repo.ErrorImageTooLarge {
+				return nil, ErrorImageTooLarge
+			}
+			if err != nil {
+				utility.Logger().Errorf("UploadCoverStream err: %v", err)
+				return nil, err
+			}
+		}
+		resp.Draft_ID = req.Draft_ID
+	} else if req.Action_Type == 3 {
+		// 删除草稿
+		if !checkUserDrafts(req_id.(uint), req.Draft_ID) { // 若非请求用户的草稿则拒绝删除
+			return nil, ErrorDraftInaccessible
+		}
+
+		err = repo.TrashVideo(context.TODO(), req.Draft_ID) // 移入回收站 OSS对象待过期清除时一并移除
+		if err != nil {
+			utility.Logger().Errorf("TrashVideo err: %v", err)
+			return nil, err
+		}
+		resp.Draft_ID = req.Draft_ID
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return resp, nil
+}
+
+// 获取草稿列表
+func DraftList(ctx *gin.Context, req *request.DraftListReq) (resp *response.DraftListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 读取请求用户草稿列表
+	drafts, err := repo.ReadUserDrafts(context.TODO(), req_id.(uint))
+	if err != nil {
+		utility.Logger().Errorf("ReadUserDrafts err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.DraftListResp{Draft_List: make([]response.Draft, 0, len(drafts))} // 初始化响应
+	draftIDs := make([]uint, 0, len(drafts))
+	for _, draft := range drafts {
+		draftIDs = append(draftIDs, draft.ID)
+	}
+	draftInfos := readVideoInfos(ctx, draftIDs, true) // 批量读取草稿信息 送审中的草稿仍对作者本人可见以便修改或删除
+	for i, draftInfo := range draftInfos {
+		if draftInfo == nil {
+			continue // 跳过读取失败的草稿
+		}
+
+		// 将该草稿及其审核状态加入列表
+		resp.Draft_List = append(resp.Draft_List, response.Draft{Video: *draftInfo, Is_Hidden: drafts[i].IsHidden})
+	}
+
+	return resp, nil
+}
+
+// 发布草稿
+func DraftPublish(ctx *gin.Context, req *request.DraftPublishReq) (resp *response.DraftPublishResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	if !checkUserDrafts(req_id.(uint), req.Draft_ID) { // 若非请求用户的草稿则拒绝发布
+		return nil, ErrorDraftInaccessible
+	}
+
+	err = repo.PublishDraft(context.TODO(), req.Draft_ID)
+	if err != nil {
+		utility.Logger().Errorf("PublishDraft err: %v", err)
+		return nil, err
+	}
+
+	// 解析标题中的提及
+	video, err := repo.ReadVideoBasics(context.TODO(), req.Draft_ID)
+	if err != nil {
+		utility.Logger().Errorf("ReadVideoBasics err: %v", err) // 响应为发布成功 仅记录错误
+	} else {
+		createMentions(req_id.(uint), mentionTargetVideo, req.Draft_ID, video.Title, false)
+	}
+
+	return &response.DraftPublishResp{}, nil
+}
@@ -24,6 +24,9 @@ func Favorite(ctx *gin.Context, req *request.FavoriteReq) (resp *response.Favori
 	// 存储点赞信息
 	if req.Action_Type == 1 {
 		// 点赞
+		if !checkVideoPublished(req.Video_ID) { // 不允许点赞草稿或不存在的视频
+			return nil, ErrorVideoInaccessible
+		}
 		err = repo.CreateUserFavorites(context.TODO(), req_id.(uint), req.Video_ID)
 		if err != nil {
 			utility.Logger().Errorf("CreateUserFavorites err: %v", err)
@@ -46,6 +49,12 @@ func Favorite(ctx *gin.Context, req *request.FavoriteReq) (resp *response.Favori
 
 // 获取喜欢列表
 func FavoriteList(ctx *gin.Context, req *request.FavoriteListReq) (resp *response.FavoriteListResp, err error) {
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 读取目标用户信息
 	favorites, err := repo.ReadUserFavorites(context.TODO(), req.User_ID)
 	if err != nil {
@@ -55,12 +64,14 @@ func FavoriteList(ctx *gin.Context, req *request.FavoriteListReq) (resp *respons
 
 	// 读取目标用户喜欢列表
 	resp = &response.FavoriteListResp{Video_List: make([]response.Video, 0, len(favorites))} // 初始化响应
+	videoIDs := make([]uint, 0, len(favorites))
 	for _, video := range favorites {
-		// 读取视频信息
-		videoInfo, err := readVideoInfo(ctx, video.ID)
-		if err != nil {
-			utility.Logger().Errorf("readVideoInfo err: %v", err)
-			continue // 跳过本条视频
+		videoIDs = append(videoIDs, video.ID)
+	}
+	videoInfos := readVideoInfoBatch(ctx, videoIDs) // 批量读取视频信息
+	for _, videoInfo := range videoInfos {
+		if videoInfo == nil {
+			continue // 跳过读取失败的视频
 		}
 
 		// 将该视频加入列表
@@ -7,40 +7,117 @@ import (
 	"douyin/utility"
 
 	"context"
+	"errors"
 
 	"github.com/gin-gonic/gin"
 )
 
+const feedSize = 30    // 单次视频流最多返回的视频数量
+const maxFeedScans = 3 // 过滤已看视频时单次请求最多查找的次数
+
 // 视频流
 func Feed(ctx *gin.Context, req *request.FeedReq) (resp *response.FeedResp, err error) {
-	// 读取视频列表
-	videos, err := repo.FindVideosByCreatedAt(context.TODO(), req.Latest_Time, false, 30) // 倒序向过去查找 最多30条
-	if err != nil {
-		utility.Logger().Errorf("FindVideosByCreatedAt err: %v", err)
-		return nil, err
-	}
+	// 获取请求用户ID
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
 
 	// 初始化响应
 	resp = &response.FeedResp{
 		// Next_Time: 0, // 本次返回的视频中发布最早的时间 根据API文档默认为不发送
-		Video_List: make([]response.Video, 0, len(videos)),
+		Video_List: make([]response.Video, 0, feedSize),
+	}
+
+	// 读取视频列表 登录用户将过滤已看视频
+	videoIDs := make([]uint, 0, feedSize)
+	var firstScanIDs []uint // 首次查找结果 用于无未看视频时回退
+	var firstScanTime int64
+	latestTime := req.Latest_Time
+	for i := 0; i < maxFeedScans && len(videoIDs) < feedSize; i++ {
+		videos, err := repo.FindVideosByCreatedAt(context.TODO(), latestTime, false, feedSize) // 倒序向过去查找 最多30条
+		if err != nil {
+			utility.Logger().Errorf("FindVideosByCreatedAt err: %v", err)
+			return nil, err
+		}
+		if len(videos) == 0 { // 已无更旧视频
+			break
+		}
+
+		scanIDs := make([]uint, 0, len(videos))
+		for _, video := range videos {
+			scanIDs = append(scanIDs, video.ID)
+		}
+		if i == 0 {
+			firstScanIDs = scanIDs
+			firstScanTime = videos[len(videos)-1].CreatedAt.Unix()
+		}
+		if req_id == nil { // 未登录时不过滤
+			videoIDs = scanIDs
+			resp.Next_Time = videos[len(videos)-1].CreatedAt.Unix() * 1000 // 更新该时间戳 API文档有误 响应实为毫秒时间戳 故在此转换
+			break
+		}
+
+		isSeen := repo.CheckUserSeenBatch(context.TODO(), req_id.(uint), scanIDs)
+		for j, video := range videos {
+			if len(videoIDs) >= feedSize {
+				break
+			}
+			latestTime = video.CreatedAt.Unix()
+			resp.Next_Time = latestTime * 1000 // 更新该时间戳 API文档有误 响应实为毫秒时间戳 故在此转换
+			if !isSeen[j] {
+				videoIDs = append(videoIDs, video.ID)
+			}
+		}
 	}
-	if len(videos) > 0 { // 如果查找结果中有视频
-		resp.Next_Time = videos[len(videos)-1].CreatedAt.Unix() * 1000 // 更新该时间戳 API文档有误 响应实为毫秒时间戳 故在此转换
+	if len(videoIDs) == 0 && len(firstScanIDs) > 0 { // 已无未看视频时回退为不过滤
+		videoIDs = firstScanIDs
+		resp.Next_Time = firstScanTime * 1000 // 更新该时间戳 API文档有误 响应实为毫秒时间戳 故在此转换
 	}
 
+	// 批量读取视频信息
+	videoInfos := readVideoInfoBatch(ctx, videoIDs)
+
 	// 向响应中添加视频
-	for _, video := range videos {
-		// 读取视频信息
-		videoInfo, err := readVideoInfo(ctx, video.ID)
-		if err != nil {
-			utility.Logger().Errorf("readVideoInfo err: %v", err)
-			continue // 跳过本条视频
+	deliveredIDs := make([]uint, 0, len(videoInfos))
+	for _, videoInfo := range videoInfos {
+		if videoInfo == nil {
+			continue // 跳过读取失败的视频
 		}
 
 		// 将该视频加入列表
 		resp.Video_List = append(resp.Video_List, *videoInfo)
+		deliveredIDs = append(deliveredIDs, videoInfo.ID)
+	}
+
+	// 记录已推送视频
+	if req_id != nil {
+		err = repo.SetUserSeen(context.TODO(), req_id.(uint), deliveredIDs)
+		if err != nil {
+			utility.Logger().Errorf("SetUserSeen err: %v", err) // 响应为获取成功 仅记录错误
+		}
 	}
 
 	return resp, nil
 }
+
+// 记录已看视频
+func Watch(ctx *gin.Context, req *request.WatchReq) (resp *response.WatchResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 检查视频是否已公开发布
+	if !checkVideoPublished(req.Video_ID) {
+		return nil, ErrorVideoInaccessible
+	}
+
+	// 存储已看视频
+	err = repo.SetUserSeen(context.TODO(), req_id.(uint), []uint{req.Video_ID})
+	if err != nil {
+		utility.Logger().Errorf("SetUserSeen err: %v", err)
+		return nil, err
+	}
+
+	return &response.WatchResp{}, nil
+}
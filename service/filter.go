@@ -0,0 +1,29 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/utility"
+
+	"context"
+	"errors"
+)
+
+// 自定义错误类型
+var ErrorSensitiveContent = errors.New("内容包含敏感词")
+
+// 过滤敏感词 返回替换后的文本及是否需要送审 包含拒绝类敏感词时返回错误
+func filterText(text string) (filtered string, needReview bool, err error) {
+	filtered, action := utility.FilterText(text)
+	if action == utility.FilterActionReject {
+		return "", false, ErrorSensitiveContent
+	}
+	return filtered, action == utility.FilterActionReview, nil
+}
+
+// 将包含送审类敏感词的内容送审 内容将被隐藏直至审核员处理
+func sendToReview(targetType int, targetID uint) {
+	_, err := repo.CreateReview(context.TODO(), targetType, targetID, "包含送审类敏感词")
+	if err != nil {
+		utility.Logger().Errorf("CreateReview err: %v", err) // 响应为操作成功 仅记录错误
+	}
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"douyin/conf"
+)
+
+// 初始化服务层
+func Init() {
+	linkKey = []byte(conf.Cfg().Share.LinkKey)
+}
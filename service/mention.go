@@ -0,0 +1,51 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+)
+
+const mentionTargetVideo = 1   // 视频标题中的提及
+const mentionTargetComment = 2 // 评论中的提及
+
+const maxMentions = 10 // 单条文本中最多解析的提及数量
+
+// 解析并存储文本中的提及 同时通知新被提及的用户 replace为true时替换对象中原有的提及 (不影响主流程 出错时仅记录错误)
+func createMentions(actorID uint, targetType int, targetID uint, text string, replace bool) {
+	spans := utility.ParseMentions(text)
+	if len(spans) == 0 && !replace {
+		return
+	}
+	if len(spans) > maxMentions {
+		spans = spans[:maxMentions]
+	}
+	_, err := repo.CreateMentions(context.TODO(), actorID, targetType, targetID, spans)
+	if err != nil {
+		utility.Logger().Errorf("CreateMentions err: %v", err)
+	}
+}
+
+// 批量读取提及对象中的提及 返回值与targetIDs一一对应 读取失败时对应空列表
+func readMentionsBatch(targetType int, targetIDs []uint) (mentions [][]response.Mention) {
+	mentions = make([][]response.Mention, len(targetIDs))
+	indexes := make(map[uint][]int, len(targetIDs)) // 对象ID对应的下标
+	for i, targetID := range targetIDs {
+		mentions[i] = make([]response.Mention, 0)
+		indexes[targetID] = append(indexes[targetID], i)
+	}
+
+	records, err := repo.FindMentionsBatch(context.TODO(), targetType, targetIDs)
+	if err != nil {
+		utility.Logger().Errorf("FindMentionsBatch err: %v", err)
+		return mentions
+	}
+	for _, record := range records {
+		for _, i := range indexes[record.TargetID] {
+			mentions[i] = append(mentions[i], response.Mention{User_ID: record.UserID, Offset: record.Offset, Length: record.Length})
+		}
+	}
+	return mentions
+}
@@ -8,10 +8,184 @@ import (
 
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const conversationPageSize = 30 // 会话列表单页默认返回的会话数量
+
+// 消息状态
+const (
+	messageStatusSent      = 1 // 已发送
+	messageStatusDelivered = 2 // 已送达
+	messageStatusRead      = 3 // 已读
+)
+
+// 消息类型
+const (
+	messageTypeText  = 1 // 文本
+	messageTypeImage = 2 // 图片
+	messageTypeVideo = 3 // 视频分享
+	messageTypeUser  = 4 // 名片(用户分享)
+)
+
+// 自定义错误类型
+var ErrorMessageInaccessible = errors.New("消息不存在或无权确认")
+var ErrorMessageInvalid = errors.New("消息内容缺失或与消息类型不符")
+var ErrorShareInaccessible = errors.New("分享的视频或用户不存在或不可见")
+var ErrorMessageRecallExpired = errors.New("消息已超出可撤回时限")
+var ErrorImageTooLarge = errors.New("图片文件或尺寸过大")
+
+// 校验并准备消息负载 图片消息将上传图片并返回其对象ID 视频分享与名片消息返回视频ID与用户ID
+func prepareMessagePayload(ctx *gin.Context, userID uint, req *request.MessageReq, msgType int) (payload string, err error) {
+	switch msgType {
+	case messageTypeText:
+		if req.Content == "" {
+			return "", ErrorMessageInvalid
+		}
+		return "", nil
+	case messageTypeImage:
+		if req.Image == nil {
+			return "", ErrorMessageInvalid
+		}
+		imageStream, err := req.Image.Open()
+		if err != nil {
+			utility.Logger().Errorf("file.Open err: %v", err)
+			return "", err
+		}
+		defer imageStream.Close() // 不保证自动关闭成功
+
+		objectID := strconv.FormatUint(uint64(userID), 10) + "_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+		err = repo.UploadMessageImageStream(context.TODO(), objectID, imageStream)
+		if err == repo.ErrorImageTooLarge {
+			return "", ErrorImageTooLarge
+		}
+		if err != nil {
+			utility.Logger().Errorf("UploadMessageImageStream err: %v", err)
+			return "", err
+		}
+		return objectID, nil
+	case messageTypeVideo:
+		if req.Video_ID == 0 {
+			return "", ErrorMessageInvalid
+		}
+		video, err := repo.ReadVideoBasics(context.TODO(), req.Video_ID)
+		if err != nil || video.IsDraft || video.IsHidden { // 仅可分享已发布且未被隐藏的视频
+			return "", ErrorShareInaccessible
+		}
+		if checkBlocked(userID, video.AuthorID) || checkUserVisible(ctx, video.AuthorID) != nil { // 仅可分享请求用户可见的视频
+			return "", ErrorShareInaccessible
+		}
+		return strconv.FormatUint(uint64(req.Video_ID), 10), nil
+	case messageTypeUser:
+		if req.Card_User_ID == 0 {
+			return "", ErrorMessageInvalid
+		}
+		_, err := repo.ReadUserBasics(context.TODO(), req.Card_User_ID)
+		if err != nil || checkBlocked(userID, req.Card_User_ID) {
+			return "", ErrorShareInaccessible
+		}
+		return strconv.FormatUint(uint64(req.Card_User_ID), 10), nil
+	default:
+		return "", ErrorMessageInvalid
+	}
+}
+
+// 批量补全消息负载(图片外链、视频卡片与名片) messageInfos与payloads一一对应 读取失败时对应字段为空
+func fillMessagePayloads(ctx *gin.Context, messageInfos []*response.Message, payloads []string) {
+	var imageIndexes, videoIndexes, userIndexes []int
+	var objectIDs []string
+	var videoIDs, userIDs []uint
+	for i, messageInfo := range messageInfos {
+		switch messageInfo.Message_Type {
+		case messageTypeImage:
+			imageIndexes = append(imageIndexes, i)
+			objectIDs = append(objectIDs, payloads[i])
+		case messageTypeVideo, messageTypeUser:
+			id, err := strconv.ParseUint(payloads[i], 10, 64)
+			if err != nil {
+				continue
+			}
+			if messageInfo.Message_Type == messageTypeVideo {
+				videoIndexes = append(videoIndexes, i)
+				videoIDs = append(videoIDs, uint(id))
+			} else {
+				userIndexes = append(userIndexes, i)
+				userIDs = append(userIDs, uint(id))
+			}
+		}
+	}
+
+	imageURLs := repo.GetMessageImageBatch(context.TODO(), objectIDs)
+	for j, i := range imageIndexes {
+		messageInfos[i].Image_URL = imageURLs[j]
+	}
+	videoInfos := readVideoInfoBatch(ctx, videoIDs)
+	for j, i := range videoIndexes {
+		messageInfos[i].Video = videoInfos[j]
+	}
+	userInfos := readUserInfoBatch(ctx, userIDs)
+	for j, i := range userIndexes {
+		messageInfos[i].User = userInfos[j]
+	}
+}
+
+// 获取消息的预览文本(用于仅展示文本的场景)
+func messagePreview(msgType int, content string) string {
+	switch msgType {
+	case messageTypeImage:
+		return "[图片]" + content
+	case messageTypeVideo:
+		return "[视频]" + content
+	case messageTypeUser:
+		return "[名片]" + content
+	default:
+		return content
+	}
+}
+
+// 结合缓存中的送达与已读进度得出消息状态(数据库中的状态经回写同步 可能滞后)
+func messageStatus(status int, messageID uint, deliveredID uint, readID uint) int {
+	if messageID <= readID {
+		return messageStatusRead
+	}
+	if messageID <= deliveredID && status < messageStatusDelivered {
+		return messageStatusDelivered
+	}
+	return status
+}
+
+// 确认对方发来的消息已送达/已读(至messageID为止) 进度推进时推送回执给聊天双方
+func ackMessages(userID uint, otherID uint, messageID uint, eventType string) (err error) {
+	message, err := repo.ReadMessageBasics(context.TODO(), messageID)
+	if err != nil || message.FromUserID != otherID || message.ToUserID != userID { // 仅可确认对方发给自己的消息
+		return ErrorMessageInaccessible
+	}
+
+	var advanced bool
+	if eventType == chatEventRead {
+		advanced, err = repo.ReadMessages(context.TODO(), otherID, userID, messageID)
+	} else {
+		advanced, err = repo.DeliverMessages(context.TODO(), otherID, userID, messageID)
+	}
+	if err != nil {
+		utility.Logger().Errorf("ackMessages err: %v", err)
+		return err
+	}
+	if advanced {
+		pushChatEvent(&response.ChatEvent{
+			Type:         eventType,
+			From_User_ID: userID,
+			To_User_ID:   otherID,
+			Message_ID:   messageID,
+			Create_Time:  time.Now().UnixMilli(),
+		}, otherID, userID) // 同时推送给请求用户的其他连接以同步状态
+	}
+	return nil
+}
+
 func Message(ctx *gin.Context, req *request.MessageReq) (resp *response.MessageResp, err error) {
 	// 获取请求用户ID
 	req_id, ok := ctx.Get("req_id")
@@ -22,12 +196,62 @@ func Message(ctx *gin.Context, req *request.MessageReq) (resp *response.MessageR
 
 	// 操作消息
 	if req.Action_Type == 1 {
+		if checkBlocked(req_id.(uint), req.To_User_ID) { // 双方之间存在拉黑关系时拒绝发送
+			return nil, ErrorUserBlocked
+		}
+
+		msgType := req.Message_Type
+		if msgType == 0 {
+			msgType = messageTypeText
+		}
+
+		// 过滤消息敏感词
+		content, needReview, err := filterText(req.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		// 校验并准备消息负载
+		payload, err := prepareMessagePayload(ctx, req_id.(uint), req, msgType)
+		if err != nil {
+			return nil, err
+		}
+
 		// 发送消息
-		_, err := repo.CreateMessage(context.TODO(), req_id.(uint), req.To_User_ID, req.Content)
+		message, err := repo.CreateMessage(context.TODO(), req_id.(uint), req.To_User_ID, msgType, content, payload)
 		if err != nil {
 			utility.Logger().Errorf("CreateMessage err: %v", err)
+			if msgType == messageTypeImage { // 移除已上传的图片(失败时仅记录错误)
+				_ = repo.RemoveMessageImage(context.TODO(), payload)
+			}
 			return nil, err
 		}
+		if needReview { // 消息包含送审类敏感词时送审
+			sendToReview(reportTargetMessage, message.ID)
+		}
+
+		// 推送新消息给聊天双方的实时连接 送审中的消息仅推送给发送者本人
+		recipientIDs := []uint{message.ToUserID, message.FromUserID}
+		if needReview {
+			recipientIDs = recipientIDs[1:]
+		}
+		messageInfo := &response.Message{
+			ID:           message.ID,
+			To_User_ID:   message.ToUserID,
+			From_User_ID: message.FromUserID,
+			Content:      message.Content,
+			Create_Time:  message.CreatedAt.Unix() * 1000, // 与消息列表一致 为毫秒时间戳
+			Status:       messageStatusSent,
+			Message_Type: message.Type,
+		}
+		fillMessagePayloads(ctx, []*response.Message{messageInfo}, []string{message.Payload})
+		pushChatEvent(&response.ChatEvent{
+			Type:         chatEventMessage,
+			From_User_ID: message.FromUserID,
+			To_User_ID:   message.ToUserID,
+			Message:      messageInfo,
+			Create_Time:  message.CreatedAt.UnixMilli(),
+		}, recipientIDs...)
 	} else {
 		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
 		return nil, errors.New("操作类型有误")
@@ -44,6 +268,11 @@ func MessageList(ctx *gin.Context, req *request.MessageListReq) (resp *response.
 		return nil, errors.New("无法获取请求用户ID")
 	}
 
+	// 存在拉黑关系时不可查看聊天记录
+	if checkBlocked(req_id.(uint), req.To_User_ID) {
+		return nil, ErrorUserBlocked
+	}
+
 	// 读取消息列表
 	messages, err := repo.FindMessagesByCreatedAt(context.TODO(), req_id.(uint), req.To_User_ID, req.Pre_Msg_Time, true, 30) // 查找从某刻起新消息 最多30条
 	if err != nil {
@@ -51,21 +280,283 @@ func MessageList(ctx *gin.Context, req *request.MessageListReq) (resp *response.
 		return nil, err
 	}
 
+	// 拉取到对方发来的消息即确认送达(仅在进度推进时写回 不会因轮询产生额外的数据库写入)
+	var maxIncomingID uint
+	for _, message := range messages {
+		if message.FromUserID == req.To_User_ID && message.ID > maxIncomingID {
+			maxIncomingID = message.ID
+		}
+	}
+	if maxIncomingID > 0 {
+		advanced, err := repo.DeliverMessages(context.TODO(), req.To_User_ID, req_id.(uint), maxIncomingID)
+		if err != nil {
+			utility.Logger().Errorf("DeliverMessages err: %v", err)
+		} else if advanced {
+			pushChatEvent(&response.ChatEvent{
+				Type:         chatEventDelivered,
+				From_User_ID: req_id.(uint),
+				To_User_ID:   req.To_User_ID,
+				Message_ID:   maxIncomingID,
+				Create_Time:  time.Now().UnixMilli(),
+			}, req.To_User_ID)
+		}
+	}
+
+	// 读取双向的送达与已读进度(下标0为请求用户发出的消息 下标1为对方发来的消息)
+	deliveredIDs, readIDs := repo.ReadMessagesProgressBatch(context.TODO(), []uint{req_id.(uint), req.To_User_ID}, []uint{req.To_User_ID, req_id.(uint)})
+
 	resp = &response.MessageListResp{Message_List: make([]response.Message, 0, len(messages))} // 初始化响应
+	payloads := make([]string, 0, len(messages))
 	for _, message := range messages {
+		direction := 0
+		if message.FromUserID != req_id.(uint) {
+			direction = 1
+		}
+
 		// 初始化消息响应结构
 		messageInfo := response.Message{
 			ID:           message.ID,
 			To_User_ID:   message.ToUserID,
 			From_User_ID: message.FromUserID,
 			Content:      message.Content,
+			Status:       messageStatus(message.Status, message.ID, deliveredIDs[direction], readIDs[direction]),
+			Message_Type: message.Type,
 			Create_Time:  message.CreatedAt.Unix() * 1000, // 消息发送时间 API文档有误 响应实为毫秒时间戳 故在此转换
 			// Create_Time:  message.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 
 		// 将该消息加入列表
 		resp.Message_List = append(resp.Message_List, messageInfo)
+		payloads = append(payloads, message.Payload)
+	}
+
+	// 批量补全消息负载
+	messageInfos := make([]*response.Message, len(resp.Message_List))
+	for i := range resp.Message_List {
+		messageInfos[i] = &resp.Message_List[i]
 	}
+	fillMessagePayloads(ctx, messageInfos, payloads)
 
 	return resp, nil
 }
+
+// 获取会话列表
+func ConversationList(ctx *gin.Context, req *request.ConversationListReq) (resp *response.ConversationListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 分页读取会话列表
+	conversations, hasMore, nextOffset, err := readPage(repo.FindUserConversations, req_id.(uint), req.Offset, req.Count, conversationPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindUserConversations err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.ConversationListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	// 批量读取对方用户信息及最近一条消息
+	otherIDs := make([]uint, 0, len(conversations))
+	unreadCounts := make([]int64, 0, len(conversations))
+	for _, conversation := range conversations {
+		if conversation.User1ID == req_id.(uint) {
+			otherIDs = append(otherIDs, conversation.User2ID)
+			unreadCounts = append(unreadCounts, conversation.User1Unread)
+		} else {
+			otherIDs = append(otherIDs, conversation.User1ID)
+			unreadCounts = append(unreadCounts, conversation.User2Unread)
+		}
+	}
+	isBlocked := checkBlockedBatch(req_id.(uint), otherIDs)
+	userInfos := readUserInfoBatch(ctx, otherIDs)
+	messages := repo.FindLastMessagesBatch(context.TODO(), req_id.(uint), otherIDs)
+
+	// 批量读取最近一条消息所在方向的送达与已读进度
+	fromUserIDs := make([]uint, len(messages))
+	toUserIDs := make([]uint, len(messages))
+	for i, message := range messages {
+		if message != nil {
+			fromUserIDs[i], toUserIDs[i] = message.FromUserID, message.ToUserID
+		}
+	}
+	deliveredIDs, readIDs := repo.ReadMessagesProgressBatch(context.TODO(), fromUserIDs, toUserIDs)
+
+	resp.Conversation_List = make([]response.Conversation, 0, len(conversations))
+	var messageInfos []*response.Message
+	var payloads []string
+	for i, conversation := range conversations {
+		if isBlocked[i] || userInfos[i] == nil {
+			continue // 跳过存在拉黑关系或读取失败的用户
+		}
+
+		// 初始化会话响应结构
+		conversationInfo := response.Conversation{
+			User:             *userInfos[i],
+			Unread_Count:     unreadCounts[i],
+			Last_Active_Time: conversation.LastMessageAt.UnixMilli(),
+		}
+		if message := messages[i]; message != nil {
+			conversationInfo.Last_Message = &response.Message{
+				ID:           message.ID,
+				To_User_ID:   message.ToUserID,
+				From_User_ID: message.FromUserID,
+				Content:      message.Content,
+				Create_Time:  message.CreatedAt.Unix() * 1000, // 与消息列表一致 为毫秒时间戳
+				Status:       messageStatus(message.Status, message.ID, deliveredIDs[i], readIDs[i]),
+				Message_Type: message.Type,
+			}
+			messageInfos = append(messageInfos, conversationInfo.Last_Message)
+			payloads = append(payloads, message.Payload)
+		}
+
+		// 将该会话加入列表
+		resp.Conversation_List = append(resp.Conversation_List, conversationInfo)
+	}
+	fillMessagePayloads(ctx, messageInfos, payloads) // 批量补全最近一条消息的负载
+
+	return resp, nil
+}
+
+// 将会话标记为已读
+func ConversationRead(ctx *gin.Context, req *request.ConversationReadReq) (resp *response.ConversationReadResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 将对方发来的消息全部确认已读 与逐条确认共用进度推进与回执推送
+	lastID, err := repo.FindLastMessageID(context.TODO(), req.To_User_ID, req_id.(uint))
+	if err != nil {
+		utility.Logger().Errorf("FindLastMessageID err: %v", err)
+		return nil, err
+	}
+	if lastID != 0 {
+		err = ackMessages(req_id.(uint), req.To_User_ID, lastID, chatEventRead)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 清零未读消息数(进度未推进时兜底修正)
+	err = repo.ReadConversation(context.TODO(), req_id.(uint), req.To_User_ID)
+	if err != nil {
+		utility.Logger().Errorf("ReadConversation err: %v", err)
+		return nil, err
+	}
+
+	return &response.ConversationReadResp{}, nil
+}
+
+// 获取未读消息总数
+func UnreadCount(ctx *gin.Context, req *request.UnreadCountReq) (resp *response.UnreadCountResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 统计未读消息总数
+	count := repo.CountUserUnread(context.TODO(), req_id.(uint))
+	if count < 0 {
+		utility.Logger().Errorf("CountUserUnread err: 统计失败")
+		return nil, errors.New("统计未读消息数失败")
+	}
+
+	return &response.UnreadCountResp{Unread_Count: count}, nil
+}
+
+// 确认消息已送达/已读
+func MessageAck(ctx *gin.Context, req *request.MessageAckReq) (resp *response.MessageAckResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 确认送达/已读
+	if req.Action_Type == 1 {
+		err = ackMessages(req_id.(uint), req.To_User_ID, req.Message_ID, chatEventDelivered)
+	} else if req.Action_Type == 2 {
+		err = ackMessages(req_id.(uint), req.To_User_ID, req.Message_ID, chatEventRead)
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.MessageAckResp{}, nil
+}
+
+// 撤回或仅为自己删除消息
+func MessageDelete(ctx *gin.Context, req *request.MessageDeleteReq) (resp *response.MessageDeleteResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	message, err := repo.ReadMessageBasics(context.TODO(), req.Message_ID)
+	if err != nil || message.IsRecalled || (message.FromUserID != req_id.(uint) && message.ToUserID != req_id.(uint)) { // 仅聊天双方可操作未撤回的消息
+		return nil, ErrorMessageInaccessible
+	}
+
+	if req.Action_Type == 1 {
+		if message.FromUserID != req_id.(uint) || message.IsHidden { // 仅发送者可撤回未被隐藏的消息
+			return nil, ErrorMessageInaccessible
+		}
+		err = repo.RecallMessage(context.TODO(), req.Message_ID)
+		if err == repo.ErrorRecallWindowExpired {
+			return nil, ErrorMessageRecallExpired
+		}
+		if err == repo.ErrorRecordNotExists { // 并发撤回
+			return nil, ErrorMessageInaccessible
+		}
+		if err != nil {
+			utility.Logger().Errorf("RecallMessage err: %v", err)
+			return nil, err
+		}
+
+		// 推送撤回事件给聊天双方的实时连接
+		pushChatEvent(&response.ChatEvent{
+			Type:         chatEventRecall,
+			From_User_ID: message.FromUserID,
+			To_User_ID:   message.ToUserID,
+			Message_ID:   message.ID,
+			Create_Time:  time.Now().UnixMilli(),
+		}, message.ToUserID, message.FromUserID)
+	} else if req.Action_Type == 2 {
+		err = repo.DeleteMessageForUser(context.TODO(), req_id.(uint), req.Message_ID)
+		if err != nil {
+			utility.Logger().Errorf("DeleteMessageForUser err: %v", err)
+			return nil, err
+		}
+
+		// 推送删除事件给请求用户的其他连接以同步状态
+		otherID := message.ToUserID
+		if otherID == req_id.(uint) {
+			otherID = message.FromUserID
+		}
+		pushChatEvent(&response.ChatEvent{
+			Type:         chatEventDelete,
+			From_User_ID: req_id.(uint),
+			To_User_ID:   otherID,
+			Message_ID:   message.ID,
+			Create_Time:  time.Now().UnixMilli(),
+		}, req_id.(uint))
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.MessageDeleteResp{}, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"douyin/service/type/request"
+
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPrepareMessagePayloadInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// 以下情形均在访问存储层之前返回
+	tests := []struct {
+		name    string
+		req     *request.MessageReq
+		msgType int
+	}{
+		{"空文本", &request.MessageReq{}, messageTypeText},
+		{"缺少图片", &request.MessageReq{Content: "附言"}, messageTypeImage},
+		{"缺少视频ID", &request.MessageReq{Content: "附言"}, messageTypeVideo},
+		{"缺少名片用户ID", &request.MessageReq{Content: "附言"}, messageTypeUser},
+		{"未知类型", &request.MessageReq{Content: "你好"}, 5},
+	}
+	for _, tt := range tests {
+		payload, err := prepareMessagePayload(ctx, 1, tt.req, tt.msgType)
+		if err != ErrorMessageInvalid || payload != "" {
+			t.Errorf("%s: prepareMessagePayload = %q, %v, want ErrorMessageInvalid", tt.name, payload, err)
+		}
+	}
+}
+
+func TestPrepareMessagePayloadText(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	payload, err := prepareMessagePayload(ctx, 1, &request.MessageReq{Content: "你好"}, messageTypeText)
+	if err != nil || payload != "" {
+		t.Errorf("prepareMessagePayload(text) = %q, %v, want empty payload", payload, err)
+	}
+}
+
+func TestMessageStatus(t *testing.T) {
+	tests := []struct {
+		status, deliveredID, readID int
+		messageID                   uint
+		want                        int
+	}{
+		{messageStatusSent, 0, 0, 5, messageStatusSent},           // 无进度时以数据库状态为准
+		{messageStatusSent, 5, 0, 5, messageStatusDelivered},      // 已送达至该消息
+		{messageStatusSent, 9, 5, 5, messageStatusRead},           // 已读至该消息
+		{messageStatusSent, 4, 4, 5, messageStatusSent},           // 进度早于该消息
+		{messageStatusRead, 0, 0, 5, messageStatusRead},           // 数据库状态已回写
+		{messageStatusDelivered, 9, 0, 5, messageStatusDelivered}, // 不回退
+	}
+	for _, tt := range tests {
+		if got := messageStatus(tt.status, tt.messageID, uint(tt.deliveredID), uint(tt.readID)); got != tt.want {
+			t.Errorf("messageStatus(%d, %d, %d, %d) = %d, want %d", tt.status, tt.messageID, tt.deliveredID, tt.readID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const notificationKindVideoMention = 1   // 在视频标题中被提及
+const notificationKindCommentMention = 2 // 在评论中被提及
+
+const notificationPageSize = 30 // 通知列表单页默认返回的通知数量
+
+// 分页获取通知列表
+func NotificationList(ctx *gin.Context, req *request.NotificationListReq) (resp *response.NotificationListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 分页读取通知列表
+	notifications, hasMore, nextOffset, err := readPage(repo.FindUserNotifications, req_id.(uint), req.Offset, req.Count, notificationPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindUserNotifications err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.NotificationListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+	resp.Unread_Count = repo.CountUserUnreadNotifications(context.TODO(), req_id.(uint))
+
+	// 按类型收集通知对象
+	actorIDs := make([]uint, 0, len(notifications))
+	var videoIDs, commentIDs []uint
+	for _, notification := range notifications {
+		actorIDs = append(actorIDs, notification.ActorID)
+		switch notification.Kind {
+		case notificationKindVideoMention:
+			videoIDs = append(videoIDs, notification.TargetID)
+		case notificationKindCommentMention:
+			commentIDs = append(commentIDs, notification.TargetID)
+		}
+	}
+
+	// 批量读取通知对象信息
+	actorInfos := readUserInfoBatch(ctx, actorIDs)
+	videoInfos := make(map[uint]*response.Video, len(videoIDs))
+	for k, videoInfo := range readVideoInfoBatch(ctx, videoIDs) {
+		videoInfos[videoIDs[k]] = videoInfo
+	}
+	commentInfos := make(map[uint]*response.Comment, len(commentIDs))
+	for k, commentInfo := range readCommentInfoBatch(ctx, commentIDs) {
+		commentInfos[commentIDs[k]] = commentInfo
+	}
+	commentVideoIDs := make(map[uint]uint, len(commentIDs)) // 评论所属视频ID
+	comments, err := repo.ReadCommentBasicsBatch(context.TODO(), commentIDs)
+	if err == nil {
+		for k, comment := range comments {
+			if comment != nil {
+				commentVideoIDs[commentIDs[k]] = comment.VideoID
+			}
+		}
+	}
+
+	resp.Notification_List = make([]response.Notification, 0, len(notifications))
+	for i, notification := range notifications {
+		if actorInfos[i] == nil {
+			continue // 跳过读取失败的通知
+		}
+		notificationInfo := response.Notification{
+			ID:          notification.ID,
+			Type:        notification.Kind,
+			Actor:       *actorInfos[i],
+			Is_Read:     notification.IsRead,
+			Create_Time: notification.CreatedAt.UnixMilli(),
+		}
+		switch notification.Kind {
+		case notificationKindVideoMention:
+			notificationInfo.Video = videoInfos[notification.TargetID]
+			notificationInfo.Video_ID = notification.TargetID
+			if notificationInfo.Video == nil {
+				continue // 跳过已删除或被隐藏的视频
+			}
+		case notificationKindCommentMention:
+			notificationInfo.Comment = commentInfos[notification.TargetID]
+			notificationInfo.Video_ID = commentVideoIDs[notification.TargetID]
+			if notificationInfo.Comment == nil {
+				continue // 跳过已删除或被隐藏的评论
+			}
+		}
+
+		// 将该通知加入列表
+		resp.Notification_List = append(resp.Notification_List, notificationInfo)
+	}
+
+	return resp, nil
+}
+
+// 标记通知为已读
+func NotificationRead(ctx *gin.Context, req *request.NotificationReadReq) (resp *response.NotificationReadResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 存储已读状态
+	err = repo.UpdateNotificationsRead(context.TODO(), req_id.(uint), req.Notification_ID)
+	if err != nil {
+		utility.Logger().Errorf("UpdateNotificationsRead err: %v", err)
+		return nil, err
+	}
+
+	return &response.NotificationReadResp{}, nil
+}
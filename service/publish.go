@@ -8,6 +8,7 @@ import (
 
 	"context"
 	"errors"
+	"mime/multipart"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -25,23 +26,106 @@ func Publish(ctx *gin.Context, req *request.PublishReq) (resp *response.PublishR
 		return nil, errors.New("无法获取请求用户ID")
 	}
 
+	// 创建视频
+	_, err = createVideo(req_id.(uint), req.Data, req.Title, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.PublishResp{}, nil
+}
+
+// 获取发布列表
+func PublishList(ctx *gin.Context, req *request.PublishListReq) (resp *response.PublishListResp, err error) {
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取目标用户信息
+	works, err := repo.ReadUserWorks(context.TODO(), req.User_ID)
+	if err != nil {
+		utility.Logger().Errorf("ReadUserWorks err: %v", err)
+		return nil, err
+	}
+
+	// 读取目标用户发布列表
+	resp = &response.PublishListResp{Video_List: make([]response.Video, 0, len(works))} // 初始化响应
+	videoIDs := make([]uint, 0, len(works))
+	for _, video := range works {
+		videoIDs = append(videoIDs, video.ID)
+	}
+	videoInfos := readVideoInfoBatch(ctx, videoIDs) // 批量读取视频信息
+	for _, videoInfo := range videoInfos {
+		if videoInfo == nil {
+			continue // 跳过读取失败的视频
+		}
+
+		// 将该视频加入列表
+		resp.Video_List = append(resp.Video_List, *videoInfo)
+	}
+
+	return resp, nil
+}
+
+// 检查视频是否为用户已发布的作品(含被隐藏的作品)
+func checkUserWorks(userID uint, videoID uint) (isIts bool) {
+	video, err := repo.ReadVideoBasics(context.TODO(), videoID)
+	return err == nil && !video.IsDraft && video.AuthorID == userID
+}
+
+// 删除已发布的视频(移入回收站)
+func PublishDelete(ctx *gin.Context, req *request.PublishDeleteReq) (resp *response.PublishDeleteResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	if !checkUserWorks(req_id.(uint), req.Video_ID) { // 若非请求用户的作品则拒绝删除
+		return nil, ErrorVideoInaccessible
+	}
+
+	err = repo.TrashVideo(context.TODO(), req.Video_ID) // 移入回收站 OSS对象待过期清除时一并移除
+	if err != nil {
+		utility.Logger().Errorf("TrashVideo err: %v", err)
+		return nil, err
+	}
+
+	return &response.PublishDeleteResp{}, nil
+}
+
+// 存储视频信息并上传视频数据(isDraft为true时创建草稿)
+func createVideo(authorID uint, data *multipart.FileHeader, title string, isDraft bool) (videoID uint, err error) {
+	// 过滤标题敏感词
+	title, needReview, err := filterText(title)
+	if err != nil {
+		return 0, err
+	}
+
 	// 先尝试打开文件 若无法打开则不创建数据库条目
-	videoStream, err := req.Data.Open()
+	videoStream, err := data.Open()
 	if err != nil {
 		utility.Logger().Errorf("file.Open err: %v", err)
-		return nil, err
+		return 0, err
 	}
 	defer videoStream.Close() // 不保证自动关闭成功
 
 	// 存储视频信息
-	video, err := repo.CreateVideo(context.TODO(), req_id.(uint), req.Title)
+	create := repo.CreateVideo
+	if isDraft {
+		create = repo.CreateDraft
+	}
+	video, err := create(context.TODO(), authorID, title)
 	if err != nil {
 		utility.Logger().Errorf("CreateVideo err: %v", err)
-		return nil, err
+		return 0, err
 	}
 
 	// 上传视频数据(封面为默认)
-	err = repo.UploadVideoStream(context.TODO(), strconv.FormatUint(uint64(video.ID), 10), videoStream, req.Data.Size)
+	err = repo.UploadVideoStream(context.TODO(), strconv.FormatUint(uint64(video.ID), 10), videoStream, data.Size)
 	if err != nil {
 		utility.Logger().Errorf("UploadVideoStream err: %v", err)
 
@@ -49,45 +133,29 @@ func Publish(ctx *gin.Context, req *request.PublishReq) (resp *response.PublishR
 		utility.Logger().Warnf("CreateVideo warn: 正在回滚(移除对应数据库条目%v)", video.ID)
 		err2 := repo.DeleteVideo(context.TODO(), video.ID, true) // 永久删除
 		if err2 != nil {
-			return nil, ErrorRollbackFailed
+			return 0, ErrorRollbackFailed
 		} else {
-			return nil, err
+			return 0, err
 		}
 	}
 
+	// 标题包含送审类敏感词时送审
+	if needReview {
+		sendToReview(reportTargetVideo, video.ID)
+	}
+
+	// 解析标题中的提及 草稿将在发布时解析
+	if !isDraft {
+		createMentions(authorID, mentionTargetVideo, video.ID, title, false)
+	}
+
 	// 创建更新封面异步任务
 	go func() {
-		err2 := repo.UpdateCover(context.TODO(), strconv.FormatUint(uint64(video.ID), 10)) // 不保证自动更新成功
+		err2 := repo.UpdateCover(context.TODO(), video.ID) // 不保证自动更新成功 已有自定义封面时跳过
 		if err2 != nil {
 			utility.Logger().Errorf("UpdateCover err: %v", err2)
 		}
 	}()
 
-	return &response.PublishResp{}, nil
-}
-
-// 获取发布列表
-func PublishList(ctx *gin.Context, req *request.PublishListReq) (resp *response.PublishListResp, err error) {
-	// 读取目标用户信息
-	works, err := repo.ReadUserWorks(context.TODO(), req.User_ID)
-	if err != nil {
-		utility.Logger().Errorf("ReadUserWorks err: %v", err)
-		return nil, err
-	}
-
-	// 读取目标用户发布列表
-	resp = &response.PublishListResp{Video_List: make([]response.Video, 0, len(works))} // 初始化响应
-	for _, video := range works {
-		// 读取视频信息
-		videoInfo, err := readVideoInfo(ctx, video.ID)
-		if err != nil {
-			utility.Logger().Errorf("readVideoInfo err: %v", err)
-			continue // 跳过本条视频
-		}
-
-		// 将该视频加入列表
-		resp.Video_List = append(resp.Video_List, *videoInfo)
-	}
-
-	return resp, nil
+	return video.ID, nil
 }
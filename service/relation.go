@@ -8,11 +8,17 @@ import (
 
 	"context"
 	"errors"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+const followRequestPageSize = 30 // 关注申请列表单页默认返回的申请数量
+const recommendListSize = 30     // 好友推荐列表默认返回的用户数量
+
+// 自定义错误类型
+var ErrorFollowRequestInaccessible = errors.New("关注申请不存在或无权处理")
+var ErrorNotFollower = errors.New("对方未关注你")
+
 // 关注/取消关注
 func Follow(ctx *gin.Context, req *request.FollowReq) (resp *response.FollowResp, err error) {
 	// 获取请求用户ID
@@ -25,13 +31,32 @@ func Follow(ctx *gin.Context, req *request.FollowReq) (resp *response.FollowResp
 	// 关注/取消关注
 	if req.Action_Type == 1 {
 		// 关注
+		if checkBlocked(req_id.(uint), req.To_User_ID) { // 双方之间存在拉黑关系时拒绝关注
+			return nil, ErrorUserBlocked
+		}
+		user, err := repo.ReadUserBasics(context.TODO(), req.To_User_ID)
+		if err != nil {
+			utility.Logger().Errorf("ReadUserBasics err: %v", err)
+			return nil, err
+		}
+		if user.IsPrivate && !repo.CheckUserFollows(context.TODO(), req_id.(uint), req.To_User_ID) { // 关注私密账号时改为发送关注申请
+			_, err = repo.CreateFollowRequest(context.TODO(), req_id.(uint), req.To_User_ID)
+			if err != nil {
+				utility.Logger().Errorf("CreateFollowRequest err: %v", err)
+				return nil, err
+			}
+			return &response.FollowResp{Is_Pending: true}, nil
+		}
 		err = repo.CreateUserFollows(context.TODO(), req_id.(uint), req.To_User_ID)
 		if err != nil {
 			utility.Logger().Errorf("CreateUserFollows err: %v", err)
 			return nil, err
 		}
 	} else if req.Action_Type == 2 {
-		// 取消关注
+		// 取消关注 尚未关注时撤回关注申请(如有)
+		if !repo.CheckUserFollows(context.TODO(), req_id.(uint), req.To_User_ID) && repo.DeleteFollowRequest(context.TODO(), req_id.(uint), req.To_User_ID) == nil {
+			return &response.FollowResp{}, nil
+		}
 		err = repo.DeleteUserFollows(context.TODO(), req_id.(uint), req.To_User_ID)
 		if err != nil {
 			utility.Logger().Errorf("DeleteUserFollows err: %v", err)
@@ -45,8 +70,41 @@ func Follow(ctx *gin.Context, req *request.FollowReq) (resp *response.FollowResp
 	return &response.FollowResp{}, nil
 }
 
+// 移除粉丝(删除对方对请求用户的关注关系)
+func RemoveFollower(ctx *gin.Context, req *request.RemoveFollowerReq) (resp *response.RemoveFollowerResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 检查对方是否关注了请求用户
+	if !repo.CheckUserFollows(context.TODO(), req.User_ID, req_id.(uint)) {
+		return nil, ErrorNotFollower
+	}
+
+	// 以对方身份取消关注 与取消关注共用同一写回路径以保持计数与缓存一致
+	err = repo.DeleteUserFollows(context.TODO(), req.User_ID, req_id.(uint))
+	if err == repo.ErrorRecordNotExists { // 重复请求
+		return nil, ErrorNotFollower
+	}
+	if err != nil {
+		utility.Logger().Errorf("DeleteUserFollows err: %v", err)
+		return nil, err
+	}
+
+	return &response.RemoveFollowerResp{}, nil
+}
+
 // 获取关注列表
 func FollowList(ctx *gin.Context, req *request.FollowListReq) (resp *response.FollowListResp, err error) {
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 读取目标用户信息
 	follows, err := repo.ReadUserFollows(context.TODO(), req.User_ID)
 	if err != nil {
@@ -56,12 +114,15 @@ func FollowList(ctx *gin.Context, req *request.FollowListReq) (resp *response.Fo
 
 	// 读取目标用户关注列表
 	resp = &response.FollowListResp{User_List: make([]response.User, 0, len(follows))} // 初始化响应
+	followIDs := make([]uint, 0, len(follows))
 	for _, follow := range follows {
-		// 读取被关注用户信息
-		followInfo, err := readUserInfo(ctx, follow.ID)
-		if err != nil {
-			utility.Logger().Errorf("readUserInfo err: %v", err)
-			continue // 跳过该用户
+		followIDs = append(followIDs, follow.ID)
+	}
+	followIDs = filterBlocked(ctx, followIDs)        // 过滤与请求用户之间存在拉黑关系的用户
+	followInfos := readUserInfoBatch(ctx, followIDs) // 批量读取被关注用户信息
+	for _, followInfo := range followInfos {
+		if followInfo == nil {
+			continue // 跳过读取失败的用户
 		}
 
 		// 将该用户加入列表
@@ -73,6 +134,12 @@ func FollowList(ctx *gin.Context, req *request.FollowListReq) (resp *response.Fo
 
 // 获取粉丝列表
 func FollowerList(ctx *gin.Context, req *request.FollowerListReq) (resp *response.FollowerListResp, err error) {
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 读取目标用户信息
 	followers, err := repo.ReadUserFollowers(context.TODO(), req.User_ID)
 	if err != nil {
@@ -82,12 +149,15 @@ func FollowerList(ctx *gin.Context, req *request.FollowerListReq) (resp *respons
 
 	// 读取目标用户粉丝列表
 	resp = &response.FollowerListResp{User_List: make([]response.User, 0, len(followers))} // 初始化响应
+	followerIDs := make([]uint, 0, len(followers))
 	for _, follower := range followers {
-		// 读取粉丝用户信息
-		followerInfo, err := readUserInfo(ctx, follower.ID)
-		if err != nil {
-			utility.Logger().Errorf("readUserInfo err: %v", err)
-			continue // 跳过该用户
+		followerIDs = append(followerIDs, follower.ID)
+	}
+	followerIDs = filterBlocked(ctx, followerIDs)        // 过滤与请求用户之间存在拉黑关系的用户
+	followerInfos := readUserInfoBatch(ctx, followerIDs) // 批量读取粉丝用户信息
+	for _, followerInfo := range followerInfos {
+		if followerInfo == nil {
+			continue // 跳过读取失败的用户
 		}
 
 		// 将该用户加入列表
@@ -99,56 +169,154 @@ func FollowerList(ctx *gin.Context, req *request.FollowerListReq) (resp *respons
 
 // 获取好友列表
 func FriendList(ctx *gin.Context, req *request.FriendListReq) (resp *response.FriendListResp, err error) {
-	// 读取目标用户信息
-	follows, err := repo.ReadUserFollows(context.TODO(), req.User_ID)
+	// 读取目标用户朋友(互粉)列表
+	friendIDs, err := repo.ReadUserFriends(context.TODO(), req.User_ID)
 	if err != nil {
-		utility.Logger().Errorf("ReadUserFollows err: %v", err)
+		utility.Logger().Errorf("ReadUserFriends err: %v", err)
 		return nil, err
 	}
+	friendIDs = filterBlocked(ctx, friendIDs) // 过滤与请求用户之间存在拉黑关系的用户
 
-	// 读取目标用户关注列表(用于读取朋友)
-	resp = &response.FriendListResp{} // 初始化响应 由于朋友(互粉)数未知且一般较小, 不预先分配空间
-	for _, friend := range follows {
-		// 检查该用户是否也关注了目标用户
-		if repo.CheckUserFollows(context.TODO(), friend.ID, req.User_ID) {
-			// 若互粉则为朋友
-			// 读取朋友用户信息
-			friendInfo, err := readUserInfo(ctx, friend.ID)
-			if err != nil {
-				utility.Logger().Errorf("readUserInfo err: %v", err)
-				continue // 跳过该用户
-			}
+	resp = &response.FriendListResp{User_List: make([]response.FriendUser, 0, len(friendIDs))} // 初始化响应
+	friendInfos := readUserInfoBatch(ctx, friendIDs)                                           // 批量读取朋友用户信息
+	messages := repo.FindLastMessagesBatch(context.TODO(), req.User_ID, friendIDs)             // 批量查找最近一条消息
+	for i, friendInfo := range friendInfos {
+		if friendInfo == nil {
+			continue // 跳过读取失败的用户
+		}
 
-			// 初始化朋友用户增补响应结构
-			friendUser := response.FriendUser{User: *friendInfo}
+		// 初始化朋友用户增补响应结构
+		friendUser := response.FriendUser{User: *friendInfo}
 
-			// 查找最近一条消息
-			message, err := repo.FindMessagesByCreatedAt(context.TODO(), req.User_ID, friend.ID, time.Now().Unix(), false, 1)
-			if err != nil {
-				utility.Logger().Errorf("FindMessagesByCreatedAt err: %v", err)
-				// 响应为获取成功 但最近消息将为空
-				// friendUser.Message = "" // 和该好友的最新聊天消息 根据API文档默认为不发送
-				friendUser.Msg_Type = 2 // 无消息往来时根据API文档强制要求将msgType赋值
-			} else if len(message) == 0 {
-				// 最近消息为空
-				// friendUser.Message = "" // 和该好友的最新聊天消息 根据API文档默认为不发送
-				friendUser.Msg_Type = 2 // 无消息往来时根据API文档强制要求将msgType赋值
-			} else if message[0].FromUserID == req.User_ID { // 为目标用户发送的消息
-				friendUser.Message = message[0].Content
-				friendUser.Msg_Type = 1
-			} else if message[0].ToUserID == req.User_ID { // 为目标用户接收的消息
-				friendUser.Message = message[0].Content
-				friendUser.Msg_Type = 0
-			} else {
-				utility.Logger().Errorf("FindMessagesByCreatedAt err: 查找结果错误")
-				// 响应为获取成功 但最近消息将为空
-				// friendUser.Message = "" // 和该好友的最新聊天消息 根据API文档默认为不发送
-				friendUser.Msg_Type = 2 // 无消息往来时根据API文档强制要求将msgType赋值
-			}
+		message := messages[i]
+		if message == nil {
+			// 无消息往来或读取失败
+			// friendUser.Message = "" // 和该好友的最新聊天消息 根据API文档默认为不发送
+			friendUser.Msg_Type = 2 // 无消息往来时根据API文档强制要求将msgType赋值
+		} else if message.FromUserID == req.User_ID { // 为目标用户发送的消息
+			friendUser.Message = messagePreview(message.Type, message.Content)
+			friendUser.Msg_Type = 1
+		} else { // 为目标用户接收的消息
+			friendUser.Message = messagePreview(message.Type, message.Content)
+			friendUser.Msg_Type = 0
+		}
+
+		// 将该朋友用户加入列表
+		resp.User_List = append(resp.User_List, friendUser)
+	}
+
+	return resp, nil
+}
+
+// 分页获取待处理的关注申请列表
+func FollowRequestList(ctx *gin.Context, req *request.FollowRequestListReq) (resp *response.FollowRequestListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 分页读取关注申请
+	requests, hasMore, nextOffset, err := readPage(repo.FindFollowRequests, req_id.(uint), req.Offset, req.Count, followRequestPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindFollowRequests err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.FollowRequestListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	// 批量读取申请者信息
+	userIDs := make([]uint, 0, len(requests))
+	for _, request := range requests {
+		userIDs = append(userIDs, request.UserID)
+	}
+	userInfos := readUserInfoBatch(ctx, userIDs)
 
-			// 将该朋友用户加入列表
-			resp.User_List = append(resp.User_List, friendUser)
+	resp.Request_List = make([]response.FollowRequest, 0, len(requests))
+	for i, request := range requests {
+		if userInfos[i] == nil {
+			continue // 跳过读取失败的用户
 		}
+		resp.Request_List = append(resp.Request_List, response.FollowRequest{
+			ID:          request.ID,
+			User:        *userInfos[i],
+			Create_Time: request.CreatedAt.UnixMilli(),
+		})
+	}
+
+	return resp, nil
+}
+
+// 同意/拒绝关注申请
+func FollowRequestAction(ctx *gin.Context, req *request.FollowRequestActionReq) (resp *response.FollowRequestActionResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 同意/拒绝 仅可处理发给请求用户的申请
+	if req.Action_Type == 1 {
+		_, err = repo.AcceptFollowRequest(context.TODO(), req.Request_ID, req_id.(uint))
+	} else if req.Action_Type == 2 {
+		err = repo.DeclineFollowRequest(context.TODO(), req.Request_ID, req_id.(uint))
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+	if err == repo.ErrorRecordNotExists {
+		return nil, ErrorFollowRequestInaccessible
+	}
+	if err != nil {
+		utility.Logger().Errorf("FollowRequestAction err: %v", err)
+		return nil, err
+	}
+
+	return &response.FollowRequestActionResp{}, nil
+}
+
+// 获取好友推荐列表
+func RecommendList(ctx *gin.Context, req *request.RecommendListReq) (resp *response.RecommendListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	count := req.Count
+	if count == 0 {
+		count = recommendListSize
+	}
+
+	// 读取预计算的推荐结果
+	recommendIDs, err := repo.FindUserRecommends(context.TODO(), req_id.(uint), count)
+	if err != nil {
+		utility.Logger().Errorf("FindUserRecommends err: %v", err)
+		return nil, err
+	}
+
+	// 排除预计算后新关注或新拉黑的用户
+	isFollowing := repo.CheckUserFollowsBatch(context.TODO(), req_id.(uint), recommendIDs)
+	isBlocked := checkBlockedBatch(req_id.(uint), recommendIDs)
+	filteredIDs := make([]uint, 0, len(recommendIDs))
+	for i, recommendID := range recommendIDs {
+		if !isFollowing[i] && !isBlocked[i] {
+			filteredIDs = append(filteredIDs, recommendID)
+		}
+	}
+
+	resp = &response.RecommendListResp{User_List: make([]response.User, 0, len(filteredIDs))} // 初始化响应
+	userInfos := readUserInfoBatch(ctx, filteredIDs)                                          // 批量读取推荐用户信息
+	for _, userInfo := range userInfos {
+		if userInfo == nil {
+			continue // 跳过读取失败的用户
+		}
+
+		// 将该用户加入列表
+		resp.User_List = append(resp.User_List, *userInfo)
 	}
 
 	return resp, nil
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const reportTargetVideo = 1     // 举报视频
+const reportTargetComment = 2   // 举报评论
+const reportTargetMessage = 3   // 举报消息
+const reportTargetSignature = 4 // 举报用户签名
+
+const reportStateOpen = 1      // 待处理
+const reportStateActioned = 2  // 已处理(内容已隐藏)
+const reportStateDismissed = 3 // 已驳回(恢复被自动隐藏或送审隐藏的内容)
+
+const moderationPageSize = 30 // 举报列表单页默认返回的举报数量
+
+// 自定义错误类型
+var ErrorReportTargetInaccessible = errors.New("举报对象不存在或无权访问")
+
+// 读取举报对象内容 视频为标题 评论和消息为正文 用户为签名 (userID不为0时检查其能否访问该对象)
+func readReportTarget(targetType int, targetID uint, userID uint) (content string, err error) {
+	switch targetType {
+	case reportTargetVideo:
+		video, err := repo.ReadVideoBasics(context.TODO(), targetID)
+		if err != nil || (userID != 0 && (video.IsDraft || video.IsHidden)) {
+			return "", ErrorReportTargetInaccessible
+		}
+		return video.Title, nil
+	case reportTargetComment:
+		comment, err := repo.ReadCommentBasics(context.TODO(), targetID)
+		if err != nil || (userID != 0 && comment.IsHidden) {
+			return "", ErrorReportTargetInaccessible
+		}
+		return comment.Content, nil
+	case reportTargetMessage:
+		message, err := repo.ReadMessageBasics(context.TODO(), targetID)
+		if err != nil || (userID != 0 && (message.IsHidden || (message.FromUserID != userID && message.ToUserID != userID))) { // 仅聊天双方可举报消息
+			return "", ErrorReportTargetInaccessible
+		}
+		return message.Content, nil
+	case reportTargetSignature:
+		user, err := repo.ReadUserBasics(context.TODO(), targetID)
+		if err != nil || (userID != 0 && user.SignatureHidden) {
+			return "", ErrorReportTargetInaccessible
+		}
+		return user.Signature, nil
+	default:
+		return "", ErrorReportTargetInaccessible
+	}
+}
+
+// 按类型批量读取举报对象内容(审核员可查看已隐藏的内容) 返回值与targetTypes及targetIDs一一对应 不存在或读取失败时对应空字符串
+func readReportTargetsBatch(targetTypes []int, targetIDs []uint) (contents []string) {
+	contents = make([]string, len(targetIDs))
+	indexes := make(map[int][]int, 4) // 各类型对象在结果中的下标
+	ids := make(map[int][]uint, 4)    // 各类型对象的ID
+	for i, targetType := range targetTypes {
+		indexes[targetType] = append(indexes[targetType], i)
+		ids[targetType] = append(ids[targetType], targetIDs[i])
+	}
+
+	if len(ids[reportTargetVideo]) > 0 {
+		videos, err := repo.ReadVideoBasicsBatch(context.TODO(), ids[reportTargetVideo])
+		if err == nil {
+			for j, i := range indexes[reportTargetVideo] {
+				if videos[j] != nil {
+					contents[i] = videos[j].Title
+				}
+			}
+		}
+	}
+	if len(ids[reportTargetComment]) > 0 {
+		comments, err := repo.ReadCommentBasicsBatch(context.TODO(), ids[reportTargetComment])
+		if err == nil {
+			for j, i := range indexes[reportTargetComment] {
+				if comments[j] != nil {
+					contents[i] = comments[j].Content
+				}
+			}
+		}
+	}
+	if len(ids[reportTargetMessage]) > 0 {
+		messages, err := repo.ReadMessageBasicsBatch(context.TODO(), ids[reportTargetMessage])
+		if err == nil {
+			for j, i := range indexes[reportTargetMessage] {
+				if messages[j] != nil {
+					contents[i] = messages[j].Content
+				}
+			}
+		}
+	}
+	if len(ids[reportTargetSignature]) > 0 {
+		users, err := repo.ReadUserBasicsBatch(context.TODO(), ids[reportTargetSignature])
+		if err == nil {
+			for j, i := range indexes[reportTargetSignature] {
+				if users[j] != nil {
+					contents[i] = users[j].Signature
+				}
+			}
+		}
+	}
+	return contents
+}
+
+// 举报内容
+func Report(ctx *gin.Context, req *request.ReportReq) (resp *response.ReportResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 检查举报对象是否可访问
+	_, err = readReportTarget(req.Target_Type, req.Target_ID, req_id.(uint))
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储举报信息
+	report, err := repo.CreateReport(context.TODO(), req_id.(uint), req.Target_Type, req.Target_ID, req.Reason, req.Detail)
+	if err != nil {
+		utility.Logger().Errorf("CreateReport err: %v", err)
+		return nil, err
+	}
+
+	return &response.ReportResp{Report_ID: report.ID}, nil
+}
+
+// 获取举报列表(审核员)
+func ModerationList(ctx *gin.Context, req *request.ModerationListReq) (resp *response.ModerationListResp, err error) {
+	state := req.State
+	if state == 0 {
+		state = reportStateOpen // 默认为待处理
+	}
+
+	// 分页读取举报列表
+	reports, hasMore, nextOffset, err := readPage(repo.FindReportsByState, state, req.Offset, req.Count, moderationPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindReportsByState err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.ModerationListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	// 批量读取举报对象内容
+	targetTypes := make([]int, 0, len(reports))
+	targetIDs := make([]uint, 0, len(reports))
+	for _, report := range reports {
+		targetTypes = append(targetTypes, report.TargetType)
+		targetIDs = append(targetIDs, report.TargetID)
+	}
+	contents := readReportTargetsBatch(targetTypes, targetIDs)
+
+	resp.Report_List = make([]response.Report, 0, len(reports))
+	for i, report := range reports {
+		content := contents[i]
+		if content == "" {
+			utility.Logger().Warnf("readReportTargetsBatch warn: 举报%v的对象不存在、读取失败或内容为空", report.ID)
+		}
+
+		// 将该举报加入列表
+		resp.Report_List = append(resp.Report_List, response.Report{
+			ID:             report.ID,
+			Reporter_ID:    report.ReporterID,
+			Target_Type:    report.TargetType,
+			Target_ID:      report.TargetID,
+			Target_Content: content,
+			Reason:         report.Reason,
+			Detail:         report.Detail,
+			State:          report.State,
+			Create_Time:    report.CreatedAt.UnixMilli(),
+		})
+	}
+
+	return resp, nil
+}
+
+// 处理/驳回举报(审核员)
+func Moderation(ctx *gin.Context, req *request.ModerationReq) (resp *response.ModerationResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 处理/驳回举报
+	var state int
+	if req.Action_Type == 1 {
+		state = reportStateActioned // 处理 隐藏内容
+	} else if req.Action_Type == 2 {
+		state = reportStateDismissed // 驳回 恢复被自动隐藏或送审隐藏的内容
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+	_, err = repo.HandleReports(context.TODO(), req.Report_ID, req_id.(uint), state)
+	if err != nil {
+		utility.Logger().Errorf("HandleReports err: %v", err)
+		return nil, err
+	}
+
+	return &response.ModerationResp{}, nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 动态条目 用于合并作品与转发 repostID为0时表示原创作品
+type postEntry struct {
+	videoID   uint
+	createdAt time.Time
+	repostID  uint
+	userID    uint
+	content   string
+}
+
+// 批量读取动态信息 跳过读取失败的条目
+func readPostInfoBatch(ctx *gin.Context, entries []postEntry) (postInfos []response.Post) {
+	videoIDs := make([]uint, 0, len(entries))
+	userIDs := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		videoIDs = append(videoIDs, entry.videoID)
+		if entry.repostID != 0 {
+			userIDs = append(userIDs, entry.userID)
+		}
+	}
+	videoInfos := readVideoInfoBatch(ctx, videoIDs) // 批量读取视频信息
+	userInfos := readUserInfoBatch(ctx, userIDs)    // 批量读取转发用户信息
+
+	postInfos = make([]response.Post, 0, len(entries))
+	j := 0 // 转发用户下标
+	for i, entry := range entries {
+		var userInfo *response.User
+		if entry.repostID != 0 {
+			userInfo = userInfos[j]
+			j++
+		}
+		if videoInfos[i] == nil {
+			continue // 跳过读取失败(如已删除)的视频
+		}
+
+		// 将该动态加入列表
+		postInfo := response.Post{Video: *videoInfos[i]}
+		if entry.repostID != 0 {
+			if userInfo == nil {
+				continue // 跳过读取失败的转发用户
+			}
+			postInfo.Repost = &response.Repost{
+				ID:          entry.repostID,
+				User:        *userInfo,
+				Content:     entry.content,
+				Create_Time: entry.createdAt.UnixMilli(),
+			}
+		}
+		postInfos = append(postInfos, postInfo)
+	}
+	return postInfos
+}
+
+// 按视频ID去重动态条目(保留最新的一条) 并过滤已看视频 全部已看时回退为仅去重
+func dedupPostEntries(userID uint, entries []postEntry) (results []postEntry) {
+	deduped := make([]postEntry, 0, len(entries))
+	videoIDs := make([]uint, 0, len(entries))
+	added := make(map[uint]bool, len(entries))
+	for _, entry := range entries {
+		if added[entry.videoID] {
+			continue // 同一视频在本页中已作为作品或转发出现
+		}
+		added[entry.videoID] = true
+		deduped = append(deduped, entry)
+		videoIDs = append(videoIDs, entry.videoID)
+	}
+
+	isSeen := repo.CheckUserSeenBatch(context.TODO(), userID, videoIDs)
+	results = make([]postEntry, 0, len(deduped))
+	for i, entry := range deduped {
+		if !isSeen[i] {
+			results = append(results, entry)
+		}
+	}
+	if len(results) == 0 { // 已无未看视频时回退为不过滤
+		return deduped
+	}
+	return results
+}
+
+// 转发/取消转发
+func Repost(ctx *gin.Context, req *request.RepostReq) (resp *response.RepostResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 转发/取消转发
+	if req.Action_Type == 1 {
+		// 转发
+		if !checkVideoPublished(req.Video_ID) { // 不允许转发草稿
+			return nil, ErrorVideoInaccessible
+		}
+		_, err = repo.CreateRepost(context.TODO(), req_id.(uint), req.Video_ID, req.Content)
+		if err != nil {
+			utility.Logger().Errorf("CreateRepost err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		// 取消转发
+		err = repo.DeleteRepost(context.TODO(), req_id.(uint), req.Video_ID)
+		if err != nil {
+			utility.Logger().Errorf("DeleteRepost err: %v", err)
+			return nil, err
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.RepostResp{}, nil
+}
+
+// 获取用户转发列表
+func RepostList(ctx *gin.Context, req *request.RepostListReq) (resp *response.RepostListResp, err error) {
+	// 私密账号仅对本人及已关注者可见
+	err = checkUserVisible(ctx, req.User_ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取目标用户转发列表
+	reposts, err := repo.FindRepostsByCreatedAt(context.TODO(), []uint{req.User_ID}, req.Latest_Time, req.Latest_ID, feedSize) // 倒序向过去查找 最多30条
+	if err != nil {
+		utility.Logger().Errorf("FindRepostsByCreatedAt err: %v", err)
+		return nil, err
+	}
+
+	entries := make([]postEntry, 0, len(reposts))
+	for _, repost := range reposts {
+		entries = append(entries, postEntry{videoID: repost.VideoID, createdAt: repost.CreatedAt, repostID: repost.ID, userID: repost.UserID, content: repost.Content})
+	}
+
+	resp = &response.RepostListResp{Post_List: readPostInfoBatch(ctx, entries)} // 初始化响应
+	if len(reposts) > 0 {
+		resp.Next_Time = reposts[len(reposts)-1].CreatedAt.UnixMilli()
+		resp.Next_ID = reposts[len(reposts)-1].ID
+	}
+
+	return resp, nil
+}
+
+// 关注动态流(关注用户的作品及转发)
+func FollowingFeed(ctx *gin.Context, req *request.FollowingFeedReq) (resp *response.FollowingFeedResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 读取请求用户关注列表
+	follows, err := repo.ReadUserFollows(context.TODO(), req_id.(uint))
+	if err != nil {
+		utility.Logger().Errorf("ReadUserFollows err: %v", err)
+		return nil, err
+	}
+	followIDs := make([]uint, 0, len(follows))
+	for _, follow := range follows {
+		followIDs = append(followIDs, follow.ID)
+	}
+
+	// 分别读取关注用户的作品与转发 按(创建时间, ID)复合游标倒序向过去查找 各最多30条
+	videos, err := repo.FindVideosByAuthorsAndCreatedAt(context.TODO(), followIDs, req.Latest_Time, req.Latest_Video_ID, feedSize)
+	if err != nil {
+		utility.Logger().Errorf("FindVideosByAuthorsAndCreatedAt err: %v", err)
+		return nil, err
+	}
+	reposts, err := repo.FindRepostsByCreatedAt(context.TODO(), followIDs, req.Latest_Time, req.Latest_Repost_ID, feedSize)
+	if err != nil {
+		utility.Logger().Errorf("FindRepostsByCreatedAt err: %v", err)
+		return nil, err
+	}
+
+	// 按时间倒序合并 同一时刻作品在前、转发在后 各自按ID倒序 最多保留30条
+	entries := make([]postEntry, 0, len(videos)+len(reposts))
+	for _, video := range videos {
+		entries = append(entries, postEntry{videoID: video.ID, createdAt: video.CreatedAt})
+	}
+	for _, repost := range reposts {
+		entries = append(entries, postEntry{videoID: repost.VideoID, createdAt: repost.CreatedAt, repostID: repost.ID, userID: repost.UserID, content: repost.Content})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].createdAt.Equal(entries[j].createdAt) {
+			return entries[i].createdAt.After(entries[j].createdAt)
+		}
+		return entries[i].repostID == 0 && entries[j].repostID != 0 // 两路查询结果已各自按ID倒序 稳定排序保持该顺序
+	})
+	if len(entries) > feedSize {
+		entries = entries[:feedSize]
+	}
+
+	resp = &response.FollowingFeedResp{Post_List: readPostInfoBatch(ctx, dedupPostEntries(req_id.(uint), entries))} // 初始化响应
+	if len(entries) > 0 {
+		// 记录游标时刻及该时刻已返回的最早作品与转发(游标按去重前的条目计算)
+		last := entries[len(entries)-1]
+		resp.Next_Time = last.createdAt.UnixMilli()
+		for _, entry := range entries {
+			if !entry.createdAt.Equal(last.createdAt) {
+				continue
+			}
+			if entry.repostID == 0 {
+				resp.Next_Video_ID = entry.videoID
+			} else {
+				resp.Next_Repost_ID = entry.repostID
+			}
+		}
+	}
+
+	// 记录已推送视频
+	deliveredIDs := make([]uint, 0, len(resp.Post_List))
+	for _, postInfo := range resp.Post_List {
+		deliveredIDs = append(deliveredIDs, postInfo.Video.ID)
+	}
+	err = repo.SetUserSeen(context.TODO(), req_id.(uint), deliveredIDs)
+	if err != nil {
+		utility.Logger().Errorf("SetUserSeen err: %v", err) // 响应为获取成功 仅记录错误
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const linkTypeVideo = 1 // 视频短链接
+const linkTypeUser = 2  // 用户短链接
+
+// 短链接码首字符 用于区分目标类型
+var linkTags = map[int]byte{linkTypeVideo: 'v', linkTypeUser: 'u'}
+
+// 目标ID置换密钥 使短链接码无法由连续ID枚举 (由配置文件读取)
+var linkKey []byte
+
+const linkRounds = 4 // Feistel置换轮数
+
+// 自定义错误类型
+var ErrorLinkInvalid = errors.New("短链接无效或目标不存在")
+
+// Feistel轮函数 取HMAC-SHA256的前32位
+func linkRound(round int, half uint32) uint32 {
+	mac := hmac.New(sha256.New, linkKey)
+	var buf [5]byte
+	buf[0] = byte(round)
+	binary.BigEndian.PutUint32(buf[1:], half)
+	mac.Write(buf[:])
+	return binary.BigEndian.Uint32(mac.Sum(nil))
+}
+
+// 以带密钥的Feistel网络置换64位ID(inverse为true时为逆置换)
+func permuteLinkID(n uint64, inverse bool) uint64 {
+	left, right := uint32(n>>32), uint32(n)
+	for i := 0; i < linkRounds; i++ {
+		if inverse {
+			left, right = right^linkRound(linkRounds-1-i, left), left
+		} else {
+			left, right = right, left^linkRound(i, right)
+		}
+	}
+	return uint64(left)<<32 | uint64(right)
+}
+
+// 生成短链接码 格式为类型标识+六十二进制置换后的目标ID
+func encodeLink(targetType int, targetID uint) (code string) {
+	return string(linkTags[targetType]) + utility.EncodeBase62(permuteLinkID(uint64(targetID), false))
+}
+
+// 解析短链接码 仅接受规范形式(重新编码后与原码一致 如不含前导零)
+func decodeLink(code string) (targetType int, targetID uint, err error) {
+	if len(code) < 2 {
+		return 0, 0, ErrorLinkInvalid
+	}
+	for t, tag := range linkTags {
+		if code[0] == tag {
+			targetType = t
+		}
+	}
+	if targetType == 0 {
+		return 0, 0, ErrorLinkInvalid
+	}
+	n, err := utility.DecodeBase62(code[1:])
+	if err != nil {
+		return 0, 0, ErrorLinkInvalid
+	}
+	id := permuteLinkID(n, true)
+	if id == 0 || uint64(uint(id)) != id || encodeLink(targetType, uint(id)) != code {
+		return 0, 0, ErrorLinkInvalid
+	}
+	return targetType, uint(id), nil
+}
+
+// 检查短链接目标是否可访问
+func checkLinkTarget(targetType int, targetID uint) (isAccessible bool) {
+	if targetType == linkTypeVideo {
+		return checkVideoPublished(targetID)
+	}
+	_, err := repo.ReadUserBasics(context.TODO(), targetID)
+	return err == nil
+}
+
+// 生成分享短链接
+func ShareLink(ctx *gin.Context, req *request.ShareLinkReq) (resp *response.ShareLinkResp, err error) {
+	if !checkLinkTarget(req.Target_Type, req.Target_ID) {
+		return nil, ErrorLinkInvalid
+	}
+
+	code := encodeLink(req.Target_Type, req.Target_ID)
+	count, err := repo.GetLinkClicks(context.TODO(), code)
+	if err != nil {
+		utility.Logger().Errorf("GetLinkClicks err: %v", err) // 响应为获取成功 仅记录错误
+	}
+
+	return &response.ShareLinkResp{Code: code, Click_Count: count}, nil
+}
+
+// 解析分享短链接
+func ShareResolve(ctx *gin.Context, req *request.ShareResolveReq) (resp *response.ShareResolveResp, err error) {
+	targetType, targetID, err := decodeLink(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !checkLinkTarget(targetType, targetID) {
+		return nil, ErrorLinkInvalid
+	}
+
+	resp = &response.ShareResolveResp{Target_Type: targetType, Target_ID: targetID} // 初始化响应
+
+	// 记录点击量(以规范形式的短链接码计数)
+	resp.Click_Count, err = repo.IncrLinkClicks(context.TODO(), encodeLink(targetType, targetID))
+	if err != nil {
+		utility.Logger().Errorf("IncrLinkClicks err: %v", err) // 响应为获取成功 仅记录错误
+	}
+
+	// 获取目标地址
+	if targetType == linkTypeVideo {
+		videoURL, _, err := repo.GetVideo(context.TODO(), strconv.FormatUint(uint64(targetID), 10))
+		if err != nil {
+			utility.Logger().Errorf("GetVideo err: %v", err)
+			return nil, err
+		}
+		resp.Redirect_URL = videoURL
+	} else {
+		resp.Redirect_URL = "/douyin/publish/list/?user_id=" + strconv.FormatUint(uint64(targetID), 10)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+)
+
+// 设置测试用置换密钥
+func setTestLinkKey(t *testing.T, key string) {
+	old := linkKey
+	linkKey = []byte(key)
+	t.Cleanup(func() { linkKey = old })
+}
+
+func TestLinkRoundTrip(t *testing.T) {
+	setTestLinkKey(t, "test-link-key")
+	for _, targetType := range []int{linkTypeVideo, linkTypeUser} {
+		for _, id := range []uint{1, 2, 62, 12345, 1 << 31} {
+			code := encodeLink(targetType, id)
+			gotType, gotID, err := decodeLink(code)
+			if err != nil || gotType != targetType || gotID != id {
+				t.Errorf("decodeLink(encodeLink(%d, %d)=%q) = %d, %d, %v", targetType, id, code, gotType, gotID, err)
+			}
+		}
+	}
+}
+
+func TestLinkDistinct(t *testing.T) {
+	setTestLinkKey(t, "test-link-key")
+	// 不同ID的短链接码互不相同 且不直接暴露原始ID 以防枚举
+	seen := make(map[string]bool)
+	for id := uint(1); id <= 1000; id++ {
+		code := encodeLink(linkTypeVideo, id)
+		if seen[code] {
+			t.Fatalf("encodeLink(%d) = %q collides", id, code)
+		}
+		seen[code] = true
+	}
+	if encodeLink(linkTypeVideo, 2) == "v2" {
+		t.Error("encodeLink exposes the raw ID")
+	}
+}
+
+func TestLinkKeyed(t *testing.T) {
+	// 相同ID在不同密钥下的短链接码不同 且无法以其他密钥解析回原ID
+	setTestLinkKey(t, "test-link-key")
+	code := encodeLink(linkTypeUser, 42)
+	linkKey = []byte("another-link-key")
+	if other := encodeLink(linkTypeUser, 42); other == code {
+		t.Errorf("encodeLink(42) = %q under both keys", code)
+	}
+	if _, id, err := decodeLink(code); err == nil && id == 42 {
+		t.Errorf("decodeLink(%q) under another key = 42", code)
+	}
+}
+
+func TestDecodeLinkInvalid(t *testing.T) {
+	setTestLinkKey(t, "test-link-key")
+	code := encodeLink(linkTypeVideo, 42)
+	for _, c := range []string{
+		"",
+		"v",
+		"x" + code[1:],       // 未知类型标识
+		"v0" + code[1:],      // 前导零(非规范形式)
+		"v!" + code[2:],      // 非法字符
+		"v" + "zzzzzzzzzzzz", // 超长
+	} {
+		if typ, id, err := decodeLink(c); err != ErrorLinkInvalid {
+			t.Errorf("decodeLink(%q) = %d, %d, %v, want ErrorLinkInvalid", c, typ, id, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"douyin/repo"
+	"douyin/service/type/request"
+	"douyin/service/type/response"
+	"douyin/utility"
+
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const trashPageSize = 30 // 回收站列表单页默认返回的条目数量
+
+// 自定义错误类型
+var ErrorTrashInaccessible = errors.New("回收站条目不存在、已过期或无权访问")
+var ErrorTrashConflict = errors.New("所属视频或所回复的评论已被删除, 无法恢复")
+
+// 分页获取回收站列表
+func TrashList(ctx *gin.Context, req *request.TrashListReq) (resp *response.TrashListResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 分页读取回收站条目
+	items, hasMore, nextOffset, err := readPage(repo.FindUserTrash, req_id.(uint), req.Offset, req.Count, trashPageSize)
+	if err != nil {
+		utility.Logger().Errorf("FindUserTrash err: %v", err)
+		return nil, err
+	}
+
+	resp = &response.TrashListResp{Has_More: hasMore, Next_Offset: nextOffset} // 初始化响应
+
+	resp.Trash_List = make([]response.TrashItem, 0, len(items))
+	for i := range items {
+		resp.Trash_List = append(resp.Trash_List, response.TrashItem{
+			ID:          items[i].ID,
+			Target_Type: items[i].TargetType,
+			Target_ID:   items[i].TargetID,
+			Summary:     items[i].Summary,
+			Delete_Time: items[i].CreatedAt.UnixMilli(),
+			Expire_Time: repo.TrashExpireTime(&items[i]).UnixMilli(),
+		})
+	}
+
+	return resp, nil
+}
+
+// 恢复回收站条目
+func TrashRestore(ctx *gin.Context, req *request.TrashRestoreReq) (resp *response.TrashRestoreResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 恢复被删除的内容 仅内容作者本人可在保留期内恢复
+	trash, err := repo.RestoreTrash(context.TODO(), req.Trash_ID, req_id.(uint))
+	if err == repo.ErrorRecordNotExists {
+		return nil, ErrorTrashInaccessible
+	}
+	if err == repo.ErrorRestoreConflict {
+		return nil, ErrorTrashConflict
+	}
+	if err != nil {
+		utility.Logger().Errorf("RestoreTrash err: %v", err)
+		return nil, err
+	}
+
+	return &response.TrashRestoreResp{Target_Type: trash.TargetType, Target_ID: trash.TargetID}, nil
+}
@@ -0,0 +1,36 @@
+package request
+
+type CollectionReq struct {
+	Token         string `json:"token" form:"token" binding:"required,jwt"`                                                  // 用户鉴权token
+	Action_Type   int    `json:"action_type" form:"action_type" binding:"required,min=1,max=3"`                              // 1-创建合集，2-编辑合集，3-删除合集
+	Collection_ID uint   `json:"collection_id" form:"collection_id" binding:"required_unless=Action_Type 1,omitempty,min=1"` // 可选参数，要编辑或删除的合集id，在action_type=2或3的时候使用
+	Name          string `json:"name" form:"name" binding:"required_if=Action_Type 1,omitempty,min=1,max=64"`                // 可选参数，合集名称，在action_type=1的时候使用，action_type=2时不填表示不修改
+	Kind          int    `json:"kind" form:"kind" binding:"omitempty,min=1,max=2"`                                           // 可选参数，1-合集(仅可收录自己的作品)，2-收藏夹，在action_type=1的时候使用，不填默认为收藏夹
+	Visibility    int    `json:"visibility" form:"visibility" binding:"omitempty,min=1,max=2"`                               // 可选参数，1-公开，2-私密，action_type=1时不填默认为公开，action_type=2时不填表示不修改
+}
+
+type CollectionListReq struct {
+	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 用户id
+	Token   string `json:"token" form:"token" binding:"omitempty,jwt"`      // 可选参数，用户鉴权token，本人请求时返回私密合集
+}
+
+type CollectionItemReq struct {
+	Token         string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Collection_ID uint   `json:"collection_id" form:"collection_id" binding:"required,min=1"`   // 合集id
+	Video_ID      uint   `json:"video_id" form:"video_id" binding:"required,min=1"`             // 视频id
+	Action_Type   int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-添加视频，2-移除视频
+}
+
+type CollectionMoveReq struct {
+	Token         string `json:"token" form:"token" binding:"required,jwt"`                   // 用户鉴权token
+	Collection_ID uint   `json:"collection_id" form:"collection_id" binding:"required,min=1"` // 合集id
+	Video_ID      uint   `json:"video_id" form:"video_id" binding:"required,min=1"`           // 要移动的视频id
+	Position      int    `json:"position" form:"position" binding:"min=0"`                    // 目标位置，从0开始，超出范围时移至末尾
+}
+
+type CollectionVideoListReq struct {
+	Collection_ID uint   `json:"collection_id" form:"collection_id" binding:"required,min=1"` // 合集id
+	Token         string `json:"token" form:"token" binding:"omitempty,jwt"`                  // 可选参数，用户鉴权token，访问私密合集时必须
+	Offset        int    `json:"offset" form:"offset" binding:"min=0"`                        // 可选参数，分页偏移量，不填默认为0
+	Count         int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"`         // 可选参数，单页数量，不填默认为30
+}
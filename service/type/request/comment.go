@@ -6,9 +6,50 @@ type CommentReq struct {
 	Action_Type  int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"`                                // 1-发布评论，2-删除评论
 	Comment_Text string `json:"comment_text" form:"comment_text" binding:"required_if=Action_Type 1,omitempty,min=1,max=256"` // 可选参数，用户填写的评论内容，在action_type=1的时候使用
 	Comment_ID   uint   `json:"comment_id" form:"comment_id" binding:"required_if=Action_Type 2,omitempty,min=1"`             // 可选参数，要删除的评论id，在action_type=2的时候使用
+	Parent_ID    uint   `json:"parent_id" form:"parent_id" binding:"omitempty,min=1"`                                         // 可选参数，所回复的评论id，在action_type=1的时候使用，不填时为顶层评论
 }
 
 type CommentListReq struct {
-	Token    string `json:"token" form:"token" binding:"omitempty,jwt"`        // 用户鉴权token API文档有误 应为可选参数
-	Video_ID uint   `json:"video_id" form:"video_id" binding:"required,min=1"` // 视频id
+	Token     string `json:"token" form:"token" binding:"omitempty,jwt"`                 // 用户鉴权token API文档有误 应为可选参数
+	Video_ID  uint   `json:"video_id" form:"video_id" binding:"required,min=1"`          // 视频id
+	Sort_Type int    `json:"sort_type" form:"sort_type" binding:"omitempty,min=1,max=3"` // 可选参数，1-最新，2-最早，3-最热，不填默认为1
+	Cursor    int64  `json:"cursor" form:"cursor" binding:"min=0"`                       // 可选参数，分页游标，填写上一页返回的next_cursor，不填默认为从头开始
+	Count     int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"`        // 可选参数，单页数量，不填默认为30
+}
+
+type CommentReplyListReq struct {
+	Token      string `json:"token" form:"token" binding:"omitempty,jwt"`            // 可选参数，用户鉴权token
+	Comment_ID uint   `json:"comment_id" form:"comment_id" binding:"required,min=1"` // 顶层评论id
+	Offset     int    `json:"offset" form:"offset" binding:"min=0"`                  // 可选参数，分页偏移量，不填默认为0
+	Count      int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"`   // 可选参数，单页数量，不填默认为30
+}
+
+type CommentLikeReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Comment_ID  uint   `json:"comment_id" form:"comment_id" binding:"required,min=1"`         // 评论id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-点赞，2-取消点赞
+}
+
+type CommentPinReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                                        // 用户鉴权token
+	Video_ID    uint   `json:"video_id" form:"video_id" binding:"required,min=1"`                                // 视频id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"`                    // 1-置顶，2-取消置顶
+	Comment_ID  uint   `json:"comment_id" form:"comment_id" binding:"required_if=Action_Type 1,omitempty,min=1"` // 可选参数，要置顶的顶层评论id，在action_type=1的时候使用
+}
+
+type CommentPolicyReq struct {
+	Token          string `json:"token" form:"token" binding:"required,jwt"`                           // 用户鉴权token
+	Video_ID       uint   `json:"video_id" form:"video_id" binding:"required,min=1"`                   // 视频id
+	Comment_Policy int    `json:"comment_policy" form:"comment_policy" binding:"required,min=1,max=4"` // 1-所有人可评论，2-关闭评论，3-仅粉丝可评论，4-仅互关好友可评论
+}
+
+type CommentEditReq struct {
+	Token        string `json:"token" form:"token" binding:"required,jwt"`                         // 用户鉴权token
+	Comment_ID   uint   `json:"comment_id" form:"comment_id" binding:"required,min=1"`             // 要编辑的评论id
+	Comment_Text string `json:"comment_text" form:"comment_text" binding:"required,min=1,max=256"` // 编辑后的评论内容
+}
+
+type CommentRevisionListReq struct {
+	Token      string `json:"token" form:"token" binding:"required,jwt"`             // 审核员鉴权token
+	Comment_ID uint   `json:"comment_id" form:"comment_id" binding:"required,min=1"` // 评论id
 }
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"mime/multipart"
+)
+
+type DraftReq struct {
+	Token       string                `json:"token" form:"token" binding:"required,jwt"`                                        // 用户鉴权token
+	Action_Type int                   `json:"action_type" form:"action_type" binding:"required,min=1,max=3"`                    // 1-创建草稿，2-编辑草稿，3-删除草稿
+	Draft_ID    uint                  `json:"draft_id" form:"draft_id" binding:"required_unless=Action_Type 1,omitempty,min=1"` // 可选参数，要编辑或删除的草稿id，在action_type=2或3的时候使用
+	Data        *multipart.FileHeader `json:"data" form:"data"`                                                                 // 可选参数，视频数据，在action_type=1的时候使用
+	Title       string                `json:"title" form:"title" binding:"omitempty,min=1,max=256"`                             // 可选参数，视频标题，在action_type=1的时候使用，action_type=2时不填表示不修改
+	Cover       *multipart.FileHeader `json:"cover" form:"cover"`                                                               // 可选参数，封面图片，在action_type=2的时候使用，不填表示不修改
+}
+
+type DraftListReq struct {
+	Token string `json:"token" form:"token" binding:"required,jwt"` // 用户鉴权token
+}
+
+type DraftPublishReq struct {
+	Token    string `json:"token" form:"token" binding:"required,jwt"`         // 用户鉴权token
+	Draft_ID uint   `json:"draft_id" form:"draft_id" binding:"required,min=1"` // 要发布的草稿id
+}
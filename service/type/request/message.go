@@ -1,10 +1,18 @@
 package request
 
+import (
+	"mime/multipart"
+)
+
 type MessageReq struct {
-	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
-	To_User_ID  uint   `json:"to_user_id" form:"to_user_id" binding:"required,min=1"`         // 对方用户id
-	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=1"` // 1-发送消息
-	Content     string `json:"content" form:"content" binding:"required,min=1,max=256"`       // 消息内容
+	Token        string                `json:"token" form:"token" binding:"required,jwt"`                        // 用户鉴权token
+	To_User_ID   uint                  `json:"to_user_id" form:"to_user_id" binding:"required,min=1"`            // 对方用户id
+	Action_Type  int                   `json:"action_type" form:"action_type" binding:"required,min=1,max=1"`    // 1-发送消息
+	Message_Type int                   `json:"message_type" form:"message_type" binding:"omitempty,min=1,max=4"` // 可选参数，1-文本，2-图片，3-视频分享，4-名片，不填默认为1
+	Content      string                `json:"content" form:"content" binding:"max=256"`                         // 消息内容，文本消息必填，其他类型消息可作为附言
+	Image        *multipart.FileHeader `json:"image" form:"image"`                                               // 图片，在message_type=2的时候使用
+	Video_ID     uint                  `json:"video_id" form:"video_id"`                                         // 分享的视频id，在message_type=3的时候使用
+	Card_User_ID uint                  `json:"card_user_id" form:"card_user_id"`                                 // 分享的用户id，在message_type=4的时候使用
 }
 
 type MessageListReq struct {
@@ -12,3 +20,42 @@ type MessageListReq struct {
 	To_User_ID   uint   `json:"to_user_id" form:"to_user_id" binding:"required,min=1"`      // 对方用户id
 	Pre_Msg_Time int64  `json:"pre_msg_time" form:"pre_msg_time" binding:"omitempty,min=0"` // 可选参数，上次最新消息的时间 API文档有误 应有此项且为可选参数
 }
+
+type ConversationListReq struct {
+	Token  string `json:"token" form:"token" binding:"required,jwt"`           // 用户鉴权token
+	Offset int    `json:"offset" form:"offset" binding:"min=0"`                // 可选参数，分页偏移量，不填默认为0
+	Count  int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，单页数量，不填默认为30
+}
+
+type ConversationReadReq struct {
+	Token      string `json:"token" form:"token" binding:"required,jwt"`             // 用户鉴权token
+	To_User_ID uint   `json:"to_user_id" form:"to_user_id" binding:"required,min=1"` // 对方用户id
+}
+
+type UnreadCountReq struct {
+	Token string `json:"token" form:"token" binding:"required,jwt"` // 用户鉴权token
+}
+
+type MessageAckReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	To_User_ID  uint   `json:"to_user_id" form:"to_user_id" binding:"required,min=1"`         // 对方用户id(消息发送者)
+	Message_ID  uint   `json:"message_id" form:"message_id" binding:"required,min=1"`         // 确认至该消息id(含)为止对方发来的全部消息
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-已送达，2-已读
+}
+
+type MessageDeleteReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Message_ID  uint   `json:"message_id" form:"message_id" binding:"required,min=1"`         // 消息id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-撤回(为聊天双方删除 仅限发送者在可撤回时限内操作)，2-仅为自己删除
+}
+
+type ChatStreamReq struct {
+	Token string `json:"token" form:"token" binding:"required,jwt"` // 用户鉴权token
+}
+
+// 客户端经WebSocket上行的聊天事件
+type ChatEvent struct {
+	Type       string `json:"type"`       // typing-正在输入，delivered-已送达，read-已读
+	To_User_ID uint   `json:"to_user_id"` // 对方用户id
+	Message_ID uint   `json:"message_id"` // 已送达/已读的最新消息id(仅用于已送达及已读事件)
+}
@@ -0,0 +1,12 @@
+package request
+
+type NotificationListReq struct {
+	Token  string `json:"token" form:"token" binding:"required,jwt"`           // 用户鉴权token
+	Offset int    `json:"offset" form:"offset" binding:"min=0"`                // 可选参数，分页偏移量，不填默认为0
+	Count  int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，单页数量，不填默认为30
+}
+
+type NotificationReadReq struct {
+	Token           string `json:"token" form:"token" binding:"required,jwt"`                        // 用户鉴权token
+	Notification_ID uint   `json:"notification_id" form:"notification_id" binding:"omitempty,min=1"` // 可选参数，要标记为已读的通知id，不填时标记全部通知
+}
@@ -14,3 +14,8 @@ type PublishListReq struct {
 	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 用户id
 	Token   string `json:"token" form:"token" binding:"omitempty,jwt"`      // 用户鉴权token API文档有误 应为可选参数
 }
+
+type PublishDeleteReq struct {
+	Token    string `json:"token" form:"token" binding:"required,jwt"`         // 用户鉴权token
+	Video_ID uint   `json:"video_id" form:"video_id" binding:"required,min=1"` // 要删除的视频id
+}
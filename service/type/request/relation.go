@@ -6,6 +6,11 @@ type FollowReq struct {
 	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-关注，2-取消关注
 }
 
+type RemoveFollowerReq struct {
+	Token   string `json:"token" form:"token" binding:"required,jwt"`       // 用户鉴权token
+	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 要移除的粉丝用户id
+}
+
 type FollowListReq struct {
 	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 用户id
 	Token   string `json:"token" form:"token" binding:"omitempty,jwt"`      // 用户鉴权token API文档有误 应为可选参数
@@ -20,3 +25,30 @@ type FriendListReq struct {
 	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 用户id
 	Token   string `json:"token" form:"token" binding:"required,jwt"`       // 用户鉴权token
 }
+
+type BlockReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	To_User_ID  uint   `json:"to_user_id" form:"to_user_id" binding:"required,min=1"`         // 对方用户id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-拉黑，2-取消拉黑
+}
+
+type BlockListReq struct {
+	Token string `json:"token" form:"token" binding:"required,jwt"` // 用户鉴权token
+}
+
+type FollowRequestListReq struct {
+	Token  string `json:"token" form:"token" binding:"required,jwt"`           // 用户鉴权token
+	Offset int    `json:"offset" form:"offset" binding:"min=0"`                // 可选参数，分页偏移量，不填默认为0
+	Count  int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，单页数量，不填默认为30
+}
+
+type FollowRequestActionReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Request_ID  uint   `json:"request_id" form:"request_id" binding:"required,min=1"`         // 关注申请id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-同意，2-拒绝
+}
+
+type RecommendListReq struct {
+	Token string `json:"token" form:"token" binding:"required,jwt"`           // 用户鉴权token
+	Count int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，返回数量，不填默认为30
+}
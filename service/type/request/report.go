@@ -0,0 +1,22 @@
+package request
+
+type ReportReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Target_Type int    `json:"target_type" form:"target_type" binding:"required,min=1,max=4"` // 1-视频，2-评论，3-消息，4-用户签名
+	Target_ID   uint   `json:"target_id" form:"target_id" binding:"required,min=1"`           // 被举报的视频id、评论id、消息id或用户id
+	Reason      int    `json:"reason" form:"reason" binding:"required,min=1,max=6"`           // 1-垃圾广告，2-辱骂攻击，3-色情低俗，4-暴力血腥，5-违法违规，6-其他
+	Detail      string `json:"detail" form:"detail" binding:"omitempty,max=256"`              // 可选参数，举报补充说明
+}
+
+type ModerationListReq struct {
+	Token  string `json:"token" form:"token" binding:"required,jwt"`           // 审核员鉴权token
+	State  int    `json:"state" form:"state" binding:"omitempty,min=1,max=3"`  // 可选参数，1-待处理，2-已处理，3-已驳回，不填默认为待处理
+	Offset int    `json:"offset" form:"offset" binding:"min=0"`                // 可选参数，分页偏移量，不填默认为0
+	Count  int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，单页数量，不填默认为30
+}
+
+type ModerationReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 审核员鉴权token
+	Report_ID   uint   `json:"report_id" form:"report_id" binding:"required,min=1"`           // 要处理的举报id，同一对象的全部待处理举报将一并处理
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-处理(隐藏内容)，2-驳回(恢复内容，曾被处理隐藏的内容除外)
+}
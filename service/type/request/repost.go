@@ -0,0 +1,22 @@
+package request
+
+type RepostReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Video_ID    uint   `json:"video_id" form:"video_id" binding:"required,min=1"`             // 视频id
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-转发，2-取消转发
+	Content     string `json:"content" form:"content" binding:"omitempty,max=256"`            // 可选参数，转发时附带的评论，在action_type=1的时候使用
+}
+
+type RepostListReq struct {
+	User_ID     uint   `json:"user_id" form:"user_id" binding:"required,min=1"`          // 用户id
+	Token       string `json:"token" form:"token" binding:"omitempty,jwt"`               // 可选参数，用户鉴权token
+	Latest_Time int64  `json:"latest_time" form:"latest_time" binding:"omitempty,min=0"` // 可选参数，限制返回转发的最新时间戳，精确到毫秒，不填表示当前时间
+	Latest_ID   uint   `json:"latest_id" form:"latest_id" binding:"omitempty,min=0"`     // 可选参数，与latest_time组成复合游标，该时刻仅返回id小于此值的转发，不填表示该时刻的全部转发
+}
+
+type FollowingFeedReq struct {
+	Token            string `json:"token" form:"token" binding:"required,jwt"`                          // 用户鉴权token
+	Latest_Time      int64  `json:"latest_time" form:"latest_time" binding:"omitempty,min=0"`           // 可选参数，限制返回动态的最新时间戳，精确到毫秒，不填表示当前时间
+	Latest_Video_ID  uint   `json:"latest_video_id" form:"latest_video_id" binding:"omitempty,min=0"`   // 可选参数，与latest_time组成复合游标，该时刻仅返回id小于此值的作品，不填表示该时刻的全部作品
+	Latest_Repost_ID uint   `json:"latest_repost_id" form:"latest_repost_id" binding:"omitempty,min=0"` // 可选参数，与latest_time组成复合游标，该时刻仅返回id小于此值的转发，不填表示该时刻的全部转发
+}
@@ -0,0 +1,12 @@
+package request
+
+type ShareLinkReq struct {
+	Target_Type int    `json:"target_type" form:"target_type" binding:"required,min=1,max=2"` // 1-视频，2-用户
+	Target_ID   uint   `json:"target_id" form:"target_id" binding:"required,min=1"`           // 视频id或用户id
+	Token       string `json:"token" form:"token" binding:"omitempty,jwt"`                    // 可选参数，用户鉴权token
+}
+
+type ShareResolveReq struct {
+	Code     string `json:"code" form:"code" binding:"required,min=2,max=12,alphanum"` // 短链接码
+	Redirect bool   `json:"redirect" form:"redirect"`                                  // 可选参数，true-重定向至目标地址，false-返回目标信息，不填默认为false
+}
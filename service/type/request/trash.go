@@ -0,0 +1,12 @@
+package request
+
+type TrashListReq struct {
+	Token  string `json:"token" form:"token" binding:"required,jwt"`           // 用户鉴权token
+	Offset int    `json:"offset" form:"offset" binding:"min=0"`                // 可选参数，分页偏移量，不填默认为0
+	Count  int    `json:"count" form:"count" binding:"omitempty,min=1,max=30"` // 可选参数，单页数量，不填默认为30
+}
+
+type TrashRestoreReq struct {
+	Token    string `json:"token" form:"token" binding:"required,jwt"`         // 用户鉴权token
+	Trash_ID uint   `json:"trash_id" form:"trash_id" binding:"required,min=1"` // 要恢复的回收站条目id
+}
@@ -14,3 +14,13 @@ type UserInfoReq struct {
 	User_ID uint   `json:"user_id" form:"user_id" binding:"required,min=1"` // 用户id
 	Token   string `json:"token" form:"token" binding:"required,jwt"`       // 用户鉴权token
 }
+
+type UserSignatureReq struct {
+	Token     string `json:"token" form:"token" binding:"required,jwt"`             // 用户鉴权token
+	Signature string `json:"signature" form:"signature" binding:"required,max=256"` // 个人签名
+}
+
+type UserPrivacyReq struct {
+	Token       string `json:"token" form:"token" binding:"required,jwt"`                     // 用户鉴权token
+	Action_Type int    `json:"action_type" form:"action_type" binding:"required,min=1,max=2"` // 1-设为私密账号，2-设为公开账号(待处理的关注申请将被全部同意)
+}
@@ -0,0 +1,26 @@
+package response
+
+type CollectionResp struct {
+	Status
+	Collection_ID uint `json:"collection_id"` // 所操作的合集id
+}
+
+type CollectionListResp struct {
+	Status
+	Collection_List []Collection `json:"collection_list"` // 用户合集列表
+}
+
+type CollectionItemResp struct {
+	Status
+}
+
+type CollectionMoveResp struct {
+	Status
+}
+
+type CollectionVideoListResp struct {
+	Status
+	Video_List  []Video `json:"video_list"`  // 合集中的视频列表
+	Next_Offset int     `json:"next_offset"` // 下一页的分页偏移量
+	Has_More    bool    `json:"has_more"`    // true-还有更多，false-已无更多
+}
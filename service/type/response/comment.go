@@ -8,4 +8,36 @@ type CommentResp struct {
 type CommentListResp struct {
 	Status
 	Comment_List []Comment `json:"comment_list"` // 评论列表
+	Next_Cursor  int64     `json:"next_cursor"`  // 下一页的分页游标
+	Has_More     bool      `json:"has_more"`     // true-还有更多，false-已无更多
+}
+
+type CommentReplyListResp struct {
+	Status
+	Reply_List  []Comment `json:"reply_list"`  // 回复列表 按时间顺序
+	Next_Offset int       `json:"next_offset"` // 下一页的分页偏移量
+	Has_More    bool      `json:"has_more"`    // true-还有更多，false-已无更多
+}
+
+type CommentLikeResp struct {
+	Status
+}
+
+type CommentPinResp struct {
+	Status
+}
+
+type CommentPolicyResp struct {
+	Status
+}
+
+type CommentEditResp struct {
+	Status
+	Comment Comment `json:"comment"` // 编辑后的评论内容
+}
+
+type CommentRevisionListResp struct {
+	Status
+	Content       string            `json:"content"`       // 当前评论内容
+	Revision_List []CommentRevision `json:"revision_list"` // 修订记录列表，按时间顺序
 }
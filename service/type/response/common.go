@@ -19,35 +19,71 @@ type User struct {
 	Total_Favorited  uint   `json:"total_favorited"`  // 获赞数量
 	Work_Count       uint   `json:"work_count"`       // 作品数
 	Favorite_Count   uint   `json:"favorite_count"`   // 喜欢数
+	Is_Private       bool   `json:"is_private"`       // true-私密账号，false-公开账号
 }
 
 // 视频信息
 type Video struct {
-	ID             uint   `json:"id"`             // 视频唯一标识
-	Author         User   `json:"author"`         // 视频作者信息
-	Play_URL       string `json:"play_url"`       // 视频播放地址
-	Cover_URL      string `json:"cover_url"`      // 视频封面地址
-	Favorite_Count uint   `json:"favorite_count"` // 视频的点赞总数
-	Comment_Count  uint   `json:"comment_count"`  // 视频的评论总数
-	Is_Favorite    bool   `json:"is_favorite"`    // true-已点赞，false-未点赞
-	Title          string `json:"title"`          // 视频标题
+	ID             uint      `json:"id"`             // 视频唯一标识
+	Author         User      `json:"author"`         // 视频作者信息
+	Play_URL       string    `json:"play_url"`       // 视频播放地址
+	Cover_URL      string    `json:"cover_url"`      // 视频封面地址
+	Favorite_Count uint      `json:"favorite_count"` // 视频的点赞总数
+	Comment_Count  uint      `json:"comment_count"`  // 视频的评论总数
+	Repost_Count   uint      `json:"repost_count"`   // 视频的转发总数
+	Is_Favorite    bool      `json:"is_favorite"`    // true-已点赞，false-未点赞
+	Title          string    `json:"title"`          // 视频标题
+	Comment_Policy int       `json:"comment_policy"` // 评论权限，1-所有人，2-关闭评论，3-仅粉丝，4-仅互关好友
+	Mentions       []Mention `json:"mentions"`       // 标题中的@提及
 }
 
 // 评论信息
 type Comment struct {
-	ID          uint   `json:"id"`          // 评论id
-	User        User   `json:"user"`        // 评论用户信息
-	Content     string `json:"content"`     // 评论内容
-	Create_Date string `json:"create_date"` // 评论发布日期，格式 mm-dd
+	ID          uint      `json:"id"`          // 评论id
+	User        User      `json:"user"`        // 评论用户信息
+	Content     string    `json:"content"`     // 评论内容
+	Create_Date string    `json:"create_date"` // 评论发布日期，格式 mm-dd
+	Parent_ID   uint      `json:"parent_id"`   // 所回复的评论id，顶层评论为0
+	Root_ID     uint      `json:"root_id"`     // 所属顶层评论id，顶层评论为0
+	Reply_Count int64     `json:"reply_count"` // 回复总数，仅顶层评论有效
+	Like_Count  int64     `json:"like_count"`  // 评论的点赞总数
+	Is_Liked    bool      `json:"is_liked"`    // true-已点赞，false-未点赞
+	Is_Pinned   bool      `json:"is_pinned"`   // true-已被视频作者置顶，false-未置顶
+	Is_Edited   bool      `json:"is_edited"`   // true-发布后被作者编辑过，false-未编辑
+	Mentions    []Mention `json:"mentions"`    // 评论中的@提及
+}
+
+// @提及信息
+type Mention struct {
+	User_ID uint `json:"user_id"` // 被提及的用户id
+	Offset  int  `json:"offset"`  // @符号在文本中的位置，以字符计
+	Length  int  `json:"length"`  // 含@符号在内的长度，以字符计
+}
+
+// 通知信息
+type Notification struct {
+	ID          uint     `json:"id"`                // 通知id
+	Type        int      `json:"type"`              // 1-在视频标题中被提及，2-在评论中被提及
+	Actor       User     `json:"actor"`             // 触发通知的用户信息
+	Video_ID    uint     `json:"video_id"`          // 相关视频id
+	Video       *Video   `json:"video,omitempty"`   // 在视频标题中被提及时为该视频信息
+	Comment     *Comment `json:"comment,omitempty"` // 在评论中被提及时为该评论信息
+	Is_Read     bool     `json:"is_read"`           // true-已读，false-未读
+	Create_Time int64    `json:"create_time"`       // 通知时间，毫秒时间戳
 }
 
 // 聊天信息
 type Message struct {
-	ID           uint   `json:"id"`           // 消息id
-	To_User_ID   uint   `json:"to_user_id"`   // 消息接收者id
-	From_User_ID uint   `json:"from_user_id"` // 消息发送者id
-	Content      string `json:"content"`      // 消息内容
-	Create_Time  int64  `json:"create_time"`  // 消息发送时间 API文档有误 实为毫秒时间戳
+	ID           uint   `json:"id"`                  // 消息id
+	To_User_ID   uint   `json:"to_user_id"`          // 消息接收者id
+	From_User_ID uint   `json:"from_user_id"`        // 消息发送者id
+	Content      string `json:"content"`             // 消息内容
+	Create_Time  int64  `json:"create_time"`         // 消息发送时间 API文档有误 实为毫秒时间戳
+	Status       int    `json:"status"`              // 1-已发送，2-已送达，3-已读
+	Message_Type int    `json:"message_type"`        // 1-文本，2-图片，3-视频分享，4-名片
+	Image_URL    string `json:"image_url,omitempty"` // 图片外链(仅用于图片消息)
+	Video        *Video `json:"video,omitempty"`     // 分享的视频(仅用于视频分享消息 不可见时不返回)
+	User         *User  `json:"user,omitempty"`      // 分享的用户(仅用于名片消息 不可见时不返回)
 }
 
 // 用户(好友)信息
@@ -56,3 +92,73 @@ type FriendUser struct {
 	Message  string `json:"message"` // 和该好友的最新聊天消息
 	Msg_Type int    `json:"msgType"` // message消息的类型，0 => 当前请求用户接收的消息， 1 => 当前请求用户发送的消息
 }
+
+// 合集信息
+type Collection struct {
+	ID          uint   `json:"id"`          // 合集id
+	Owner_ID    uint   `json:"owner_id"`    // 合集创建者id
+	Name        string `json:"name"`        // 合集名称
+	Kind        int    `json:"kind"`        // 1-合集(仅收录自己的作品)，2-收藏夹
+	Is_Public   bool   `json:"is_public"`   // true-公开，false-私密
+	Video_Count uint   `json:"video_count"` // 收录视频数
+}
+
+// 转发信息
+type Repost struct {
+	ID          uint   `json:"id"`          // 转发id
+	User        User   `json:"user"`        // 转发用户信息
+	Content     string `json:"content"`     // 转发时附带的评论
+	Create_Time int64  `json:"create_time"` // 转发时间，毫秒时间戳
+}
+
+// 动态信息(作品或转发)
+type Post struct {
+	Video  Video   `json:"video"`            // 视频信息
+	Repost *Repost `json:"repost,omitempty"` // 转发信息，为空表示原创作品
+}
+
+// 举报信息
+type Report struct {
+	ID             uint   `json:"id"`             // 举报id
+	Reporter_ID    uint   `json:"reporter_id"`    // 举报用户id
+	Target_Type    int    `json:"target_type"`    // 1-视频，2-评论，3-消息，4-用户签名
+	Target_ID      uint   `json:"target_id"`      // 被举报的视频id、评论id、消息id或用户id
+	Target_Content string `json:"target_content"` // 被举报内容，视频为标题，评论和消息为正文，用户为签名
+	Reason         int    `json:"reason"`         // 1-垃圾广告，2-辱骂攻击，3-色情低俗，4-暴力血腥，5-违法违规，6-其他
+	Detail         string `json:"detail"`         // 举报补充说明
+	State          int    `json:"state"`          // 1-待处理，2-已处理，3-已驳回
+	Create_Time    int64  `json:"create_time"`    // 举报时间，毫秒时间戳
+}
+
+// 评论修订记录
+type CommentRevision struct {
+	ID          uint   `json:"id"`          // 修订记录id
+	Editor_ID   uint   `json:"editor_id"`   // 编辑者id
+	Content     string `json:"content"`     // 编辑前的评论内容
+	Create_Time int64  `json:"create_time"` // 编辑时间，毫秒时间戳
+}
+
+// 回收站条目
+type TrashItem struct {
+	ID          uint   `json:"id"`          // 回收站条目id
+	Target_Type int    `json:"target_type"` // 1-视频(含草稿)，2-评论(含其下全部回复)
+	Target_ID   uint   `json:"target_id"`   // 被删除的视频id或评论id
+	Summary     string `json:"summary"`     // 删除时的视频标题或评论内容
+	Delete_Time int64  `json:"delete_time"` // 删除时间，毫秒时间戳
+	Expire_Time int64  `json:"expire_time"` // 过期时间(此后将被彻底清除且无法恢复)，毫秒时间戳
+}
+
+// 关注申请
+type FollowRequest struct {
+	ID          uint  `json:"id"`          // 关注申请id
+	User        User  `json:"user"`        // 申请者用户信息
+	Create_Time int64 `json:"create_time"` // 申请时间，毫秒时间戳
+}
+
+// 会话
+type Conversation struct {
+	User             User     `json:"user"`                   // 对方用户信息
+	Last_Message     *Message `json:"last_message,omitempty"` // 最近一条消息(被隐藏时不返回)
+	Unread_Count     int64    `json:"unread_count"`           // 未读消息数
+	Last_Active_Time int64    `json:"last_active_time"`       // 最近活跃时间，毫秒时间戳
+}
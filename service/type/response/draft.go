@@ -0,0 +1,21 @@
+package response
+
+type DraftResp struct {
+	Status
+	Draft_ID uint `json:"draft_id"` // 所操作的草稿id
+}
+
+type DraftListResp struct {
+	Status
+	Draft_List []Draft `json:"draft_list"` // 用户草稿列表
+}
+
+// 草稿信息
+type Draft struct {
+	Video
+	Is_Hidden bool `json:"is_hidden"` // true-送审中或因举报被隐藏，待审核员处理，false-未被隐藏
+}
+
+type DraftPublishResp struct {
+	Status
+}
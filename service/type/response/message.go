@@ -8,3 +8,37 @@ type MessageListResp struct {
 	Status
 	Message_List []Message `json:"message_list"` // 消息列表
 }
+
+type ConversationListResp struct {
+	Status
+	Conversation_List []Conversation `json:"conversation_list"` // 会话列表 按最近活跃时间倒序
+	Next_Offset       int            `json:"next_offset"`       // 下一页的分页偏移量
+	Has_More          bool           `json:"has_more"`          // true-还有更多，false-已无更多
+}
+
+type ConversationReadResp struct {
+	Status
+}
+
+type UnreadCountResp struct {
+	Status
+	Unread_Count int64 `json:"unread_count"` // 全部会话的未读消息总数
+}
+
+type MessageAckResp struct {
+	Status
+}
+
+type MessageDeleteResp struct {
+	Status
+}
+
+// 经WebSocket推送的聊天事件
+type ChatEvent struct {
+	Type         string   `json:"type"`                 // message-新消息，typing-正在输入，delivered-已送达，read-已读，recall-撤回消息，delete-仅为自己删除消息
+	From_User_ID uint     `json:"from_user_id"`         // 事件发起用户id
+	To_User_ID   uint     `json:"to_user_id"`           // 事件目标用户id
+	Message      *Message `json:"message,omitempty"`    // 新消息内容(仅用于新消息事件)
+	Message_ID   uint     `json:"message_id,omitempty"` // 已送达/已读的最新消息id或被撤回/删除的消息id(仅用于已送达、已读、撤回及删除事件)
+	Create_Time  int64    `json:"create_time"`          // 事件发生时间(毫秒时间戳)
+}
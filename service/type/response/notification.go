@@ -0,0 +1,13 @@
+package response
+
+type NotificationListResp struct {
+	Status
+	Notification_List []Notification `json:"notification_list"` // 通知列表
+	Unread_Count      int64          `json:"unread_count"`      // 未读通知总数
+	Next_Offset       int            `json:"next_offset"`       // 下一页的分页偏移量
+	Has_More          bool           `json:"has_more"`          // true-还有更多，false-已无更多
+}
+
+type NotificationReadResp struct {
+	Status
+}
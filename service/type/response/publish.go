@@ -8,3 +8,7 @@ type PublishListResp struct {
 	Status
 	Video_List []Video `json:"video_list"` // 用户发布的视频列表
 }
+
+type PublishDeleteResp struct {
+	Status
+}
@@ -2,6 +2,11 @@ package response
 
 type FollowResp struct {
 	Status
+	Is_Pending bool `json:"is_pending"` // true-对方为私密账号，已发送关注申请待对方同意
+}
+
+type RemoveFollowerResp struct {
+	Status
 }
 
 type FollowListResp struct {
@@ -18,3 +23,28 @@ type FriendListResp struct {
 	Status
 	User_List []FriendUser `json:"user_list"` // 用户(好友)信息列表 API文档有误 应为此结构
 }
+
+type BlockResp struct {
+	Status
+}
+
+type BlockListResp struct {
+	Status
+	User_List []User `json:"user_list"` // 已拉黑的用户信息列表
+}
+
+type FollowRequestListResp struct {
+	Status
+	Request_List []FollowRequest `json:"request_list"` // 待处理的关注申请列表
+	Next_Offset  int             `json:"next_offset"`  // 下一页的分页偏移量
+	Has_More     bool            `json:"has_more"`     // true-还有更多，false-已无更多
+}
+
+type FollowRequestActionResp struct {
+	Status
+}
+
+type RecommendListResp struct {
+	Status
+	User_List []User `json:"user_list"` // 推荐用户信息列表 按推荐程度从高到低
+}
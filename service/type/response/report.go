@@ -0,0 +1,17 @@
+package response
+
+type ReportResp struct {
+	Status
+	Report_ID uint `json:"report_id"` // 所创建的举报id
+}
+
+type ModerationListResp struct {
+	Status
+	Report_List []Report `json:"report_list"` // 举报列表
+	Next_Offset int      `json:"next_offset"` // 下一页的分页偏移量
+	Has_More    bool     `json:"has_more"`    // true-还有更多，false-已无更多
+}
+
+type ModerationResp struct {
+	Status
+}
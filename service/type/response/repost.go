@@ -0,0 +1,20 @@
+package response
+
+type RepostResp struct {
+	Status
+}
+
+type RepostListResp struct {
+	Status
+	Post_List []Post `json:"post_list"` // 用户转发列表
+	Next_Time int64  `json:"next_time"` // 本次返回的转发中最早的时间，作为下次请求时的latest_time，毫秒时间戳
+	Next_ID   uint   `json:"next_id"`   // 本次返回的转发中最早的转发id，作为下次请求时的latest_id
+}
+
+type FollowingFeedResp struct {
+	Status
+	Post_List      []Post `json:"post_list"`      // 关注用户的作品及转发列表
+	Next_Time      int64  `json:"next_time"`      // 本次返回的动态中最早的时间，作为下次请求时的latest_time，毫秒时间戳
+	Next_Video_ID  uint   `json:"next_video_id"`  // 作为下次请求时的latest_video_id，为0表示该时刻的作品尚未返回
+	Next_Repost_ID uint   `json:"next_repost_id"` // 作为下次请求时的latest_repost_id，为0表示该时刻的转发尚未返回
+}
@@ -0,0 +1,15 @@
+package response
+
+type ShareLinkResp struct {
+	Status
+	Code        string `json:"code"`        // 短链接码
+	Click_Count int64  `json:"click_count"` // 短链接点击量
+}
+
+type ShareResolveResp struct {
+	Status
+	Target_Type  int    `json:"target_type"`  // 1-视频，2-用户
+	Target_ID    uint   `json:"target_id"`    // 视频id或用户id
+	Click_Count  int64  `json:"click_count"`  // 短链接点击量(含本次)
+	Redirect_URL string `json:"redirect_url"` // 目标地址，视频为播放地址，用户为发布列表地址
+}
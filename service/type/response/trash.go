@@ -0,0 +1,14 @@
+package response
+
+type TrashListResp struct {
+	Status
+	Trash_List  []TrashItem `json:"trash_list"`  // 回收站条目列表
+	Next_Offset int         `json:"next_offset"` // 下一页的分页偏移量
+	Has_More    bool        `json:"has_more"`    // true-还有更多，false-已无更多
+}
+
+type TrashRestoreResp struct {
+	Status
+	Target_Type int  `json:"target_type"` // 被恢复的对象类型，1-视频(含草稿)，2-评论
+	Target_ID   uint `json:"target_id"`   // 被恢复的对象id
+}
@@ -16,3 +16,11 @@ type UserInfoResp struct {
 	Status
 	User User `json:"user"` // 用户信息
 }
+
+type UserSignatureResp struct {
+	Status
+}
+
+type UserPrivacyResp struct {
+	Status
+}
@@ -17,10 +17,32 @@ import (
 // 自定义错误类型
 var ErrorUserExists = errors.New("用户已存在")
 var ErrorWrongPassword = errors.New("账号或密码错误")
+var ErrorUserPrivate = errors.New("该用户为私密账号, 仅对已关注者可见")
+var ErrorUserNotExists = errors.New("用户不存在")
 
 // 默认个人签名
 const signature = "Ad Astra Per Aspera"
 
+// 检查请求用户能否查看目标用户的作品、点赞、关注、合集及转发列表 私密账号仅对本人及已关注者可见
+func checkUserVisible(ctx *gin.Context, userID uint) (err error) {
+	user, err := repo.ReadUserBasics(context.TODO(), userID)
+	if err == repo.ErrorEmptyObject || err == repo.ErrorRecordNotExists {
+		return ErrorUserNotExists
+	}
+	if err != nil {
+		utility.Logger().Errorf("ReadUserBasics err: %v", err)
+		return err
+	}
+	if !user.IsPrivate {
+		return nil
+	}
+	req_id, _ := ctx.Get("req_id") // 允许无法获取 获取请求用户ID不成功时req_id为nil
+	if req_id != nil && (req_id.(uint) == userID || repo.CheckUserFollows(context.TODO(), req_id.(uint), userID)) {
+		return nil
+	}
+	return ErrorUserPrivate
+}
+
 // 用户注册
 func UserRegister(ctx *gin.Context, req *request.UserRegisterReq) (resp *response.UserRegisterResp, err error) {
 	// 校验用户名是否可注册
@@ -84,3 +106,67 @@ func UserInfo(ctx *gin.Context, req *request.UserInfoReq) (resp *response.UserIn
 
 	return &response.UserInfoResp{User: *userInfo}, nil
 }
+
+// 编辑个人签名
+func UserSignature(ctx *gin.Context, req *request.UserSignatureReq) (resp *response.UserSignatureResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 过滤签名敏感词
+	signature, needReview, err := filterText(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	// 存储个人签名(送审的签名在审核前保持隐藏 未送审的签名解除隐藏)
+	err = repo.UpdateUserSignature(context.TODO(), req_id.(uint), signature, needReview)
+	if err != nil {
+		utility.Logger().Errorf("UpdateUserSignature err: %v", err)
+		return nil, err
+	}
+	if needReview { // 签名包含送审类敏感词时送审
+		sendToReview(reportTargetSignature, req_id.(uint))
+	}
+
+	return &response.UserSignatureResp{}, nil
+}
+
+// 设置账号是否私密
+func UserPrivacy(ctx *gin.Context, req *request.UserPrivacyReq) (resp *response.UserPrivacyResp, err error) {
+	// 获取请求用户ID
+	req_id, ok := ctx.Get("req_id")
+	if !ok {
+		utility.Logger().Errorf("ctx.Get (req_id) err: 无法获取")
+		return nil, errors.New("无法获取请求用户ID")
+	}
+
+	// 设为私密/公开账号
+	if req.Action_Type == 1 {
+		err = repo.UpdateUserPrivacy(context.TODO(), req_id.(uint), true)
+		if err != nil {
+			utility.Logger().Errorf("UpdateUserPrivacy err: %v", err)
+			return nil, err
+		}
+	} else if req.Action_Type == 2 {
+		err = repo.UpdateUserPrivacy(context.TODO(), req_id.(uint), false)
+		if err != nil {
+			utility.Logger().Errorf("UpdateUserPrivacy err: %v", err)
+			return nil, err
+		}
+
+		// 公开后待处理的关注申请已无必要 全部同意
+		_, err = repo.AcceptAllFollowRequests(context.TODO(), req_id.(uint))
+		if err != nil {
+			utility.Logger().Errorf("AcceptAllFollowRequests err: %v", err) // 响应为设置成功 仅记录错误
+		}
+	} else {
+		utility.Logger().Errorf("Invalid action_type err: %v", req.Action_Type)
+		return nil, errors.New("操作类型有误")
+	}
+
+	return &response.UserPrivacyResp{}, nil
+}
@@ -0,0 +1,46 @@
+package utility
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// 自定义错误类型
+var ErrorInvalidBase62 = errors.New("非法的六十二进制字符串")
+
+// 将非负整数编码为六十二进制字符串
+func EncodeBase62(n uint64) string {
+	if n == 0 {
+		return base62Alphabet[:1]
+	}
+	buf := make([]byte, 0, 11) // uint64最多11位
+	for n > 0 {
+		buf = append(buf, base62Alphabet[n%62])
+		n /= 62
+	}
+	for i, j := 0, len(buf)-1; i < j; i, j = i+1, j-1 { // 反转为高位在前
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf)
+}
+
+// 将六十二进制字符串解码为非负整数
+func DecodeBase62(s string) (n uint64, err error) {
+	if s == "" || len(s) > 11 {
+		return 0, ErrorInvalidBase62
+	}
+	for i := 0; i < len(s); i++ {
+		digit := strings.IndexByte(base62Alphabet, s[i])
+		if digit == -1 {
+			return 0, ErrorInvalidBase62
+		}
+		if n > (math.MaxUint64-uint64(digit))/62 { // 溢出
+			return 0, ErrorInvalidBase62
+		}
+		n = n*62 + uint64(digit)
+	}
+	return n, nil
+}
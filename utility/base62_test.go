@@ -0,0 +1,24 @@
+package utility
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBase62RoundTrip(t *testing.T) {
+	for _, n := range []uint64{0, 1, 61, 62, 3843, 3844, 1 << 32, math.MaxUint64} {
+		s := EncodeBase62(n)
+		got, err := DecodeBase62(s)
+		if err != nil || got != n {
+			t.Errorf("DecodeBase62(EncodeBase62(%d)=%q) = %d, %v", n, s, got, err)
+		}
+	}
+}
+
+func TestDecodeBase62Invalid(t *testing.T) {
+	for _, s := range []string{"", "abc-", "!", "zzzzzzzzzzzz", "LygHa16AHYG"} { // 空串、非法字符、超长及溢出
+		if _, err := DecodeBase62(s); err != ErrorInvalidBase62 {
+			t.Errorf("DecodeBase62(%q) err = %v, want ErrorInvalidBase62", s, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package utility
+
+import (
+	"douyin/conf"
+
+	"bufio"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// 敏感词处理方式 数值越大越严格
+const FilterActionPass = 0   // 放行
+const FilterActionMask = 1   // 替换为*
+const FilterActionReview = 2 // 送审(内容隐藏直至审核员处理)
+const FilterActionReject = 3 // 拒绝提交
+
+var filterActions = map[string]int{"mask": FilterActionMask, "review": FilterActionReview, "reject": FilterActionReject}
+
+// AC自动机节点
+type acNode struct {
+	children map[rune]*acNode
+	fail     *acNode
+	output   *acNode // 沿失配链最近的词尾节点
+	depth    int     // 节点深度 即以此节点结尾的词长
+	action   int     // 以此节点结尾的词的处理方式 为0时非词尾
+}
+
+// AC自动机(多模式匹配)
+type acMatcher struct {
+	root *acNode
+}
+
+var _filter atomic.Pointer[acMatcher] // 热重载时整体替换 无需加锁
+
+// 构建AC自动机
+func newACMatcher(words map[string]int) *acMatcher {
+	root := &acNode{children: make(map[rune]*acNode)}
+
+	// 构建字典树
+	for word, action := range words {
+		node := root
+		for _, r := range word {
+			r = unicode.ToLower(r)
+			child, ok := node.children[r]
+			if !ok {
+				child = &acNode{children: make(map[rune]*acNode), depth: node.depth + 1}
+				node.children[r] = child
+			}
+			node = child
+		}
+		if action > node.action { // 重复的词取最严格的处理方式
+			node.action = action
+		}
+	}
+
+	// 广度优先构建失配指针
+	queue := make([]*acNode, 0, len(root.children))
+	for _, child := range root.children {
+		child.fail = root
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for r, child := range node.children {
+			fail := node.fail
+			for fail != nil && fail.children[r] == nil {
+				fail = fail.fail
+			}
+			if fail == nil {
+				child.fail = root
+			} else {
+				child.fail = fail.children[r]
+			}
+			if child.fail.action > 0 {
+				child.output = child.fail
+			} else {
+				child.output = child.fail.output
+			}
+			queue = append(queue, child)
+		}
+	}
+
+	return &acMatcher{root: root}
+}
+
+// 匹配文本 返回替换后的文本及最严格的处理方式
+func (m *acMatcher) filter(text string) (filtered string, action int) {
+	runes := []rune(text)
+	masked := make([]bool, len(runes))
+	node := m.root
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		for node != m.root && node.children[r] == nil {
+			node = node.fail
+		}
+		if next, ok := node.children[r]; ok {
+			node = next
+		}
+
+		// 检查所有以当前字符结尾的词
+		for hit := node; hit != nil; hit = hit.output {
+			if hit.action == 0 {
+				continue
+			}
+			if hit.action > action {
+				action = hit.action
+			}
+			if hit.action == FilterActionMask {
+				for j := i - hit.depth + 1; j <= i; j++ {
+					masked[j] = true
+				}
+			}
+		}
+	}
+
+	if action == FilterActionPass {
+		return text, action
+	}
+	for i := range runes {
+		if masked[i] {
+			runes[i] = '*'
+		}
+	}
+	return string(runes), action
+}
+
+// 读取敏感词表
+func loadWordList(path string) (words map[string]int, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	words = make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") { // 跳过空行与注释
+			continue
+		}
+		word, actionName, _ := strings.Cut(line, ",")
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		action, ok := filterActions[strings.ToLower(strings.TrimSpace(actionName))]
+		if !ok {
+			action = FilterActionMask // 默认替换为*
+		}
+		words[word] = action
+	}
+	return words, scanner.Err()
+}
+
+// 初始化敏感词过滤器 并按配置周期检查词表变更以热重载
+func InitFilter() {
+	filterCfg := conf.Cfg().Filter
+	if strings.ToLower(filterCfg.WordList) == "none" { // 不过滤
+		return
+	}
+
+	var modTime time.Time
+	reload := func() {
+		info, err := os.Stat(filterCfg.WordList)
+		if err != nil {
+			Logger().Errorf("os.Stat err: %v", err) // 保留原词表
+			return
+		}
+		if info.ModTime().Equal(modTime) { // 词表未变更
+			return
+		}
+		words, err := loadWordList(filterCfg.WordList)
+		if err != nil {
+			Logger().Errorf("loadWordList err: %v", err) // 保留原词表
+			return
+		}
+		_filter.Store(newACMatcher(words))
+		modTime = info.ModTime()
+		Logger().Infof("InitFilter info: 已加载%v个敏感词", len(words))
+	}
+	reload()
+
+	if filterCfg.ReloadInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Second * time.Duration(filterCfg.ReloadInterval))
+			for range ticker.C {
+				reload()
+			}
+		}()
+	}
+}
+
+// 过滤敏感词 返回替换后的文本及最严格的处理方式 未加载词表时原样放行
+func FilterText(text string) (filtered string, action int) {
+	matcher := _filter.Load()
+	if matcher == nil {
+		return text, FilterActionPass
+	}
+	return matcher.filter(text)
+}
@@ -0,0 +1,38 @@
+package utility
+
+import (
+	"unicode"
+)
+
+const maxMentionLength = 32 // 用户名最大长度 与注册时的限制一致
+
+// 文本中的@提及
+type MentionSpan struct {
+	Username string // 被提及的用户名
+	Offset   int    // @符号在文本中的位置(以字符计)
+	Length   int    // 含@符号在内的长度(以字符计)
+}
+
+// 解析文本中的@提及 用户名为@之后直至空白字符或下一个@之前的内容(末尾的标点不视为用户名的一部分)
+func ParseMentions(text string) (spans []MentionSpan) {
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && end-i-1 < maxMentionLength && runes[end] != '@' && !unicode.IsSpace(runes[end]) {
+			end++
+		}
+		next := end // 下一次查找的起点
+		for end > i+1 && unicode.IsPunct(runes[end-1]) && runes[end-1] != '_' {
+			end--
+		}
+		if end > i+1 {
+			spans = append(spans, MentionSpan{Username: string(runes[i+1 : end]), Offset: i, Length: end - i})
+		}
+		i = next - 1
+	}
+	return spans
+}
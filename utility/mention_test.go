@@ -0,0 +1,37 @@
+package utility
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []MentionSpan
+	}{
+		{"", nil},
+		{"没有提及", nil},
+		{"@alice", []MentionSpan{{"alice", 0, 6}}},
+		{"hi @alice, @bob!", []MentionSpan{{"alice", 3, 6}, {"bob", 11, 4}}},
+		{"@alice@bob", []MentionSpan{{"alice", 0, 6}, {"bob", 6, 4}}},
+		{"你好@小明 再见", []MentionSpan{{"小明", 2, 3}}}, // 位置以字符计
+		{"@ @", nil},
+		{"@a_b_", []MentionSpan{{"a_b_", 0, 5}}}, // 下划线不视为末尾标点
+		{"@...", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseMentions(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseMentionsMaxLength(t *testing.T) {
+	name := strings.Repeat("a", maxMentionLength+5)
+	spans := ParseMentions("@" + name)
+	if len(spans) == 0 || len(spans[0].Username) != maxMentionLength {
+		t.Fatalf("ParseMentions(long) = %+v, want first username truncated to %d", spans, maxMentionLength)
+	}
+}